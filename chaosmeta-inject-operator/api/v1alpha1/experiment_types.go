@@ -26,6 +26,15 @@ const (
 	FinalizerName  = "chaosmeta/experiment"
 	ContainerKey   = "containername"
 	FirstContainer = "firstcontainer"
+
+	// ReadinessGateType is the readiness gate controlled by the operator, the pods declaring it in
+	// spec.readinessGates can be made NotReady by the readiness fault
+	ReadinessGateType = "chaosmeta.io/ready"
+	// ReadinessGateLabel must be set to "true" on the pods declaring ReadinessGateType, the operator only watches
+	// the pods with this label to keep their gate True
+	ReadinessGateLabel = "chaosmeta.io/readiness-gate"
+	// ReadinessFaultAnnotation marks the pod under the readiness fault, the value is the uid of sub experiment
+	ReadinessFaultAnnotation = "chaosmeta.io/readiness-fault"
 )
 
 // EDIT THIS FILE!  THIS IS SCAFFOLDING FOR YOU TO OWN!
@@ -50,6 +59,8 @@ type ExperimentSpec struct {
 	Experiment *ExperimentCommon `json:"experiment"`
 	// Selector The internal part of unit is "AND", and the external part is "OR" and de-duplication
 	Selector []SelectorUnit `json:"selector,omitempty"`
+	// Rollout Optional. inject the targets wave by wave instead of all at once
+	Rollout *RolloutStrategy `json:"rollout,omitempty"`
 
 	TargetPhase PhaseType `json:"targetPhase"`
 	//SubObj      bool      `json:"subObj"`
@@ -83,6 +94,10 @@ type ExperimentStatus struct {
 	Detail     ExperimentDetail `json:"detail"`
 	CreateTime string           `json:"createTime"`
 	UpdateTime string           `json:"updateTime"`
+	// Range the seed and the targets selected by RangeMode
+	Range *RangeStatus `json:"range,omitempty"`
+	// Rollout the progress of the waves of Rollout
+	Rollout *RolloutStatus `json:"rollout,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -116,12 +131,66 @@ const (
 	AllRangeType     RangeType = "all"
 	PercentRangeType RangeType = "percent"
 	CountRangeType   RangeType = "count"
+	SpreadRangeType  RangeType = "spread"
+)
+
+type TopologyType string
+
+const (
+	ZoneTopology     TopologyType = "zone"
+	NodeTopology     TopologyType = "node"
+	WorkloadTopology TopologyType = "workload"
 )
 
 type RangeMode struct {
-	// Type Optional: all、percent、count
+	// Type Optional: all、percent、count、spread
 	Type  RangeType `json:"type"`
 	Value int       `json:"value,omitempty"`
+	// Seed Optional. the same seed and targets get the same result, a random seed is used and recorded in status if not provided
+	Seed *int64 `json:"seed,omitempty"`
+	// SpreadBy Only for spread, Optional: zone、node、workload. the range is applied in every topology domain
+	SpreadBy TopologyType `json:"spreadBy,omitempty"`
+	// SpreadType Only for spread, Optional: count、percent. the type of "value" in every topology domain
+	SpreadType RangeType `json:"spreadType,omitempty"`
+	// MaxPerWorkload Optional. the max count of selected pods owned by the same workload, 0 means no limit
+	MaxPerWorkload int `json:"maxPerWorkload,omitempty"`
+}
+
+// RangeStatus the result of RangeMode
+type RangeStatus struct {
+	Seed    int64    `json:"seed"`
+	Targets []string `json:"targets,omitempty"`
+}
+
+type RolloutStrategy struct {
+	// BatchType Optional: count、percent. the type of "batchSize"
+	BatchType RangeType `json:"batchType"`
+	// BatchSize the count or the percent of targets injected in every wave
+	BatchSize int `json:"batchSize"`
+	// Interval support "h", "m", "s". the min interval between the start of two waves
+	Interval string `json:"interval,omitempty"`
+	// Pause Optional. the condition to stop starting further waves
+	Pause *PauseCondition `json:"pause,omitempty"`
+}
+
+type PauseCondition struct {
+	// OnFailure stop further waves if any sub experiment failed
+	OnFailure bool `json:"onFailure,omitempty"`
+	// Measure the name of a CommonMeasure in the same namespace, stop further waves if it failed or can not be got
+	Measure string `json:"measure,omitempty"`
+}
+
+// RolloutStatus the progress of RolloutStrategy
+type RolloutStatus struct {
+	// Wave the latest started wave, starting from 1
+	Wave      int `json:"wave"`
+	TotalWave int `json:"totalWave"`
+	// WaveTime the start time of the latest started wave
+	WaveTime string `json:"waveTime,omitempty"`
+	// Paused the reason of stopping further waves
+	Paused string `json:"paused,omitempty"`
+	// MeasureErrors the count of continuous errors of getting the measure of pause condition
+	MeasureErrors int `json:"measureErrors,omitempty"`
 }
 
 type SelectorUnit struct {
@@ -129,9 +198,25 @@ type SelectorUnit struct {
 	Name      []string          `json:"name,omitempty"`
 	IP        []string          `json:"ip,omitempty"`
 	Label     map[string]string `json:"label,omitempty"`
+	// LabelExpressions Optional operator: In、NotIn、Exists、DoesNotExist, "AND" with Label
+	LabelExpressions []metav1.LabelSelectorRequirement `json:"labelExpressions,omitempty"`
+	// Annotation the annotations must be all equal
+	Annotation map[string]string `json:"annotation,omitempty"`
+	// Field field selector like "spec.nodeName=node1,status.phase!=Running", see SupportedSelectorFields
+	Field string `json:"field,omitempty"`
+	// Owner Only for pod. select the pods controlled by the workload, a pod of deployment is owned by the deployment
+	Owner *OwnerSelector `json:"owner,omitempty"`
+	// NodeLabel Only for pod. select the pods running on the nodes with the labels
+	NodeLabel map[string]string `json:"nodeLabel,omitempty"`
 	SubName   string            `json:"subName,omitempty"`
 }
 
+type OwnerSelector struct {
+	// Kind Optional: Deployment、ReplicaSet、StatefulSet、DaemonSet、Job
+	Kind string   `json:"kind"`
+	Name []string `json:"name"`
+}
+
 //type TargetType string
 //type FaultType string
 
@@ -170,6 +255,8 @@ type ExperimentDetailUnit struct {
 	StartTime  string     `json:"startTime,omitempty"`
 	UpdateTime string     `json:"updateTime,omitempty"`
 	Backup     string     `json:"backup,omitempty"`
+	// Wave the wave of rollout the sub experiment belongs to, 0 means no rollout
+	Wave int `json:"wave,omitempty"`
 }
 
 type CloudTargetType string
@@ -183,4 +270,7 @@ const (
 	DaemonsetCloudTarget   CloudTargetType = "daemonset"
 	NamespaceCloudTarget   CloudTargetType = "namespace"
 	JobCloudTarget         CloudTargetType = "job"
+	ServiceCloudTarget     CloudTargetType = "service"
+	ConfigMapCloudTarget   CloudTargetType = "configmap"
+	SecretCloudTarget      CloudTargetType = "secret"
 )
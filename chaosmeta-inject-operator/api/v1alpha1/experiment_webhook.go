@@ -24,6 +24,7 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -96,20 +97,14 @@ func (r *Experiment) ValidateCreate() error {
 	}
 
 	if r.Spec.RangeMode != nil {
-		if r.Spec.RangeMode.Type != AllRangeType && r.Spec.RangeMode.Type != PercentRangeType && r.Spec.RangeMode.Type != CountRangeType {
-			return fmt.Errorf("\"rangeMode.type\" not support: %s, only support: %s, %s, %s", r.Spec.RangeMode.Type, AllRangeType, PercentRangeType, CountRangeType)
-		}
-
-		if r.Spec.RangeMode.Type == PercentRangeType {
-			if r.Spec.RangeMode.Value <= 0 || r.Spec.RangeMode.Value > 100 {
-				return fmt.Errorf("\"rangeMode.value\" should be in (0,100]")
-			}
+		if err := validateRangeMode(GetSelectorTarget(&r.Spec), r.Spec.RangeMode); err != nil {
+			return err
 		}
+	}
 
-		if r.Spec.RangeMode.Type == CountRangeType {
-			if r.Spec.RangeMode.Value <= 0 {
-				return fmt.Errorf("\"rangeMode.value\" should larger than 0")
-			}
+	if r.Spec.Rollout != nil {
+		if err := validateRollout(r.Spec.Rollout); err != nil {
+			return err
 		}
 	}
 
@@ -144,12 +139,91 @@ func (r *Experiment) ValidateCreate() error {
 				return fmt.Errorf("must provide one type of \"name\"、\"label\"、\"ip\" selector in one selector unit")
 			}
 
-			if emptyCount != 2 {
+			// the extended selectors can be used alone
+			if emptyCount != 2 && !(emptyCount == 3 && unitSelector.IsExtended()) {
 				return fmt.Errorf("can only provide one type of \"name\"、\"label\"、\"ip\" selector in one selector unit")
 			}
 		}
 	}
 
+	target := GetSelectorTarget(&r.Spec)
+	for i := range r.Spec.Selector {
+		if err := validateSelectorUnit(target, &r.Spec.Selector[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateRangeMode(target CloudTargetType, rangeMode *RangeMode) error {
+	if rangeMode.Type != AllRangeType && rangeMode.Type != PercentRangeType && rangeMode.Type != CountRangeType && rangeMode.Type != SpreadRangeType {
+		return fmt.Errorf("\"rangeMode.type\" not support: %s, only support: %s, %s, %s, %s", rangeMode.Type, AllRangeType, PercentRangeType, CountRangeType, SpreadRangeType)
+	}
+
+	valueType := rangeMode.Type
+	if rangeMode.Type == SpreadRangeType {
+		if rangeMode.SpreadBy != ZoneTopology && rangeMode.SpreadBy != NodeTopology && rangeMode.SpreadBy != WorkloadTopology {
+			return fmt.Errorf("\"rangeMode.spreadBy\" not support: %s, only support: %s, %s, %s", rangeMode.SpreadBy, ZoneTopology, NodeTopology, WorkloadTopology)
+		}
+
+		if rangeMode.SpreadType != PercentRangeType && rangeMode.SpreadType != CountRangeType {
+			return fmt.Errorf("\"rangeMode.spreadType\" not support: %s, only support: %s, %s", rangeMode.SpreadType, PercentRangeType, CountRangeType)
+		}
+
+		if target != PodCloudTarget && (rangeMode.SpreadBy == WorkloadTopology || target != NodeCloudTarget) {
+			return fmt.Errorf("\"rangeMode.spreadBy\" %s not support target: %s", rangeMode.SpreadBy, target)
+		}
+
+		valueType = rangeMode.SpreadType
+	}
+
+	if valueType == PercentRangeType {
+		if rangeMode.Value <= 0 || rangeMode.Value > 100 {
+			return fmt.Errorf("\"rangeMode.value\" should be in (0,100]")
+		}
+	}
+
+	if valueType == CountRangeType {
+		if rangeMode.Value <= 0 {
+			return fmt.Errorf("\"rangeMode.value\" should larger than 0")
+		}
+	}
+
+	if rangeMode.MaxPerWorkload < 0 {
+		return fmt.Errorf("\"rangeMode.maxPerWorkload\" should not less than 0")
+	}
+
+	if rangeMode.MaxPerWorkload > 0 && target != PodCloudTarget {
+		return fmt.Errorf("\"rangeMode.maxPerWorkload\" only support target: %s", PodCloudTarget)
+	}
+
+	return nil
+}
+
+func validateRollout(rollout *RolloutStrategy) error {
+	if rollout.BatchType != PercentRangeType && rollout.BatchType != CountRangeType {
+		return fmt.Errorf("\"rollout.batchType\" not support: %s, only support: %s, %s", rollout.BatchType, PercentRangeType, CountRangeType)
+	}
+
+	if rollout.BatchType == PercentRangeType && (rollout.BatchSize <= 0 || rollout.BatchSize > 100) {
+		return fmt.Errorf("\"rollout.batchSize\" should be in (0,100]")
+	}
+
+	if rollout.BatchType == CountRangeType && rollout.BatchSize <= 0 {
+		return fmt.Errorf("\"rollout.batchSize\" should larger than 0")
+	}
+
+	if rollout.Interval != "" {
+		if _, err := ConvertDuration(rollout.Interval); err != nil {
+			return fmt.Errorf("\"rollout.interval\" is invalid: %s", err.Error())
+		}
+	}
+
+	if rollout.Pause != nil && !rollout.Pause.OnFailure && strings.TrimSpace(rollout.Pause.Measure) == "" {
+		return fmt.Errorf("\"rollout.pause.measure\" must not be empty if \"rollout.pause.onFailure\" is false")
+	}
+
 	return nil
 }
 
@@ -164,6 +238,7 @@ func (r *Experiment) ValidateUpdate(old runtime.Object) error {
 	if !reflect.DeepEqual(r.Spec.Experiment, oldExp.Spec.Experiment) ||
 		!reflect.DeepEqual(r.Spec.Selector, oldExp.Spec.Selector) ||
 		!reflect.DeepEqual(r.Spec.RangeMode, oldExp.Spec.RangeMode) ||
+		!reflect.DeepEqual(r.Spec.Rollout, oldExp.Spec.Rollout) ||
 		r.Spec.Scope != oldExp.Spec.Scope {
 		return fmt.Errorf("spec only support update \"targetPhase\"")
 	}
@@ -83,3 +83,57 @@ func TestConvertDuration(t *testing.T) {
 		})
 	}
 }
+
+func Test_validateRangeMode(t *testing.T) {
+	tests := []struct {
+		name      string
+		target    CloudTargetType
+		rangeMode RangeMode
+		wantErr   bool
+	}{
+		{name: "count", target: DeploymentCloudTarget, rangeMode: RangeMode{Type: CountRangeType, Value: 1}},
+		{name: "count zero", target: PodCloudTarget, rangeMode: RangeMode{Type: CountRangeType}, wantErr: true},
+		{name: "unknown type", target: PodCloudTarget, rangeMode: RangeMode{Type: "half"}, wantErr: true},
+		{name: "spread by zone of pod", target: PodCloudTarget, rangeMode: RangeMode{Type: SpreadRangeType, SpreadBy: ZoneTopology, SpreadType: PercentRangeType, Value: 50}},
+		{name: "spread by node of node", target: NodeCloudTarget, rangeMode: RangeMode{Type: SpreadRangeType, SpreadBy: NodeTopology, SpreadType: CountRangeType, Value: 1}},
+		{name: "spread by workload of node", target: NodeCloudTarget, rangeMode: RangeMode{Type: SpreadRangeType, SpreadBy: WorkloadTopology, SpreadType: CountRangeType, Value: 1}, wantErr: true},
+		{name: "spread of deployment", target: DeploymentCloudTarget, rangeMode: RangeMode{Type: SpreadRangeType, SpreadBy: ZoneTopology, SpreadType: CountRangeType, Value: 1}, wantErr: true},
+		{name: "spread without spreadBy", target: PodCloudTarget, rangeMode: RangeMode{Type: SpreadRangeType, SpreadType: CountRangeType, Value: 1}, wantErr: true},
+		{name: "spread percent out of range", target: PodCloudTarget, rangeMode: RangeMode{Type: SpreadRangeType, SpreadBy: ZoneTopology, SpreadType: PercentRangeType, Value: 120}, wantErr: true},
+		{name: "max per workload", target: PodCloudTarget, rangeMode: RangeMode{Type: AllRangeType, MaxPerWorkload: 1}},
+		{name: "max per workload of node", target: NodeCloudTarget, rangeMode: RangeMode{Type: AllRangeType, MaxPerWorkload: 1}, wantErr: true},
+		{name: "negative max per workload", target: PodCloudTarget, rangeMode: RangeMode{Type: AllRangeType, MaxPerWorkload: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateRangeMode(tt.target, &tt.rangeMode); (err != nil) != tt.wantErr {
+				t.Errorf("validateRangeMode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_validateRollout(t *testing.T) {
+	tests := []struct {
+		name    string
+		rollout RolloutStrategy
+		wantErr bool
+	}{
+		{name: "count", rollout: RolloutStrategy{BatchType: CountRangeType, BatchSize: 10, Interval: "1m"}},
+		{name: "percent", rollout: RolloutStrategy{BatchType: PercentRangeType, BatchSize: 20, Pause: &PauseCondition{OnFailure: true, Measure: "m1"}}},
+		{name: "all", rollout: RolloutStrategy{BatchType: AllRangeType, BatchSize: 1}, wantErr: true},
+		{name: "count zero", rollout: RolloutStrategy{BatchType: CountRangeType}, wantErr: true},
+		{name: "percent out of range", rollout: RolloutStrategy{BatchType: PercentRangeType, BatchSize: 101}, wantErr: true},
+		{name: "invalid interval", rollout: RolloutStrategy{BatchType: CountRangeType, BatchSize: 1, Interval: "1d"}, wantErr: true},
+		{name: "pause on failure", rollout: RolloutStrategy{BatchType: CountRangeType, BatchSize: 1, Pause: &PauseCondition{OnFailure: true}}},
+		{name: "pause without measure", rollout: RolloutStrategy{BatchType: CountRangeType, BatchSize: 1, Pause: &PauseCondition{}}, wantErr: true},
+		{name: "pause with blank measure", rollout: RolloutStrategy{BatchType: CountRangeType, BatchSize: 1, Pause: &PauseCondition{Measure: " "}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateRollout(&tt.rollout); (err != nil) != tt.wantErr {
+				t.Errorf("validateRollout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,150 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+const (
+	FieldName          = "metadata.name"
+	FieldNamespace     = "metadata.namespace"
+	FieldNodeName      = "spec.nodeName"
+	FieldPhase         = "status.phase"
+	FieldPodIP         = "status.podIP"
+	FieldHostIP        = "status.hostIP"
+	FieldUnschedulable = "spec.unschedulable"
+)
+
+// SupportedSelectorFields the targets supporting labelExpressions, annotation and field, and the supported fields
+var SupportedSelectorFields = map[CloudTargetType][]string{
+	PodCloudTarget:        {FieldName, FieldNamespace, FieldNodeName, FieldPhase, FieldPodIP, FieldHostIP},
+	NodeCloudTarget:       {FieldName, FieldUnschedulable},
+	DeploymentCloudTarget: {FieldName, FieldNamespace},
+}
+
+// SupportedOwnerKinds the kinds of workload supported by the owner selector of pod
+var SupportedOwnerKinds = []string{"Deployment", "ReplicaSet", "StatefulSet", "DaemonSet", "Job"}
+
+// IsExtended return true if any selector except namespace, name, ip and label is used
+func (s *SelectorUnit) IsExtended() bool {
+	return len(s.LabelExpressions) != 0 || len(s.Annotation) != 0 || s.Field != "" || s.Owner != nil || len(s.NodeLabel) != 0
+}
+
+// GetLabelSelector combine Label and LabelExpressions, return labels.Everything if both are empty
+func (s *SelectorUnit) GetLabelSelector() (labels.Selector, error) {
+	return metav1.LabelSelectorAsSelector(&metav1.LabelSelector{
+		MatchLabels:      s.Label,
+		MatchExpressions: s.LabelExpressions,
+	})
+}
+
+// GetFieldSelector return fields.Everything if Field is empty
+func (s *SelectorUnit) GetFieldSelector() (fields.Selector, error) {
+	return fields.ParseSelector(s.Field)
+}
+
+// GetSelectorTarget return the target type of the objects selected by selector
+func GetSelectorTarget(spec *ExperimentSpec) CloudTargetType {
+	switch spec.Scope {
+	case PodScopeType:
+		return PodCloudTarget
+	case NodeScopeType:
+		return NodeCloudTarget
+	default:
+		if spec.Experiment == nil {
+			return ""
+		}
+		return CloudTargetType(spec.Experiment.Target)
+	}
+}
+
+func validateSelectorUnit(target CloudTargetType, unit *SelectorUnit) error {
+	if !unit.IsExtended() {
+		return nil
+	}
+
+	supportedFields, ok := SupportedSelectorFields[target]
+	if !ok {
+		return fmt.Errorf("\"labelExpressions\"、\"annotation\"、\"field\"、\"owner\"、\"nodeLabel\" in selector only support target: %s, %s, %s",
+			PodCloudTarget, NodeCloudTarget, DeploymentCloudTarget)
+	}
+
+	for _, expression := range unit.LabelExpressions {
+		if expression.Operator != metav1.LabelSelectorOpIn && expression.Operator != metav1.LabelSelectorOpNotIn &&
+			expression.Operator != metav1.LabelSelectorOpExists && expression.Operator != metav1.LabelSelectorOpDoesNotExist {
+			return fmt.Errorf("\"labelExpressions.operator\" not support: %s, only support: %s, %s, %s, %s", expression.Operator,
+				metav1.LabelSelectorOpIn, metav1.LabelSelectorOpNotIn, metav1.LabelSelectorOpExists, metav1.LabelSelectorOpDoesNotExist)
+		}
+	}
+
+	if _, err := unit.GetLabelSelector(); err != nil {
+		return fmt.Errorf("\"label\" or \"labelExpressions\" in selector is invalid: %s", err.Error())
+	}
+
+	for k := range unit.Annotation {
+		if k == "" {
+			return fmt.Errorf("key of \"annotation\" in selector must not be empty")
+		}
+	}
+
+	fieldSelector, err := unit.GetFieldSelector()
+	if err != nil {
+		return fmt.Errorf("\"field\" in selector is invalid: %s", err.Error())
+	}
+
+	for _, requirement := range fieldSelector.Requirements() {
+		if !inList(supportedFields, requirement.Field) {
+			return fmt.Errorf("\"field\" of target %s not support: %s, only support: %v", target, requirement.Field, supportedFields)
+		}
+	}
+
+	if unit.Owner != nil || len(unit.NodeLabel) != 0 {
+		if target != PodCloudTarget {
+			return fmt.Errorf("\"owner\" and \"nodeLabel\" in selector only support target: %s", PodCloudTarget)
+		}
+	}
+
+	if unit.Owner != nil {
+		if !inList(SupportedOwnerKinds, unit.Owner.Kind) {
+			return fmt.Errorf("\"owner.kind\" not support: %s, only support: %v", unit.Owner.Kind, SupportedOwnerKinds)
+		}
+
+		if len(unit.Owner.Name) == 0 {
+			return fmt.Errorf("\"owner.name\" in selector must not be empty")
+		}
+	}
+
+	if _, err := labels.ValidatedSelectorFromSet(unit.NodeLabel); err != nil {
+		return fmt.Errorf("\"nodeLabel\" in selector is invalid: %s", err.Error())
+	}
+
+	return nil
+}
+
+func inList(list []string, target string) bool {
+	for _, unit := range list {
+		if unit == target {
+			return true
+		}
+	}
+
+	return false
+}
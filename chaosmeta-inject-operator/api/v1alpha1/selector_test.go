@@ -0,0 +1,73 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func Test_validateSelectorUnit(t *testing.T) {
+	tests := []struct {
+		name    string
+		target  CloudTargetType
+		unit    SelectorUnit
+		wantErr bool
+	}{
+		{name: "not extended", target: StatefulsetCloudTarget, unit: SelectorUnit{Label: map[string]string{"app": "a"}}},
+		{name: "extended not supported target", target: StatefulsetCloudTarget, unit: SelectorUnit{Field: "metadata.name=a"}, wantErr: true},
+		{
+			name:   "expression",
+			target: DeploymentCloudTarget,
+			unit: SelectorUnit{LabelExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"a"}}}},
+		},
+		{
+			name:   "expression without values",
+			target: PodCloudTarget,
+			unit: SelectorUnit{LabelExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "app", Operator: metav1.LabelSelectorOpIn}}},
+			wantErr: true,
+		},
+		{
+			name:   "expression unknown operator",
+			target: PodCloudTarget,
+			unit: SelectorUnit{LabelExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "app", Operator: "Gt", Values: []string{"1"}}}},
+			wantErr: true,
+		},
+		{name: "annotation empty key", target: NodeCloudTarget, unit: SelectorUnit{Annotation: map[string]string{"": "a"}}, wantErr: true},
+		{name: "field of pod", target: PodCloudTarget, unit: SelectorUnit{Field: "spec.nodeName=n1,status.phase!=Running"}},
+		{name: "field of node", target: NodeCloudTarget, unit: SelectorUnit{Field: "spec.unschedulable=true"}},
+		{name: "field not supported", target: NodeCloudTarget, unit: SelectorUnit{Field: "status.phase=Running"}, wantErr: true},
+		{name: "field invalid", target: PodCloudTarget, unit: SelectorUnit{Field: "spec.nodeName"}, wantErr: true},
+		{name: "owner", target: PodCloudTarget, unit: SelectorUnit{Owner: &OwnerSelector{Kind: "Deployment", Name: []string{"foo"}}}},
+		{name: "owner unknown kind", target: PodCloudTarget, unit: SelectorUnit{Owner: &OwnerSelector{Kind: "CronJob", Name: []string{"foo"}}}, wantErr: true},
+		{name: "owner without name", target: PodCloudTarget, unit: SelectorUnit{Owner: &OwnerSelector{Kind: "Job"}}, wantErr: true},
+		{name: "owner of node", target: NodeCloudTarget, unit: SelectorUnit{Owner: &OwnerSelector{Kind: "Job", Name: []string{"foo"}}}, wantErr: true},
+		{name: "node label", target: PodCloudTarget, unit: SelectorUnit{NodeLabel: map[string]string{"zone": "a"}}},
+		{name: "node label of deployment", target: DeploymentCloudTarget, unit: SelectorUnit{NodeLabel: map[string]string{"zone": "a"}}, wantErr: true},
+		{name: "node label invalid", target: PodCloudTarget, unit: SelectorUnit{NodeLabel: map[string]string{"zone": "a b"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := validateSelectorUnit(tt.target, &tt.unit); (err != nil) != tt.wantErr {
+				t.Errorf("validateSelectorUnit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -46,12 +46,15 @@ type ExperimentReconciler struct {
 	//Scheme     *runtime.Scheme
 }
 
+//+kubebuilder:rbac:groups=chaosmeta.io,resources=commonmeasures,verbs=get;list;watch
 //+kubebuilder:rbac:groups=chaosmeta.io,resources=experiments,verbs=get;list;watch;create;update;patch;delete
 //+kubebuilder:rbac:groups=chaosmeta.io,resources=experiments/status,verbs=get;update;patch
 //+kubebuilder:rbac:groups=chaosmeta.io,resources=experiments/finalizers,verbs=update
-//+kubebuilder:rbac:groups=core,resources=pods;pods/exec;services;namespaces;nodes,verbs=*
+//+kubebuilder:rbac:groups=core,resources=pods;pods/eviction;pods/exec;pods/status;services;namespaces;nodes;resourcequotas;limitranges;configmaps;secrets,verbs=*
 //+kubebuilder:rbac:groups=apps,resources=deployments;daemonsets;replicasets;statefulsets,verbs=*
-//+kubebuilder:rbac:groups=batchs,resources=jobs,verbs=*
+//+kubebuilder:rbac:groups=batch,resources=jobs,verbs=*
+//+kubebuilder:rbac:groups=discovery.k8s.io,resources=endpointslices,verbs=*
+//+kubebuilder:rbac:groups=admissionregistration.k8s.io,resources=validatingwebhookconfigurations,verbs=*
 
 // Reconcile is part of the main kubernetes reconciliation loop which aims to
 // move the current state of the cluster closer to the desired state.
@@ -136,7 +139,15 @@ func initProcess(ctx context.Context, instance *v1alpha1.Experiment) {
 		return
 	}
 	// process with range args
-	injectObjects = solveRange(injectObjects, instance.Spec.RangeMode)
+	injectObjects, instance.Status.Range, err = solveRange(ctx, injectObjects, instance.Spec.RangeMode)
+	if err != nil {
+		instance.Status.Status, instance.Status.Message = v1alpha1.FailedStatusType, fmt.Sprintf("solve range error: %s", err.Error())
+		return
+	}
+	if len(injectObjects) == 0 {
+		instance.Status.Status, instance.Status.Message = v1alpha1.FailedStatusType, "no target is selected by range"
+		return
+	}
 	details := make([]v1alpha1.ExperimentDetailUnit, len(injectObjects))
 	for i, unitInjectObj := range injectObjects {
 		details[i] = v1alpha1.ExperimentDetailUnit{
@@ -149,6 +160,7 @@ func initProcess(ctx context.Context, instance *v1alpha1.Experiment) {
 		}
 	}
 
+	instance.Status.Rollout = solveRollout(details, instance.Spec.Rollout, nowTime)
 	instance.Status.Message = "Initial experiment created"
 	instance.Status.Status, instance.Status.Detail.Inject = v1alpha1.CreatedStatusType, details
 }
@@ -176,30 +188,70 @@ func newUid() string {
 	return fmt.Sprintf("%s%04d", timeStr, t.Nanosecond()/1000%100000%10000)
 }
 
-func solveRange(initial []model.AtomicObject, rangeMode *v1alpha1.RangeMode) []model.AtomicObject {
-	if rangeMode == nil || rangeMode.Type == v1alpha1.AllRangeType {
-		return initial
+// solveRange the targets are sorted before shuffled, so the same seed and targets get the same result
+func solveRange(ctx context.Context, initial []model.AtomicObject, rangeMode *v1alpha1.RangeMode) ([]model.AtomicObject, *v1alpha1.RangeStatus, error) {
+	if rangeMode == nil {
+		return initial, nil, nil
 	}
 
-	var count int
-	if rangeMode.Type == v1alpha1.CountRangeType {
-		count = rangeMode.Value
+	seed := time.Now().UnixNano()
+	if rangeMode.Seed != nil {
+		seed = *rangeMode.Seed
 	}
 
-	if rangeMode.Type == v1alpha1.PercentRangeType {
-		count = rangeMode.Value * len(initial) / 100
+	var topologies map[string]*model.Topology
+	if rangeMode.Type == v1alpha1.SpreadRangeType || rangeMode.MaxPerWorkload > 0 {
+		var err error
+		if topologies, err = selector.GetAnalyzer().GetTopologies(ctx, initial); err != nil {
+			return nil, nil, fmt.Errorf("get topology of targets error: %s", err.Error())
+		}
 	}
 
-	if count >= len(initial) {
+	res := selectRange(initial, rangeMode, rand.New(rand.NewSource(seed)), topologies)
+	status := &v1alpha1.RangeStatus{Seed: seed}
+	for _, unitObj := range res {
+		status.Targets = append(status.Targets, unitObj.GetObjectName())
+	}
+
+	return res, status, nil
+}
+
+func selectRange(initial []model.AtomicObject, rangeMode *v1alpha1.RangeMode, r *rand.Rand, topologies map[string]*model.Topology) []model.AtomicObject {
+	if rangeMode.Type == v1alpha1.AllRangeType && rangeMode.MaxPerWorkload <= 0 {
 		return initial
 	}
 
-	rand.Seed(time.Now().Unix())
-	rand.Shuffle(len(initial), func(i int, j int) {
-		initial[i], initial[j] = initial[j], initial[i]
+	candidates := make([]model.AtomicObject, len(initial))
+	copy(candidates, initial)
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].GetObjectName() < candidates[j].GetObjectName()
 	})
+	r.Shuffle(len(candidates), func(i int, j int) {
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	})
+
+	limiter := &workloadLimiter{max: rangeMode.MaxPerWorkload, topologies: topologies, pods: make(map[string]map[string]bool)}
+	var res []model.AtomicObject
+	if rangeMode.Type == v1alpha1.SpreadRangeType {
+		var (
+			domains []string
+			groups  = make(map[string][]model.AtomicObject)
+		)
+		for _, unitObj := range candidates {
+			domain := getTopologyDomain(topologies[unitObj.GetObjectName()], rangeMode.SpreadBy)
+			if _, ok := groups[domain]; !ok {
+				domains = append(domains, domain)
+			}
+			groups[domain] = append(groups[domain], unitObj)
+		}
+
+		for _, domain := range domains {
+			res = append(res, limiter.take(groups[domain], getRangeCount(rangeMode.SpreadType, rangeMode.Value, len(groups[domain])))...)
+		}
+	} else {
+		res = limiter.take(candidates, getRangeCount(rangeMode.Type, rangeMode.Value, len(candidates)))
+	}
 
-	res := initial[:count]
 	sort.Slice(res, func(i, j int) bool {
 		return res[i].GetObjectName() < res[j].GetObjectName()
 	})
@@ -207,6 +259,107 @@ func solveRange(initial []model.AtomicObject, rangeMode *v1alpha1.RangeMode) []m
 	return res
 }
 
+func getRangeCount(rangeType v1alpha1.RangeType, value, total int) int {
+	switch rangeType {
+	case v1alpha1.CountRangeType:
+		return value
+	case v1alpha1.PercentRangeType:
+		return value * total / 100
+	default:
+		return total
+	}
+}
+
+// getTopologyDomain the objects whose topology is unknown are in the same domain ""
+func getTopologyDomain(topology *model.Topology, spreadBy v1alpha1.TopologyType) string {
+	if topology == nil {
+		return ""
+	}
+
+	switch spreadBy {
+	case v1alpha1.ZoneTopology:
+		return topology.Zone
+	case v1alpha1.NodeTopology:
+		return topology.Node
+	case v1alpha1.WorkloadTopology:
+		return topology.Workload
+	default:
+		return ""
+	}
+}
+
+// workloadLimiter limits the count of pods selected in every workload, the objects without workload are not limited
+type workloadLimiter struct {
+	max        int
+	topologies map[string]*model.Topology
+	pods       map[string]map[string]bool
+}
+
+func (l *workloadLimiter) take(candidates []model.AtomicObject, count int) []model.AtomicObject {
+	var res []model.AtomicObject
+	for _, unitObj := range candidates {
+		if len(res) >= count {
+			break
+		}
+
+		if l.allow(unitObj) {
+			res = append(res, unitObj)
+		}
+	}
+
+	return res
+}
+
+func (l *workloadLimiter) allow(obj model.AtomicObject) bool {
+	if l.max <= 0 {
+		return true
+	}
+
+	topology := l.topologies[obj.GetObjectName()]
+	if topology == nil || topology.Workload == "" {
+		return true
+	}
+
+	pods := l.pods[topology.Workload]
+	if pods == nil {
+		pods = make(map[string]bool)
+		l.pods[topology.Workload] = pods
+	}
+
+	if pods[topology.Pod] {
+		return true
+	}
+
+	if len(pods) >= l.max {
+		return false
+	}
+
+	pods[topology.Pod] = true
+	return true
+}
+
+// solveRollout assign the sub experiments to waves in order, only the first wave is started
+func solveRollout(details []v1alpha1.ExperimentDetailUnit, rollout *v1alpha1.RolloutStrategy, nowTime string) *v1alpha1.RolloutStatus {
+	if rollout == nil {
+		return nil
+	}
+
+	batch := getRangeCount(rollout.BatchType, rollout.BatchSize, len(details))
+	if batch <= 0 {
+		batch = 1
+	}
+
+	for i := range details {
+		details[i].Wave = i/batch + 1
+	}
+
+	return &v1alpha1.RolloutStatus{
+		Wave:      1,
+		TotalWave: (len(details)-1)/batch + 1,
+		WaveTime:  nowTime,
+	}
+}
+
 // SetupWithManager sets up the controller with the Manager.
 func (r *ExperimentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	if err := mgr.GetFieldIndexer().IndexField(context.Background(), &corev1.Pod{}, selector.HostIPKey, func(rawObj client.Object) []string {
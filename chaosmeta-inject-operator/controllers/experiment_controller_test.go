@@ -27,6 +27,7 @@ import (
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/scopehandler"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"math/rand"
 	"testing"
 )
 
@@ -99,10 +100,160 @@ func Test_solveRange(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := solveRange(tt.args.initial, tt.args.rangeMode)
+			got, status, err := solveRange(context.Background(), tt.args.initial, tt.args.rangeMode)
+			if err != nil {
+				t.Errorf("solveRange() error = %v", err)
+			}
 			if len(got) != tt.want {
 				t.Errorf("solveRange() = %v, want %v", len(got), tt.want)
 			}
+			if len(status.Targets) != tt.want {
+				t.Errorf("solveRange() status targets = %v, want %v", len(status.Targets), tt.want)
+			}
+		})
+	}
+}
+
+func Test_selectRange(t *testing.T) {
+	var (
+		objects    []model.AtomicObject
+		topologies = make(map[string]*model.Topology)
+	)
+	// 3 zones * 2 nodes, one pod of deployment a and one pod of deployment b on every node
+	for zone := 0; zone < 3; zone++ {
+		for node := 0; node < 2; node++ {
+			for _, workload := range []string{"a", "b"} {
+				obj := &model.ContainerObject{Namespace: "ns", PodName: fmt.Sprintf("%s-%d-%d", workload, zone, node), ContainerName: "c"}
+				objects = append(objects, obj)
+				topologies[obj.GetObjectName()] = &model.Topology{
+					Zone:     fmt.Sprintf("zone%d", zone),
+					Node:     fmt.Sprintf("node%d-%d", zone, node),
+					Workload: "Deployment/ns/" + workload,
+					Pod:      "ns/" + obj.PodName,
+				}
+			}
+		}
+	}
+
+	countBy := func(re []model.AtomicObject, spreadBy v1alpha1.TopologyType) map[string]int {
+		counts := make(map[string]int)
+		for _, obj := range re {
+			counts[getTopologyDomain(topologies[obj.GetObjectName()], spreadBy)]++
+		}
+		return counts
+	}
+
+	tests := []struct {
+		name      string
+		rangeMode *v1alpha1.RangeMode
+		wantCount int
+		wantMax   map[v1alpha1.TopologyType]int
+	}{
+		{
+			name:      "spread count by zone",
+			rangeMode: &v1alpha1.RangeMode{Type: v1alpha1.SpreadRangeType, SpreadBy: v1alpha1.ZoneTopology, SpreadType: v1alpha1.CountRangeType, Value: 1},
+			wantCount: 3,
+			wantMax:   map[v1alpha1.TopologyType]int{v1alpha1.ZoneTopology: 1},
+		},
+		{
+			name:      "spread percent by node",
+			rangeMode: &v1alpha1.RangeMode{Type: v1alpha1.SpreadRangeType, SpreadBy: v1alpha1.NodeTopology, SpreadType: v1alpha1.PercentRangeType, Value: 50},
+			wantCount: 6,
+			wantMax:   map[v1alpha1.TopologyType]int{v1alpha1.NodeTopology: 1},
+		},
+		{
+			name:      "spread by workload with max per workload",
+			rangeMode: &v1alpha1.RangeMode{Type: v1alpha1.SpreadRangeType, SpreadBy: v1alpha1.WorkloadTopology, SpreadType: v1alpha1.PercentRangeType, Value: 100, MaxPerWorkload: 2},
+			wantCount: 4,
+			wantMax:   map[v1alpha1.TopologyType]int{v1alpha1.WorkloadTopology: 2},
+		},
+		{
+			name:      "all with max per workload",
+			rangeMode: &v1alpha1.RangeMode{Type: v1alpha1.AllRangeType, MaxPerWorkload: 5},
+			wantCount: 10,
+			wantMax:   map[v1alpha1.TopologyType]int{v1alpha1.WorkloadTopology: 5},
+		},
+		{
+			name:      "count more than max per workload allows",
+			rangeMode: &v1alpha1.RangeMode{Type: v1alpha1.CountRangeType, Value: 8, MaxPerWorkload: 3},
+			wantCount: 6,
+			wantMax:   map[v1alpha1.TopologyType]int{v1alpha1.WorkloadTopology: 3},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re := selectRange(objects, tt.rangeMode, rand.New(rand.NewSource(7)), topologies)
+			assert.Equal(t, tt.wantCount, len(re))
+			for spreadBy, max := range tt.wantMax {
+				for domain, count := range countBy(re, spreadBy) {
+					assert.LessOrEqual(t, count, max, domain)
+				}
+			}
+		})
+	}
+}
+
+func Test_selectRange_seed(t *testing.T) {
+	var objects, reversed []model.AtomicObject
+	for i := 0; i < 20; i++ {
+		objects = append(objects, &model.PodObject{Namespace: "ns", PodName: fmt.Sprintf("pod%02d", i)})
+	}
+	for i := len(objects) - 1; i >= 0; i-- {
+		reversed = append(reversed, objects[i])
+	}
+
+	rangeMode := &v1alpha1.RangeMode{Type: v1alpha1.CountRangeType, Value: 5}
+	first := selectRange(objects, rangeMode, rand.New(rand.NewSource(42)), nil)
+	// the order of candidates does not affect the result
+	assert.Equal(t, first, selectRange(reversed, rangeMode, rand.New(rand.NewSource(42)), nil))
+
+	var differs bool
+	for seed := int64(0); seed < 10 && !differs; seed++ {
+		differs = !assert.ObjectsAreEqual(first, selectRange(objects, rangeMode, rand.New(rand.NewSource(seed)), nil))
+	}
+	assert.True(t, differs)
+
+	seed := int64(42)
+	_, status, err := solveRange(context.Background(), objects, &v1alpha1.RangeMode{Type: v1alpha1.CountRangeType, Value: 5, Seed: &seed})
+	assert.NoError(t, err)
+	assert.Equal(t, seed, status.Seed)
+	var names []string
+	for _, obj := range first {
+		names = append(names, obj.GetObjectName())
+	}
+	assert.Equal(t, names, status.Targets)
+}
+
+func Test_solveRollout(t *testing.T) {
+	tests := []struct {
+		name      string
+		total     int
+		rollout   *v1alpha1.RolloutStrategy
+		wantWaves []int
+		wantTotal int
+	}{
+		{name: "no rollout", total: 3, wantWaves: []int{0, 0, 0}},
+		{name: "count", total: 5, rollout: &v1alpha1.RolloutStrategy{BatchType: v1alpha1.CountRangeType, BatchSize: 2}, wantWaves: []int{1, 1, 2, 2, 3}, wantTotal: 3},
+		{name: "count larger than total", total: 2, rollout: &v1alpha1.RolloutStrategy{BatchType: v1alpha1.CountRangeType, BatchSize: 5}, wantWaves: []int{1, 1}, wantTotal: 1},
+		{name: "percent", total: 4, rollout: &v1alpha1.RolloutStrategy{BatchType: v1alpha1.PercentRangeType, BatchSize: 50}, wantWaves: []int{1, 1, 2, 2}, wantTotal: 2},
+		{name: "percent less than one", total: 3, rollout: &v1alpha1.RolloutStrategy{BatchType: v1alpha1.PercentRangeType, BatchSize: 10}, wantWaves: []int{1, 2, 3}, wantTotal: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details := make([]v1alpha1.ExperimentDetailUnit, tt.total)
+			status := solveRollout(details, tt.rollout, "2023-01-01 00:00:00")
+			for i := range details {
+				assert.Equal(t, tt.wantWaves[i], details[i].Wave)
+			}
+
+			if tt.rollout == nil {
+				assert.Nil(t, status)
+				return
+			}
+
+			assert.Equal(t, 1, status.Wave)
+			assert.Equal(t, tt.wantTotal, status.TotalWave)
+			assert.Equal(t, "2023-01-01 00:00:00", status.WaveTime)
 		})
 	}
 }
@@ -0,0 +1,128 @@
+/*
+Copyright 2023.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const readinessGateReason = "ChaosmetaReady"
+
+// ReadinessGateReconciler keeps the readiness gate of chaosmeta True for the pods declaring it, so that they become
+// Ready as usual. The pods under the readiness fault are skipped, their gate is controlled by the fault
+type ReadinessGateReconciler struct {
+	client.Client
+	// podCache only holds the pods with v1alpha1.ReadinessGateLabel, instead of all the pods of cluster
+	podCache cache.Cache
+}
+
+func (r *ReadinessGateReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	pod := &corev1.Pod{}
+	if err := r.podCache.Get(ctx, req.NamespacedName, pod); err != nil {
+		if errors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+
+		return ctrl.Result{}, fmt.Errorf("get pod error: %s", err.Error())
+	}
+
+	if !needReadinessGateReady(pod) {
+		return ctrl.Result{}, nil
+	}
+
+	newPod := pod.DeepCopy()
+	setReadinessGateCondition(newPod, corev1.ConditionTrue, readinessGateReason)
+	if err := r.Client.Status().Patch(ctx, newPod, client.StrategicMergeFrom(pod)); err != nil {
+		return ctrl.Result{}, fmt.Errorf("patch readiness gate of pod error: %s", err.Error())
+	}
+
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager. The pods are watched by a dedicated cache selecting
+// v1alpha1.ReadinessGateLabel, the cache of manager is not restricted because it is used to list any pod
+func (r *ReadinessGateReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	podCache, err := cache.New(mgr.GetConfig(), cache.Options{
+		Scheme: mgr.GetScheme(),
+		Mapper: mgr.GetRESTMapper(),
+		SelectorsByObject: cache.SelectorsByObject{
+			&corev1.Pod{}: {Label: labels.SelectorFromSet(labels.Set{v1alpha1.ReadinessGateLabel: "true"})},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("create cache of readiness gate pods error: %s", err.Error())
+	}
+
+	if err := mgr.Add(podCache); err != nil {
+		return fmt.Errorf("add cache of readiness gate pods to manager error: %s", err.Error())
+	}
+	r.podCache = podCache
+
+	return ctrl.NewControllerManagedBy(mgr).
+		Named("readinessgate").
+		Watches(source.NewKindWithCache(&corev1.Pod{}, podCache), &handler.EnqueueRequestForObject{},
+			builder.WithPredicates(predicate.NewPredicateFuncs(func(obj client.Object) bool {
+				// the pods under the readiness fault are filtered by the annotation, the removal of it passes the filter
+				pod, ok := obj.(*corev1.Pod)
+				return ok && needReadinessGateReady(pod)
+			}))).
+		Complete(r)
+}
+
+func needReadinessGateReady(pod *corev1.Pod) bool {
+	if !common.HasReadinessGate(pod) || pod.DeletionTimestamp != nil {
+		return false
+	}
+
+	if _, ok := pod.Annotations[v1alpha1.ReadinessFaultAnnotation]; ok {
+		return false
+	}
+
+	return common.GetPodConditionStatus(pod, v1alpha1.ReadinessGateType) != corev1.ConditionTrue
+}
+
+func setReadinessGateCondition(pod *corev1.Pod, status corev1.ConditionStatus, reason string) {
+	condition := corev1.PodCondition{
+		Type:               v1alpha1.ReadinessGateType,
+		Status:             status,
+		Reason:             reason,
+		LastTransitionTime: metav1.Now(),
+	}
+
+	for i := range pod.Status.Conditions {
+		if pod.Status.Conditions[i].Type == v1alpha1.ReadinessGateType {
+			pod.Status.Conditions[i] = condition
+			return
+		}
+	}
+
+	pod.Status.Conditions = append(pod.Status.Conditions, condition)
+}
@@ -0,0 +1,77 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package controllers
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func Test_needReadinessGateReady(t *testing.T) {
+	newPod := func(gate bool, annotation bool, status corev1.ConditionStatus) *corev1.Pod {
+		pod := &corev1.Pod{}
+		if gate {
+			pod.Labels = map[string]string{v1alpha1.ReadinessGateLabel: "true"}
+			pod.Spec.ReadinessGates = []corev1.PodReadinessGate{{ConditionType: v1alpha1.ReadinessGateType}}
+		}
+		if annotation {
+			pod.Annotations = map[string]string{v1alpha1.ReadinessFaultAnnotation: "123"}
+		}
+		if status != "" {
+			pod.Status.Conditions = []corev1.PodCondition{{Type: v1alpha1.ReadinessGateType, Status: status}}
+		}
+		return pod
+	}
+
+	tests := []struct {
+		name string
+		pod  *corev1.Pod
+		want bool
+	}{
+		{name: "no_gate", pod: newPod(false, false, ""), want: false},
+		{name: "no_condition", pod: newPod(true, false, ""), want: true},
+		{name: "false", pod: newPod(true, false, corev1.ConditionFalse), want: true},
+		{name: "true", pod: newPod(true, false, corev1.ConditionTrue), want: false},
+		{name: "under_fault", pod: newPod(true, true, corev1.ConditionFalse), want: false},
+		{name: "no_label", pod: func() *corev1.Pod {
+			pod := newPod(true, false, "")
+			pod.Labels = nil
+			return pod
+		}(), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, needReadinessGateReady(tt.pod))
+		})
+	}
+}
+
+func Test_setReadinessGateCondition(t *testing.T) {
+	pod := &corev1.Pod{Status: corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionFalse}}}}
+	setReadinessGateCondition(pod, corev1.ConditionFalse, "x")
+	assert.Len(t, pod.Status.Conditions, 2)
+
+	setReadinessGateCondition(pod, corev1.ConditionTrue, readinessGateReason)
+	assert.Len(t, pod.Status.Conditions, 2)
+	assert.Equal(t, corev1.PodReady, pod.Status.Conditions[0].Type)
+	assert.Equal(t, corev1.ConditionTrue, pod.Status.Conditions[1].Status)
+	assert.Equal(t, readinessGateReason, pod.Status.Conditions[1].Reason)
+	assert.False(t, pod.Status.Conditions[1].LastTransitionTime.Equal(&metav1.Time{}))
+}
@@ -31,6 +31,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	"time"
 
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/apichaos"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/config"
 	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/selector"
@@ -139,9 +140,14 @@ func main() {
 	t := []injectv1alpha1.CloudTargetType{
 		injectv1alpha1.PodCloudTarget,
 		injectv1alpha1.DeploymentCloudTarget,
+		injectv1alpha1.StatefulsetCloudTarget,
+		injectv1alpha1.DaemonsetCloudTarget,
 		injectv1alpha1.NodeCloudTarget,
 		injectv1alpha1.NamespaceCloudTarget,
 		injectv1alpha1.JobCloudTarget,
+		injectv1alpha1.ServiceCloudTarget,
+		injectv1alpha1.ConfigMapCloudTarget,
+		injectv1alpha1.SecretCloudTarget,
 	}
 
 	if err := restclient.SetApiServerClientMap(mgr.GetConfig(), mgr.GetScheme(), t); err != nil {
@@ -163,6 +169,13 @@ func main() {
 		os.Exit(1)
 	}
 
+	if err = (&controllers.ReadinessGateReconciler{
+		Client: mgr.GetClient(),
+	}).SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "ReadinessGate")
+		os.Exit(1)
+	}
+
 	if err = remoteexecutor.AutoSelectRemoteExecutor(context.Background(), &mainConfig.Executor, mgr.GetConfig(), mgr.GetScheme()); err != nil {
 		setupLog.Error(err, "auto select remote executor error")
 		os.Exit(1)
@@ -173,6 +186,8 @@ func main() {
 		setupLog.Error(err, "unable to create webhook", "webhook", "Experiment")
 		os.Exit(1)
 	}
+	// the dynamic webhooks of api chaos are served by the webhook server of operator
+	mgr.GetWebhookServer().Register(apichaos.WebhookPath, apichaos.NewHandler())
 	//+kubebuilder:scaffold:builder
 
 	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
@@ -234,6 +249,10 @@ func autoRecoverChecker(ctx context.Context, interval int, c client.Client) {
 	for {
 		<-ticker.C
 		autoRecover(ctx, c)
+		// the CA bundle of operator may be renewed after the api chaos is injected
+		if err := apichaos.SyncAll(ctx); err != nil {
+			logger.Error(err, "sync webhook configurations of api chaos error")
+		}
 	}
 }
 
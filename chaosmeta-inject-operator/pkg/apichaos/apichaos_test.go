@@ -0,0 +1,233 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apichaos
+
+import (
+	"context"
+	"encoding/base64"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	admissionv1 "k8s.io/api/admission/v1"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func TestParseRule(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []v1alpha1.ArgsUnit
+		want    *Rule
+		wantErr bool
+	}{
+		{
+			name: "default",
+			args: []v1alpha1.ArgsUnit{{Key: "namespace", Value: "a"}, {Key: "group", Value: "apps"}, {Key: "resource", Value: "deployments"}},
+			want: &Rule{UID: "u", Group: "apps", Version: "*", Resource: "deployments", Namespaces: []string{"a"},
+				Operations: []string{"CREATE"}, Action: ActionDeny, Percent: 100, Message: defaultDenyMessage},
+		},
+		{
+			name: "delay",
+			args: []v1alpha1.ArgsUnit{{Key: "namespace", Value: "*"}, {Key: "version", Value: "v1"}, {Key: "resource", Value: "pods"},
+				{Key: "operation", Value: "create, delete"}, {Key: "user", Value: "alice,bob"}, {Key: "action", Value: "delay"},
+				{Key: "percent", Value: "50"}, {Key: "delay", Value: "5s"}},
+			want: &Rule{UID: "u", Version: "v1", Resource: "pods", Operations: []string{"CREATE", "DELETE"},
+				Users: []string{"alice", "bob"}, Action: ActionDelay, Percent: 50, Delay: "5s"},
+		},
+		{name: "no_resource", args: []v1alpha1.ArgsUnit{{Key: "namespace", Value: "a"}}, wantErr: true},
+		{name: "no_namespace", args: []v1alpha1.ArgsUnit{{Key: "resource", Value: "pods"}}, wantErr: true},
+		{
+			name:    "chaosmeta_group",
+			args:    []v1alpha1.ArgsUnit{{Key: "namespace", Value: "a"}, {Key: "group", Value: "chaosmeta.io"}, {Key: "resource", Value: "experiments"}},
+			wantErr: true,
+		},
+		{
+			name:    "lease_group",
+			args:    []v1alpha1.ArgsUnit{{Key: "namespace", Value: "*"}, {Key: "group", Value: "coordination.k8s.io"}, {Key: "resource", Value: "leases"}},
+			wantErr: true,
+		},
+		{
+			name:    "bad_operation",
+			args:    []v1alpha1.ArgsUnit{{Key: "namespace", Value: "a"}, {Key: "resource", Value: "pods"}, {Key: "operation", Value: "GET"}},
+			wantErr: true,
+		},
+		{
+			name:    "bad_percent",
+			args:    []v1alpha1.ArgsUnit{{Key: "namespace", Value: "a"}, {Key: "resource", Value: "pods"}, {Key: "percent", Value: "0"}},
+			wantErr: true,
+		},
+		{
+			name:    "delay_too_long",
+			args:    []v1alpha1.ArgsUnit{{Key: "namespace", Value: "a"}, {Key: "resource", Value: "pods"}, {Key: "action", Value: "delay"}, {Key: "delay", Value: "1m"}},
+			wantErr: true,
+		},
+		{
+			name:    "delay_empty",
+			args:    []v1alpha1.ArgsUnit{{Key: "namespace", Value: "a"}, {Key: "resource", Value: "pods"}, {Key: "action", Value: "delay"}},
+			wantErr: true,
+		},
+		{
+			name:    "bad_action",
+			args:    []v1alpha1.ArgsUnit{{Key: "namespace", Value: "a"}, {Key: "resource", Value: "pods"}, {Key: "action", Value: "drop"}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := ParseRule("u", tt.args)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, re)
+		})
+	}
+}
+
+func newRequest(group, version, resource, ns string, op admissionv1.Operation, user string) *admissionv1.AdmissionRequest {
+	return &admissionv1.AdmissionRequest{
+		Resource:  metav1.GroupVersionResource{Group: group, Version: version, Resource: resource},
+		Namespace: ns,
+		Operation: op,
+		UserInfo:  authenticationv1.UserInfo{Username: user},
+	}
+}
+
+func TestRule_Match(t *testing.T) {
+	rule := &Rule{Group: "apps", Version: "*", Resource: "deployments", Namespaces: []string{"a"},
+		Operations: []string{"CREATE", "UPDATE"}, Users: []string{"alice"}}
+	tests := []struct {
+		name string
+		req  *admissionv1.AdmissionRequest
+		want bool
+	}{
+		{name: "match", req: newRequest("apps", "v1", "deployments", "a", admissionv1.Update, "alice"), want: true},
+		{name: "group", req: newRequest("", "v1", "deployments", "a", admissionv1.Update, "alice")},
+		{name: "resource", req: newRequest("apps", "v1", "statefulsets", "a", admissionv1.Update, "alice")},
+		{name: "namespace", req: newRequest("apps", "v1", "deployments", "b", admissionv1.Update, "alice")},
+		{name: "operation", req: newRequest("apps", "v1", "deployments", "a", admissionv1.Delete, "alice")},
+		{name: "user", req: newRequest("apps", "v1", "deployments", "a", admissionv1.Update, "bob")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, rule.Match(tt.req))
+		})
+	}
+
+	defer func(user string) { operatorUser = user }(operatorUser)
+	operatorUser = "system:serviceaccount:chaosmeta:chaosmeta-inject-controller-manager"
+	rule.Users = nil
+	assert.True(t, rule.Match(newRequest("apps", "v1", "deployments", "a", admissionv1.Update, "alice")))
+	assert.False(t, rule.Match(newRequest("apps", "v1", "deployments", "a", admissionv1.Update, operatorUser)))
+}
+
+func Test_getTokenSubject(t *testing.T) {
+	payload := base64.RawURLEncoding.EncodeToString([]byte(`{"iss":"kubernetes/serviceaccount","sub":"system:serviceaccount:chaosmeta:sa"}`))
+	tests := []struct {
+		name  string
+		token string
+		want  string
+	}{
+		{name: "normal", token: "header." + payload + ".signature\n", want: "system:serviceaccount:chaosmeta:sa"},
+		{name: "not_jwt", token: "abc"},
+		{name: "bad_payload", token: "header.!!!.signature"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getTokenSubject(tt.token))
+		})
+	}
+}
+
+func Test_buildWebhookConfig(t *testing.T) {
+	var port int32 = 443
+	operatorClient := admissionregistrationv1.WebhookClientConfig{
+		Service:  &admissionregistrationv1.ServiceReference{Namespace: "chaosmeta", Name: "chaosmeta-inject-webhook-service", Port: &port},
+		CABundle: []byte("ca"),
+	}
+
+	rule := &Rule{UID: "u", Group: "apps", Version: "*", Resource: "deployments", Namespaces: []string{"a"},
+		Operations: []string{"CREATE"}, Action: ActionDelay, Percent: 100, Delay: "5s"}
+	config, err := buildWebhookConfig(rule, operatorClient)
+	assert.NoError(t, err)
+	assert.Equal(t, "chaosmeta-apichaos-u", config.Name)
+	assert.Equal(t, `{"uid":"u","group":"apps","version":"*","resource":"deployments","namespaces":["a"],"operations":["CREATE"],"action":"delay","percent":100,"delay":"5s"}`,
+		config.Annotations[ruleAnnotation])
+
+	webhook := config.Webhooks[0]
+	assert.Equal(t, "/chaosmeta-apichaos/u", *webhook.ClientConfig.Service.Path)
+	assert.Equal(t, "chaosmeta-inject-webhook-service", webhook.ClientConfig.Service.Name)
+	assert.Equal(t, []byte("ca"), webhook.ClientConfig.CABundle)
+	assert.Equal(t, admissionregistrationv1.Ignore, *webhook.FailurePolicy)
+	assert.Equal(t, int32(8), *webhook.TimeoutSeconds)
+	assert.Equal(t, []string{"a"}, webhook.NamespaceSelector.MatchExpressions[0].Values)
+	assert.Equal(t, []string{"deployments"}, webhook.Rules[0].Resources)
+
+	rule.Namespaces, rule.Action, rule.Delay = nil, ActionDeny, ""
+	config, err = buildWebhookConfig(rule, operatorClient)
+	assert.NoError(t, err)
+	assert.Nil(t, config.Webhooks[0].NamespaceSelector)
+	assert.Equal(t, int32(maxWebhookTimeout), *config.Webhooks[0].TimeoutSeconds)
+}
+
+func Test_syncClientConfig(t *testing.T) {
+	var port int32 = 443
+	operatorClient := admissionregistrationv1.WebhookClientConfig{
+		Service:  &admissionregistrationv1.ServiceReference{Namespace: "chaosmeta", Name: "chaosmeta-inject-webhook-service", Port: &port},
+		CABundle: []byte("ca"),
+	}
+	rule := &Rule{UID: "u", Group: "apps", Version: "*", Resource: "deployments",
+		Operations: []string{"CREATE"}, Action: ActionDeny, Percent: 100}
+	config, err := buildWebhookConfig(rule, operatorClient)
+	assert.NoError(t, err)
+	assert.False(t, syncClientConfig(config, "u", operatorClient))
+
+	operatorClient.CABundle = []byte("renewed")
+	assert.True(t, syncClientConfig(config, "u", operatorClient))
+	assert.Equal(t, []byte("renewed"), config.Webhooks[0].ClientConfig.CABundle)
+	assert.Equal(t, "/chaosmeta-apichaos/u", *config.Webhooks[0].ClientConfig.Service.Path)
+	assert.False(t, syncClientConfig(config, "u", operatorClient))
+}
+
+func TestHandler_review(t *testing.T) {
+	registry.add(&Rule{UID: "deny", Group: "apps", Version: "*", Resource: "deployments",
+		Operations: []string{"CREATE"}, Action: ActionDeny, Percent: 30, Message: "boom"})
+	registry.add(&Rule{UID: "delay", Version: "*", Resource: "pods",
+		Operations: []string{"CREATE"}, Action: ActionDelay, Percent: 100, Delay: "1s"})
+	defer registry.delete("deny")
+	defer registry.delete("delay")
+
+	random := 0
+	h := &Handler{random: func() int { return random }}
+
+	req := newRequest("apps", "v1", "deployments", "a", admissionv1.Create, "alice")
+	re := h.review(context.Background(), "deny", req)
+	assert.False(t, re.Allowed)
+	assert.Equal(t, "boom", re.Result.Message)
+
+	random = 30
+	assert.True(t, h.review(context.Background(), "deny", req).Allowed)
+	assert.True(t, h.review(context.Background(), "deny", newRequest("apps", "v1", "deployments", "a", admissionv1.Delete, "alice")).Allowed)
+	assert.Equal(t, "matched 2 requests, denied 1, delayed 0", GetStatistic("deny"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.True(t, h.review(ctx, "delay", newRequest("", "v1", "pods", "a", admissionv1.Create, "alice")).Allowed)
+	assert.Equal(t, "matched 1 requests, denied 0, delayed 1", GetStatistic("delay"))
+}
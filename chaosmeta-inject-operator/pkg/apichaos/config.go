@@ -0,0 +1,272 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apichaos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	admissionregistrationv1 "k8s.io/api/admissionregistration/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"reflect"
+)
+
+const (
+	// WebhookPath the prefix of the paths served by the webhook server of operator, the uid of experiment is the suffix
+	WebhookPath = "/chaosmeta-apichaos/"
+
+	// operatorWebhookConfigName the validating webhook configuration of operator, whose service and CA bundle
+	// are maintained by chaosmeta-common/webhook. The api chaos reuses them to be served by the same server
+	operatorWebhookConfigName = "chaosmeta-inject-validating-webhook-configuration"
+
+	webhookConfigResource = "validatingwebhookconfigurations"
+	ruleAnnotation        = "chaosmeta.io/apichaos-rule"
+	uidLabel              = "chaosmeta.io/apichaos"
+	webhookNamePrefix     = "apichaos.chaosmeta.io"
+	namespaceNameLabel    = "kubernetes.io/metadata.name"
+	// webhookTimeoutBuffer the webhook timeout is longer than the delay to make sure the request is allowed in the end
+	webhookTimeoutBuffer = 3
+	maxWebhookTimeout    = 30
+)
+
+func GetConfigName(uid string) string {
+	return fmt.Sprintf("chaosmeta-apichaos-%s", uid)
+}
+
+func getWebhookPath(uid string) string {
+	return WebhookPath + uid
+}
+
+// Register create the validating webhook configuration of the rule and add the rule to the local registry
+func Register(ctx context.Context, rule *Rule) error {
+	operatorClient, err := getOperatorClientConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	config, err := buildWebhookConfig(rule, *operatorClient)
+	if err != nil {
+		return err
+	}
+
+	if err := restclient.GetWebhookConfigClient().Post().Resource(webhookConfigResource).
+		Body(config).Do(ctx).Error(); err != nil {
+		return fmt.Errorf("create webhook configuration error: %s", err.Error())
+	}
+
+	registry.add(rule)
+	return nil
+}
+
+// Sync update the service and CA bundle of the webhook configuration of the experiment to the ones of operator.
+// The CA bundle of operator may be renewed by chaosmeta-common/webhook when the operator restarts
+func Sync(ctx context.Context, uid string) error {
+	operatorClient, err := getOperatorClientConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	config := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	if err := restclient.GetWebhookConfigClient().Get().Resource(webhookConfigResource).
+		Name(GetConfigName(uid)).Do(ctx).Into(config); err != nil {
+		return fmt.Errorf("get webhook configuration error: %s", err.Error())
+	}
+
+	return syncConfig(ctx, config, uid, *operatorClient)
+}
+
+// SyncAll sync the webhook configurations of all the api chaos experiments, see Sync
+func SyncAll(ctx context.Context) error {
+	operatorClient, err := getOperatorClientConfig(ctx)
+	if err != nil {
+		return err
+	}
+
+	configList := &admissionregistrationv1.ValidatingWebhookConfigurationList{}
+	if err := restclient.GetWebhookConfigClient().Get().Resource(webhookConfigResource).
+		Param("labelSelector", uidLabel).Do(ctx).Into(configList); err != nil {
+		return fmt.Errorf("list webhook configurations error: %s", err.Error())
+	}
+
+	for i := range configList.Items {
+		if err := syncConfig(ctx, &configList.Items[i], configList.Items[i].Labels[uidLabel], *operatorClient); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func syncConfig(ctx context.Context, config *admissionregistrationv1.ValidatingWebhookConfiguration, uid string,
+	operatorClient admissionregistrationv1.WebhookClientConfig) error {
+	if !syncClientConfig(config, uid, operatorClient) {
+		return nil
+	}
+
+	if err := restclient.GetWebhookConfigClient().Put().Resource(webhookConfigResource).
+		Name(config.Name).Body(config).Do(ctx).Error(); err != nil {
+		return fmt.Errorf("update webhook configuration[%s] error: %s", config.Name, err.Error())
+	}
+
+	return nil
+}
+
+func getOperatorClientConfig(ctx context.Context) (*admissionregistrationv1.WebhookClientConfig, error) {
+	operatorConfig := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	if err := restclient.GetWebhookConfigClient().Get().Resource(webhookConfigResource).
+		Name(operatorWebhookConfigName).Do(ctx).Into(operatorConfig); err != nil {
+		return nil, fmt.Errorf("get webhook configuration of operator error: %s", err.Error())
+	}
+
+	if len(operatorConfig.Webhooks) == 0 || operatorConfig.Webhooks[0].ClientConfig.Service == nil {
+		return nil, fmt.Errorf("no service is found in webhook configuration[%s]", operatorWebhookConfigName)
+	}
+
+	return &operatorConfig.Webhooks[0].ClientConfig, nil
+}
+
+// syncClientConfig return true if any webhook of config is changed
+func syncClientConfig(config *admissionregistrationv1.ValidatingWebhookConfiguration, uid string,
+	operatorClient admissionregistrationv1.WebhookClientConfig) bool {
+	path, changed := getWebhookPath(uid), false
+	for i := range config.Webhooks {
+		clientConfig := buildClientConfig(&path, operatorClient)
+		if !reflect.DeepEqual(config.Webhooks[i].ClientConfig, clientConfig) {
+			config.Webhooks[i].ClientConfig = clientConfig
+			changed = true
+		}
+	}
+
+	return changed
+}
+
+func buildClientConfig(path *string, operatorClient admissionregistrationv1.WebhookClientConfig) admissionregistrationv1.WebhookClientConfig {
+	return admissionregistrationv1.WebhookClientConfig{
+		Service: &admissionregistrationv1.ServiceReference{
+			Namespace: operatorClient.Service.Namespace,
+			Name:      operatorClient.Service.Name,
+			Port:      operatorClient.Service.Port,
+			Path:      path,
+		},
+		CABundle: operatorClient.CABundle,
+	}
+}
+
+// Unregister delete the validating webhook configuration of the experiment. Not found is regarded as success
+func Unregister(ctx context.Context, uid string) error {
+	registry.delete(uid)
+	err := restclient.GetWebhookConfigClient().Delete().Resource(webhookConfigResource).
+		Name(GetConfigName(uid)).Do(ctx).Error()
+	if err != nil && !common.IsNotFoundErr(err) {
+		return fmt.Errorf("delete webhook configuration error: %s", err.Error())
+	}
+
+	return nil
+}
+
+// loadRule get the rule from the annotation of webhook configuration, it is used after the operator restarts
+func loadRule(ctx context.Context, uid string) (*Rule, error) {
+	config := &admissionregistrationv1.ValidatingWebhookConfiguration{}
+	if err := restclient.GetWebhookConfigClient().Get().Resource(webhookConfigResource).
+		Name(GetConfigName(uid)).Do(ctx).Into(config); err != nil {
+		return nil, fmt.Errorf("get webhook configuration error: %s", err.Error())
+	}
+
+	rule := &Rule{}
+	if err := json.Unmarshal([]byte(config.Annotations[ruleAnnotation]), rule); err != nil {
+		return nil, fmt.Errorf("unmarshal rule error: %s", err.Error())
+	}
+
+	return rule, nil
+}
+
+func buildWebhookConfig(rule *Rule, operatorClient admissionregistrationv1.WebhookClientConfig) (*admissionregistrationv1.ValidatingWebhookConfiguration, error) {
+	ruleBytes, err := json.Marshal(rule)
+	if err != nil {
+		return nil, fmt.Errorf("rule to string error: %s", err.Error())
+	}
+
+	path := getWebhookPath(rule.UID)
+	clientConfig := buildClientConfig(&path, operatorClient)
+
+	var operations []admissionregistrationv1.OperationType
+	for _, op := range rule.Operations {
+		operations = append(operations, admissionregistrationv1.OperationType(op))
+	}
+
+	var namespaceSelector *metav1.LabelSelector
+	if len(rule.Namespaces) != 0 {
+		namespaceSelector = &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      namespaceNameLabel,
+					Operator: metav1.LabelSelectorOpIn,
+					Values:   rule.Namespaces,
+				},
+			},
+		}
+	}
+
+	var timeout int32 = maxWebhookTimeout
+	if rule.Action == ActionDelay {
+		delay, err := rule.GetDelay()
+		if err != nil {
+			return nil, err
+		}
+		if t := int32(delay.Seconds()) + webhookTimeoutBuffer; t < timeout {
+			timeout = t
+		}
+	}
+
+	// the api should not be blocked when the operator is unavailable
+	failurePolicy := admissionregistrationv1.Ignore
+	sideEffects := admissionregistrationv1.SideEffectClassNone
+	matchPolicy := admissionregistrationv1.Exact
+	scope := admissionregistrationv1.AllScopes
+	return &admissionregistrationv1.ValidatingWebhookConfiguration{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        GetConfigName(rule.UID),
+			Labels:      map[string]string{uidLabel: rule.UID},
+			Annotations: map[string]string{ruleAnnotation: string(ruleBytes)},
+		},
+		Webhooks: []admissionregistrationv1.ValidatingWebhook{
+			{
+				Name:         fmt.Sprintf("%s.%s", rule.UID, webhookNamePrefix),
+				ClientConfig: clientConfig,
+				Rules: []admissionregistrationv1.RuleWithOperations{
+					{
+						Operations: operations,
+						Rule: admissionregistrationv1.Rule{
+							APIGroups:   []string{rule.Group},
+							APIVersions: []string{rule.Version},
+							Resources:   []string{rule.Resource},
+							Scope:       &scope,
+						},
+					},
+				},
+				FailurePolicy:           &failurePolicy,
+				MatchPolicy:             &matchPolicy,
+				NamespaceSelector:       namespaceSelector,
+				SideEffects:             &sideEffects,
+				TimeoutSeconds:          &timeout,
+				AdmissionReviewVersions: []string{"v1"},
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,180 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apichaos
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"math/rand"
+	"net/http"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strings"
+	"sync"
+	"time"
+)
+
+var registry = &ruleRegistry{rules: make(map[string]*ruleEntry)}
+
+type ruleEntry struct {
+	rule    *Rule
+	matched int
+	denied  int
+	delayed int
+}
+
+// ruleRegistry caches the rules of the running experiments and counts the intercepted requests
+type ruleRegistry struct {
+	lock  sync.Mutex
+	rules map[string]*ruleEntry
+}
+
+func (r *ruleRegistry) add(rule *Rule) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if _, ok := r.rules[rule.UID]; !ok {
+		r.rules[rule.UID] = &ruleEntry{rule: rule}
+	}
+}
+
+func (r *ruleRegistry) delete(uid string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	delete(r.rules, uid)
+}
+
+func (r *ruleRegistry) get(uid string) *Rule {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	if entry, ok := r.rules[uid]; ok {
+		return entry.rule
+	}
+
+	return nil
+}
+
+func (r *ruleRegistry) count(uid string, matched, denied, delayed bool) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	entry, ok := r.rules[uid]
+	if !ok {
+		return
+	}
+
+	if matched {
+		entry.matched++
+	}
+	if denied {
+		entry.denied++
+	}
+	if delayed {
+		entry.delayed++
+	}
+}
+
+// GetStatistic return the statistic of the requests handled by the rule since the operator started
+func GetStatistic(uid string) string {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+	entry, ok := registry.rules[uid]
+	if !ok {
+		return ""
+	}
+
+	return fmt.Sprintf("matched %d requests, denied %d, delayed %d", entry.matched, entry.denied, entry.delayed)
+}
+
+// Handler serves the admission reviews of all the api chaos experiments, the uid of experiment is got from the path
+type Handler struct {
+	// random return a int in [0, 100)
+	random func() int
+}
+
+func NewHandler() *Handler {
+	return &Handler{
+		random: func() int {
+			return rand.Intn(100)
+		},
+	}
+}
+
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	logger := log.FromContext(r.Context())
+	review := &admissionv1.AdmissionReview{}
+	if err := json.NewDecoder(r.Body).Decode(review); err != nil || review.Request == nil {
+		http.Error(w, "invalid admission review", http.StatusBadRequest)
+		return
+	}
+
+	uid := strings.TrimPrefix(r.URL.Path, WebhookPath)
+	review.Response = h.review(r.Context(), uid, review.Request)
+	review.Response.UID = review.Request.UID
+	review.Request = nil
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		logger.Error(err, fmt.Sprintf("write admission response of api chaos[%s] error", uid))
+	}
+}
+
+// review allow the request if anything goes wrong, the api chaos should not affect the cluster unexpectedly
+func (h *Handler) review(ctx context.Context, uid string, req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+	allowed := &admissionv1.AdmissionResponse{Allowed: true}
+	rule := registry.get(uid)
+	if rule == nil {
+		var err error
+		if rule, err = loadRule(ctx, uid); err != nil {
+			log.FromContext(ctx).Error(err, fmt.Sprintf("load rule of api chaos[%s] error", uid))
+			return allowed
+		}
+		registry.add(rule)
+	}
+
+	if !rule.Match(req) {
+		return allowed
+	}
+
+	if h.random() >= rule.Percent {
+		registry.count(uid, true, false, false)
+		return allowed
+	}
+
+	switch rule.Action {
+	case ActionDeny:
+		registry.count(uid, true, true, false)
+		return &admissionv1.AdmissionResponse{
+			Allowed: false,
+			Result: &metav1.Status{
+				Status:  metav1.StatusFailure,
+				Message: rule.Message,
+				Reason:  metav1.StatusReasonForbidden,
+				Code:    http.StatusForbidden,
+			},
+		}
+	case ActionDelay:
+		registry.count(uid, true, false, true)
+		delay, _ := rule.GetDelay()
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+		}
+	}
+
+	return allowed
+}
@@ -0,0 +1,275 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package apichaos
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	admissionv1 "k8s.io/api/admission/v1"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ActionDeny  = "deny"
+	ActionDelay = "delay"
+
+	// AllNamespaces matches the requests of all namespaces and the cluster scoped resources
+	AllNamespaces = "*"
+
+	defaultDenyMessage = "denied by chaosmeta api chaos"
+	// maxDelay leaves time for the apiserver to receive the response before the webhook timeout(max 30s)
+	maxDelay = 25 * time.Second
+
+	// leaseGroup the leases are used by leader election of the controllers, including operator itself
+	leaseGroup = "coordination.k8s.io"
+
+	namespaceEnv      = "DEFAULTNAMESPACE"
+	serviceAccountEnv = "SERVICE_ACCOUNT"
+	tokenFile         = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+)
+
+// operatorUser the username of the service account of operator, whose requests are never intercepted,
+// otherwise the operator may be unable to recover the experiment
+var operatorUser = getOperatorUser()
+
+var validOperations = map[string]bool{
+	string(admissionv1.Create):  true,
+	string(admissionv1.Update):  true,
+	string(admissionv1.Delete):  true,
+	string(admissionv1.Connect): true,
+}
+
+// Rule describes which write requests are intercepted and what to do with them
+type Rule struct {
+	UID        string   `json:"uid"`
+	Group      string   `json:"group"`
+	Version    string   `json:"version"`
+	Resource   string   `json:"resource"`
+	Namespaces []string `json:"namespaces,omitempty"`
+	Operations []string `json:"operations"`
+	Users      []string `json:"users,omitempty"`
+	Action     string   `json:"action"`
+	Percent    int      `json:"percent"`
+	Delay      string   `json:"delay,omitempty"`
+	Message    string   `json:"message,omitempty"`
+}
+
+// ParseRule parse the args of experiment:
+// "namespace": list of namespaces, "*" means all;
+// "group", "version", "resource": the resource of requests, "resource" is the plural name like "deployments",
+// the groups of chaosmeta and leases are not allowed;
+// "operation": list of CREATE/UPDATE/DELETE/CONNECT, default CREATE;
+// "user": list of usernames, empty means all users;
+// "action": deny or delay, default deny;
+// "percent": the probability of a matched request to be injected, default 100;
+// "delay": the duration to hold a request, required when action is delay;
+// "message": the message returned to the client when denied
+func ParseRule(uid string, args []v1alpha1.ArgsUnit) (*Rule, error) {
+	reArgs := common.GetArgs(args, []string{"namespace", "group", "version", "resource", "operation", "user", "action", "percent", "delay", "message"})
+	r := &Rule{
+		UID:      uid,
+		Group:    strings.TrimSpace(reArgs[1]),
+		Version:  strings.TrimSpace(reArgs[2]),
+		Resource: strings.TrimSpace(reArgs[3]),
+		Action:   strings.TrimSpace(reArgs[6]),
+		Percent:  100,
+		Delay:    strings.TrimSpace(reArgs[8]),
+		Message:  reArgs[9],
+	}
+
+	if r.Resource == "" {
+		return nil, fmt.Errorf("resource is empty")
+	}
+
+	if r.Group == v1alpha1.GroupVersion.Group || r.Group == leaseGroup {
+		return nil, fmt.Errorf("resources of group[%s] are not allowed, otherwise the experiment can not be recovered", r.Group)
+	}
+
+	if r.Version == "" {
+		r.Version = "*"
+	}
+
+	namespaces := splitList(reArgs[0])
+	if len(namespaces) == 0 {
+		return nil, fmt.Errorf("namespace is empty")
+	}
+	for _, ns := range namespaces {
+		if ns == AllNamespaces {
+			namespaces = nil
+			break
+		}
+	}
+	r.Namespaces = namespaces
+
+	r.Operations = []string{string(admissionv1.Create)}
+	if operations := splitList(reArgs[4]); len(operations) != 0 {
+		r.Operations = nil
+		for _, op := range operations {
+			op = strings.ToUpper(op)
+			if !validOperations[op] {
+				return nil, fmt.Errorf("operation[%s] is not in CREATE/UPDATE/DELETE/CONNECT", op)
+			}
+			r.Operations = append(r.Operations, op)
+		}
+	}
+
+	r.Users = splitList(reArgs[5])
+
+	if reArgs[7] != "" {
+		percent, err := strconv.Atoi(strings.TrimSpace(reArgs[7]))
+		if err != nil {
+			return nil, fmt.Errorf("percent[%s] is not a int: %s", reArgs[7], err.Error())
+		}
+		if percent <= 0 || percent > 100 {
+			return nil, fmt.Errorf("percent[%d] is not in (0, 100]", percent)
+		}
+		r.Percent = percent
+	}
+
+	if r.Action == "" {
+		r.Action = ActionDeny
+	}
+
+	switch r.Action {
+	case ActionDeny:
+		r.Delay = ""
+		if r.Message == "" {
+			r.Message = defaultDenyMessage
+		}
+	case ActionDelay:
+		delay, err := r.GetDelay()
+		if err != nil {
+			return nil, err
+		}
+		if delay <= 0 || delay > maxDelay {
+			return nil, fmt.Errorf("delay[%s] is not in (0, %s]", r.Delay, maxDelay)
+		}
+	default:
+		return nil, fmt.Errorf("action[%s] is not in deny/delay", r.Action)
+	}
+
+	return r, nil
+}
+
+// GetDelay the delay is in format of v1alpha1.ConvertDuration
+func (r *Rule) GetDelay() (time.Duration, error) {
+	if r.Delay == "" {
+		return 0, fmt.Errorf("delay is empty")
+	}
+
+	delay, err := v1alpha1.ConvertDuration(r.Delay)
+	if err != nil {
+		return 0, fmt.Errorf("delay[%s] is invalid: %s", r.Delay, err.Error())
+	}
+
+	return delay, nil
+}
+
+// Match return true if the request is in the scope of the rule. The apiserver has filtered the requests
+// by the webhook rules already, the request is checked again because different versions of rules may coexist
+func (r *Rule) Match(req *admissionv1.AdmissionRequest) bool {
+	if operatorUser != "" && req.UserInfo.Username == operatorUser {
+		return false
+	}
+
+	if req.Resource.Group != r.Group || req.Resource.Resource != r.Resource {
+		return false
+	}
+
+	if r.Version != "*" && req.Resource.Version != r.Version {
+		return false
+	}
+
+	if len(r.Namespaces) != 0 && !inList(r.Namespaces, req.Namespace) {
+		return false
+	}
+
+	if !inList(r.Operations, string(req.Operation)) {
+		return false
+	}
+
+	if len(r.Users) != 0 && !inList(r.Users, req.UserInfo.Username) {
+		return false
+	}
+
+	return true
+}
+
+func splitList(str string) []string {
+	var re []string
+	for _, unit := range strings.Split(str, v1alpha1.ArgsListSplit) {
+		if unit = strings.TrimSpace(unit); unit != "" {
+			re = append(re, unit)
+		}
+	}
+
+	return re
+}
+
+func inList(list []string, target string) bool {
+	for _, unit := range list {
+		if unit == target {
+			return true
+		}
+	}
+
+	return false
+}
+
+// getOperatorUser get the service account from env, which is set by the downward api. If the env is absent,
+// the subject of the mounted service account token is used
+func getOperatorUser() string {
+	ns, sa := os.Getenv(namespaceEnv), os.Getenv(serviceAccountEnv)
+	if ns != "" && sa != "" {
+		return fmt.Sprintf("system:serviceaccount:%s:%s", ns, sa)
+	}
+
+	token, err := os.ReadFile(tokenFile)
+	if err != nil {
+		return ""
+	}
+
+	return getTokenSubject(string(token))
+}
+
+// getTokenSubject return the "sub" claim of a jwt token without verifying it
+func getTokenSubject(token string) string {
+	parts := strings.Split(strings.TrimSpace(token), ".")
+	if len(parts) != 3 {
+		return ""
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+
+	claims := &struct {
+		Subject string `json:"sub"`
+	}{}
+	if err := json.Unmarshal(payload, claims); err != nil {
+		return ""
+	}
+
+	return claims.Subject
+}
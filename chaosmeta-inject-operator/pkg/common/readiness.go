@@ -0,0 +1,49 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package common
+
+import (
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// HasReadinessGate return true if the pod declares the readiness gate of chaosmeta and carries the label of it,
+// the pods without the label are not watched by the operator
+func HasReadinessGate(pod *corev1.Pod) bool {
+	if pod.Labels[v1alpha1.ReadinessGateLabel] != "true" {
+		return false
+	}
+
+	for _, gate := range pod.Spec.ReadinessGates {
+		if gate.ConditionType == v1alpha1.ReadinessGateType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// GetPodConditionStatus return empty if the condition is not found
+func GetPodConditionStatus(pod *corev1.Pod, conditionType corev1.PodConditionType) corev1.ConditionStatus {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == conditionType {
+			return c.Status
+		}
+	}
+
+	return ""
+}
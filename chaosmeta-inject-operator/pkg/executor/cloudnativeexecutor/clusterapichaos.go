@@ -0,0 +1,74 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/apichaos"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"time"
+)
+
+const (
+	faultClusterAPIChaos = "apichaos"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.ClusterCloudTarget, faultClusterAPIChaos, &ClusterAPIChaosExecutor{})
+}
+
+// ClusterAPIChaosExecutor denies or delays the matched write requests of apiserver by a dynamic validating webhook
+// served by the webhook server of operator. See apichaos.ParseRule for the args
+type ClusterAPIChaosExecutor struct{}
+
+func (e *ClusterAPIChaosExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	rule, err := apichaos.ParseRule(uid, args)
+	if err != nil {
+		return "", fmt.Errorf("args error: %s", err.Error())
+	}
+
+	if err := apichaos.Register(ctx, rule); err != nil {
+		return "", fmt.Errorf("register api chaos error: %s", err.Error())
+	}
+
+	return apichaos.GetConfigName(uid), nil
+}
+
+func (e *ClusterAPIChaosExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	if err := apichaos.Unregister(ctx, uid); err != nil {
+		return fmt.Errorf("unregister api chaos error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (e *ClusterAPIChaosExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	if phase == v1alpha1.InjectPhaseType {
+		if err := apichaos.Sync(ctx, uid); err != nil {
+			return nil, fmt.Errorf("sync api chaos error: %s", err.Error())
+		}
+	}
+
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		Message:    apichaos.GetStatistic(uid),
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
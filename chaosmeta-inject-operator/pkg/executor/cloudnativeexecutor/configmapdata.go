@@ -0,0 +1,406 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"math/rand"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	faultDataSet     = "set"
+	faultDataDelete  = "delete"
+	faultDataCorrupt = "corrupt"
+	faultDataReplace = "replace"
+
+	corruptModeGarbage  = "garbage"
+	corruptModeTruncate = "truncate"
+	corruptModeEmpty    = "empty"
+
+	dataBackupPrefix        = "chaosmeta-backup-"
+	dataBackupKey           = "backup"
+	dataBackupLabel         = "chaosmeta.io/backup"
+	maxInlineDataBackupSize = 16 * 1024
+)
+
+func init() {
+	for _, fault := range []string{faultDataSet, faultDataDelete, faultDataCorrupt, faultDataReplace} {
+		registerCloudExecutor(v1alpha1.ConfigMapCloudTarget, fault, &ConfigMapDataExecutor{fault: fault})
+	}
+}
+
+// ConfigMapDataExecutor changes the data of configmap, "set" and "replace" use "data" in json object format,
+// "delete" and "corrupt" use "key" in list format, all the keys are corrupted if "key" is empty.
+// The corrupt "mode" is garbage(default), truncate or empty
+type ConfigMapDataExecutor struct {
+	fault string
+}
+
+// dataBackup is the original data of configmap or secret. The data is saved in a secret named BackupObject in the
+// same namespace if it is too large for the status of experiment, and always for secret, whose data must not be
+// exposed in the status. InjectedDigest identifies the data written by inject, the recovery is refused only if the data
+// is changed by others after inject
+type dataBackup struct {
+	InjectedDigest string            `json:"injectedDigest"`
+	BackupObject   string            `json:"backupObject,omitempty"`
+	StringData     map[string]string `json:"stringData,omitempty"`
+	ByteData       map[string][]byte `json:"byteData,omitempty"`
+}
+
+type dataFaultArgs struct {
+	Data map[string]string
+	Keys []string
+	Mode string
+}
+
+func (e *ConfigMapDataExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseConfigMapInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected configmap format: %s", err.Error())
+	}
+
+	dArgs, err := parseDataFaultArgs(e.fault, args)
+	if err != nil {
+		return "", fmt.Errorf("args error: %s", err.Error())
+	}
+
+	c, cm := restclient.GetApiServerClientMap(v1alpha1.ConfigMapCloudTarget), &corev1.ConfigMap{}
+	if err := c.Get().Namespace(ns).Resource("configmaps").Name(name).Do(ctx).Into(cm); err != nil {
+		return "", fmt.Errorf("get configmap error: %s", err.Error())
+	}
+
+	if cm.Immutable != nil && *cm.Immutable {
+		return "", fmt.Errorf("configmap[%s] is immutable", name)
+	}
+
+	merged := make(map[string][]byte)
+	for k, v := range cm.Data {
+		merged[k] = []byte(v)
+	}
+	for k, v := range cm.BinaryData {
+		merged[k] = v
+	}
+
+	newData, err := applyDataFault(merged, e.fault, dArgs)
+	if err != nil {
+		return "", err
+	}
+
+	backup := &dataBackup{StringData: cm.Data, ByteData: cm.BinaryData}
+	cm.Data, cm.BinaryData = splitConfigMapData(newData, cm.BinaryData)
+
+	owner := metav1.OwnerReference{APIVersion: "v1", Kind: "ConfigMap", Name: cm.Name, UID: cm.UID}
+	return injectDataWithBackup(ctx, ns, uid, owner, backup, false, func() (string, error) {
+		if err := c.Put().Namespace(ns).Resource("configmaps").Name(name).Body(cm).Do(ctx).Into(cm); err != nil {
+			return "", fmt.Errorf("update configmap error: %s", err.Error())
+		}
+		return dataDigest(cm.Data, cm.BinaryData), nil
+	})
+}
+
+func (e *ConfigMapDataExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	ns, name, err := model.ParseConfigMapInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected configmap format: %s", err.Error())
+	}
+
+	backupInfo, err := loadDataBackup(ctx, ns, backup)
+	if err != nil {
+		return err
+	}
+
+	c, cm := restclient.GetApiServerClientMap(v1alpha1.ConfigMapCloudTarget), &corev1.ConfigMap{}
+	if err := c.Get().Namespace(ns).Resource("configmaps").Name(name).Do(ctx).Into(cm); err != nil {
+		return fmt.Errorf("get configmap error: %s", err.Error())
+	}
+
+	needRestore, err := checkDataConflict(dataDigest(cm.Data, cm.BinaryData), backupInfo)
+	if err != nil {
+		return err
+	}
+
+	// the update is rejected with conflict if the configmap is changed after the check
+	if needRestore {
+		cm.Data, cm.BinaryData = backupInfo.StringData, backupInfo.ByteData
+		if err := c.Put().Namespace(ns).Resource("configmaps").Name(name).Body(cm).Do(ctx).Error(); err != nil {
+			return fmt.Errorf("restore configmap error: %s", err.Error())
+		}
+	}
+
+	return deleteNamespacedObject(ctx, ns, "secrets", backupInfo.BackupObject)
+}
+
+func (e *ConfigMapDataExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
+
+// splitConfigMapData the keys of old binaryData are kept in binaryData, others are put in data
+func splitConfigMapData(merged map[string][]byte, oldBinary map[string][]byte) (map[string]string, map[string][]byte) {
+	var (
+		data   map[string]string
+		binary map[string][]byte
+	)
+	for k, v := range merged {
+		if _, ok := oldBinary[k]; ok {
+			if binary == nil {
+				binary = make(map[string][]byte)
+			}
+			binary[k] = v
+			continue
+		}
+
+		if data == nil {
+			data = make(map[string]string)
+		}
+		data[k] = string(v)
+	}
+
+	return data, binary
+}
+
+func parseDataFaultArgs(fault string, args []v1alpha1.ArgsUnit) (*dataFaultArgs, error) {
+	reArgs := common.GetArgs(args, []string{"data", "key", "mode"})
+	re := &dataFaultArgs{Mode: corruptModeGarbage}
+	if reArgs[1] != "" {
+		for _, k := range strings.Split(reArgs[1], v1alpha1.ArgsListSplit) {
+			if k = strings.TrimSpace(k); k != "" {
+				re.Keys = append(re.Keys, k)
+			}
+		}
+	}
+
+	switch fault {
+	case faultDataSet, faultDataReplace:
+		if reArgs[0] == "" {
+			return nil, fmt.Errorf("data is empty")
+		}
+		if err := json.Unmarshal([]byte(reArgs[0]), &re.Data); err != nil {
+			return nil, fmt.Errorf("data is not a json object of string: %s", err.Error())
+		}
+		if fault == faultDataSet && len(re.Data) == 0 {
+			return nil, fmt.Errorf("data has no key")
+		}
+	case faultDataDelete:
+		if len(re.Keys) == 0 {
+			return nil, fmt.Errorf("key is empty")
+		}
+	case faultDataCorrupt:
+		if reArgs[2] != "" {
+			re.Mode = reArgs[2]
+		}
+		if re.Mode != corruptModeGarbage && re.Mode != corruptModeTruncate && re.Mode != corruptModeEmpty {
+			return nil, fmt.Errorf("mode[%s] is not one of: %s, %s, %s", re.Mode, corruptModeGarbage, corruptModeTruncate, corruptModeEmpty)
+		}
+	default:
+		return nil, fmt.Errorf("not support fault: %s", fault)
+	}
+
+	return re, nil
+}
+
+// applyDataFault return the new data, the old data is not changed
+func applyDataFault(oldData map[string][]byte, fault string, dArgs *dataFaultArgs) (map[string][]byte, error) {
+	re := make(map[string][]byte)
+	if fault == faultDataReplace {
+		for k, v := range dArgs.Data {
+			re[k] = []byte(v)
+		}
+		return re, nil
+	}
+
+	for k, v := range oldData {
+		re[k] = v
+	}
+
+	switch fault {
+	case faultDataSet:
+		for k, v := range dArgs.Data {
+			re[k] = []byte(v)
+		}
+	case faultDataDelete:
+		for _, k := range dArgs.Keys {
+			if _, ok := re[k]; !ok {
+				return nil, fmt.Errorf("key[%s] is not found", k)
+			}
+			delete(re, k)
+		}
+	case faultDataCorrupt:
+		keys := dArgs.Keys
+		if len(keys) == 0 {
+			for k := range re {
+				keys = append(keys, k)
+			}
+			sort.Strings(keys)
+		}
+
+		for _, k := range keys {
+			v, ok := re[k]
+			if !ok {
+				return nil, fmt.Errorf("key[%s] is not found", k)
+			}
+			re[k] = corruptValue(v, dArgs.Mode)
+		}
+	}
+
+	return re, nil
+}
+
+func corruptValue(v []byte, mode string) []byte {
+	switch mode {
+	case corruptModeTruncate:
+		return v[:len(v)/2]
+	case corruptModeEmpty:
+		return []byte{}
+	default:
+		const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!@#$%^&*()"
+		re := make([]byte, len(v))
+		if len(re) == 0 {
+			re = make([]byte, 8)
+		}
+		for i := range re {
+			re[i] = letters[rand.Intn(len(letters))]
+		}
+		return re
+	}
+}
+
+// injectDataWithBackup the large backup, or any backup if inObject, is saved in a secret before update, and removed if the
+// update failed. The secret is owned by the target object, so it is removed with the object if the experiment is never
+// recovered. update returns the digest of the data written
+func injectDataWithBackup(ctx context.Context, ns, uid string, owner metav1.OwnerReference, backup *dataBackup, inObject bool, update func() (string, error)) (string, error) {
+	backupBytes, err := json.Marshal(backup)
+	if err != nil {
+		return "", fmt.Errorf("backup to string error: %s", err.Error())
+	}
+
+	re := &dataBackup{}
+	if inObject || len(backupBytes) > maxInlineDataBackupSize {
+		re.BackupObject = dataBackupPrefix + uid
+		backupObject := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            re.BackupObject,
+				Namespace:       ns,
+				Labels:          map[string]string{dataBackupLabel: uid},
+				OwnerReferences: []metav1.OwnerReference{owner},
+			},
+			Data: map[string][]byte{dataBackupKey: backupBytes},
+		}
+		if err := restclient.GetApiServerClientMap(v1alpha1.SecretCloudTarget).Post().Namespace(ns).
+			Resource("secrets").Body(backupObject).Do(ctx).Error(); err != nil {
+			return "", fmt.Errorf("create backup object error: %s", err.Error())
+		}
+	} else {
+		re.StringData, re.ByteData = backup.StringData, backup.ByteData
+	}
+
+	if re.InjectedDigest, err = update(); err != nil {
+		if deleteErr := deleteNamespacedObject(ctx, ns, "secrets", re.BackupObject); deleteErr != nil {
+			return "", fmt.Errorf("%s, and %s", err.Error(), deleteErr.Error())
+		}
+		return "", err
+	}
+
+	reBytes, err := json.Marshal(re)
+	if err != nil {
+		return "", fmt.Errorf("backup to string error: %s", err.Error())
+	}
+
+	return string(reBytes), nil
+}
+
+// loadDataBackup the data is read from the backup object if it is saved in a secret
+func loadDataBackup(ctx context.Context, ns, backup string) (*dataBackup, error) {
+	re := &dataBackup{}
+	if err := json.Unmarshal([]byte(backup), re); err != nil {
+		return nil, fmt.Errorf("backup is not a json: %s", err.Error())
+	}
+
+	if re.BackupObject == "" {
+		return re, nil
+	}
+
+	backupObject := &corev1.Secret{}
+	if err := restclient.GetApiServerClientMap(v1alpha1.SecretCloudTarget).Get().Namespace(ns).Resource("secrets").
+		Name(re.BackupObject).Do(ctx).Into(backupObject); err != nil {
+		return nil, fmt.Errorf("get backup object error: %s", err.Error())
+	}
+
+	data := &dataBackup{}
+	if err := json.Unmarshal(backupObject.Data[dataBackupKey], data); err != nil {
+		return nil, fmt.Errorf("data of backup object is not a json: %s", err.Error())
+	}
+	re.StringData, re.ByteData = data.StringData, data.ByteData
+
+	return re, nil
+}
+
+// checkDataConflict return false if the data has been restored by others, and error if the data is changed to others
+// after inject, which is not overwritten
+func checkDataConflict(nowDigest string, backup *dataBackup) (bool, error) {
+	switch nowDigest {
+	case backup.InjectedDigest:
+		return true, nil
+	case dataDigest(backup.StringData, backup.ByteData):
+		return false, nil
+	default:
+		return false, fmt.Errorf("data of object is changed by others after inject, restore is skipped to keep the change")
+	}
+}
+
+// dataDigest is the sha256 of data in the order of keys, empty and nil data are the same
+func dataDigest(stringData map[string]string, byteData map[string][]byte) string {
+	h := sha256.New()
+	writeSorted := func(kind string, keys []string, value func(string) []byte) {
+		sort.Strings(keys)
+		for _, k := range keys {
+			v := value(k)
+			_, _ = fmt.Fprintf(h, "%s%d:%s%d:", kind, len(k), k, len(v))
+			_, _ = h.Write(v)
+		}
+	}
+
+	var keys []string
+	for k := range stringData {
+		keys = append(keys, k)
+	}
+	writeSorted("s", keys, func(k string) []byte { return []byte(stringData[k]) })
+
+	keys = nil
+	for k := range byteData {
+		keys = append(keys, k)
+	}
+	writeSorted("b", keys, func(k string) []byte { return byteData[k] })
+
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"testing"
+)
+
+func Test_parseDataFaultArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		fault   string
+		args    []v1alpha1.ArgsUnit
+		want    *dataFaultArgs
+		wantErr bool
+	}{
+		{
+			name:  "set",
+			fault: faultDataSet,
+			args:  []v1alpha1.ArgsUnit{{Key: "data", Value: `{"a":"x=1,y=2"}`}},
+			want:  &dataFaultArgs{Data: map[string]string{"a": "x=1,y=2"}, Mode: corruptModeGarbage},
+		},
+		{name: "set_empty", fault: faultDataSet, args: []v1alpha1.ArgsUnit{{Key: "data", Value: `{}`}}, wantErr: true},
+		{name: "set_not_json", fault: faultDataSet, args: []v1alpha1.ArgsUnit{{Key: "data", Value: `a=b`}}, wantErr: true},
+		{
+			name:  "replace_empty",
+			fault: faultDataReplace,
+			args:  []v1alpha1.ArgsUnit{{Key: "data", Value: `{}`}},
+			want:  &dataFaultArgs{Data: map[string]string{}, Mode: corruptModeGarbage},
+		},
+		{
+			name:  "delete",
+			fault: faultDataDelete,
+			args:  []v1alpha1.ArgsUnit{{Key: "key", Value: "a, b"}},
+			want:  &dataFaultArgs{Keys: []string{"a", "b"}, Mode: corruptModeGarbage},
+		},
+		{name: "delete_no_key", fault: faultDataDelete, wantErr: true},
+		{
+			name:  "corrupt",
+			fault: faultDataCorrupt,
+			args:  []v1alpha1.ArgsUnit{{Key: "mode", Value: corruptModeTruncate}},
+			want:  &dataFaultArgs{Mode: corruptModeTruncate},
+		},
+		{name: "corrupt_bad_mode", fault: faultDataCorrupt, args: []v1alpha1.ArgsUnit{{Key: "mode", Value: "x"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := parseDataFaultArgs(tt.fault, tt.args)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, re)
+		})
+	}
+}
+
+func Test_applyDataFault(t *testing.T) {
+	oldData := map[string][]byte{"a": []byte("1234"), "b": []byte("xy")}
+	tests := []struct {
+		name    string
+		fault   string
+		args    *dataFaultArgs
+		want    map[string][]byte
+		wantErr bool
+	}{
+		{
+			name:  "set",
+			fault: faultDataSet,
+			args:  &dataFaultArgs{Data: map[string]string{"a": "new", "c": "3"}},
+			want:  map[string][]byte{"a": []byte("new"), "b": []byte("xy"), "c": []byte("3")},
+		},
+		{
+			name:  "delete",
+			fault: faultDataDelete,
+			args:  &dataFaultArgs{Keys: []string{"a"}},
+			want:  map[string][]byte{"b": []byte("xy")},
+		},
+		{name: "delete_not_found", fault: faultDataDelete, args: &dataFaultArgs{Keys: []string{"c"}}, wantErr: true},
+		{
+			name:  "replace",
+			fault: faultDataReplace,
+			args:  &dataFaultArgs{Data: map[string]string{"c": "3"}},
+			want:  map[string][]byte{"c": []byte("3")},
+		},
+		{
+			name:  "truncate_all",
+			fault: faultDataCorrupt,
+			args:  &dataFaultArgs{Mode: corruptModeTruncate},
+			want:  map[string][]byte{"a": []byte("12"), "b": []byte("x")},
+		},
+		{
+			name:  "empty_one",
+			fault: faultDataCorrupt,
+			args:  &dataFaultArgs{Keys: []string{"b"}, Mode: corruptModeEmpty},
+			want:  map[string][]byte{"a": []byte("1234"), "b": {}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := applyDataFault(oldData, tt.fault, tt.args)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, re)
+			assert.Equal(t, map[string][]byte{"a": []byte("1234"), "b": []byte("xy")}, oldData)
+		})
+	}
+
+	re, err := applyDataFault(oldData, faultDataCorrupt, &dataFaultArgs{Keys: []string{"a"}, Mode: corruptModeGarbage})
+	assert.NoError(t, err)
+	assert.Len(t, re["a"], 4)
+	assert.Len(t, corruptValue(nil, corruptModeGarbage), 8)
+}
+
+func Test_splitConfigMapData(t *testing.T) {
+	data, binary := splitConfigMapData(map[string][]byte{"a": []byte("1"), "bin": {0xff}}, map[string][]byte{"bin": {0x00}})
+	assert.Equal(t, map[string]string{"a": "1"}, data)
+	assert.Equal(t, map[string][]byte{"bin": {0xff}}, binary)
+
+	data, binary = splitConfigMapData(map[string][]byte{}, nil)
+	assert.Nil(t, data)
+	assert.Nil(t, binary)
+}
+
+func Test_checkDataConflict(t *testing.T) {
+	original := &dataBackup{StringData: map[string]string{"a": "1"}, ByteData: map[string][]byte{"bin": {0x00}}}
+	original.InjectedDigest = dataDigest(map[string]string{"a": "2"}, map[string][]byte{"bin": {0x00}})
+	tests := []struct {
+		name        string
+		stringData  map[string]string
+		byteData    map[string][]byte
+		wantRestore bool
+		wantErr     bool
+	}{
+		{name: "injected", stringData: map[string]string{"a": "2"}, byteData: map[string][]byte{"bin": {0x00}}, wantRestore: true},
+		{name: "restored_by_others", stringData: map[string]string{"a": "1"}, byteData: map[string][]byte{"bin": {0x00}}, wantRestore: false},
+		{name: "changed_by_others", stringData: map[string]string{"a": "3"}, byteData: map[string][]byte{"bin": {0x00}}, wantErr: true},
+		{name: "key_added_by_others", stringData: map[string]string{"a": "2", "b": ""}, byteData: map[string][]byte{"bin": {0x00}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			restore, err := checkDataConflict(dataDigest(tt.stringData, tt.byteData), original)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantRestore, restore)
+		})
+	}
+}
+
+func Test_dataDigest(t *testing.T) {
+	assert.Equal(t, dataDigest(nil, nil), dataDigest(map[string]string{}, map[string][]byte{}))
+	assert.Equal(t, dataDigest(map[string]string{"a": "1", "b": "2"}, nil), dataDigest(map[string]string{"b": "2", "a": "1"}, nil))
+	assert.NotEqual(t, dataDigest(map[string]string{"a": "1"}, nil), dataDigest(nil, map[string][]byte{"a": []byte("1")}))
+	assert.NotEqual(t, dataDigest(map[string]string{"ab": "c"}, nil), dataDigest(map[string]string{"a": "bc"}, nil))
+}
@@ -0,0 +1,177 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"strings"
+	"time"
+)
+
+const (
+	faultDaemonSetPodDelete = "poddelete"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.DaemonsetCloudTarget, faultDaemonSetPodDelete, &DaemonSetPodDeleteExecutor{})
+}
+
+// DaemonSetPodDeleteExecutor deletes the pods of daemonset on the selected nodes. The nodes are selected by
+// "nodename" or "nodelabel", all the nodes running the daemonset are selected if both are empty
+type DaemonSetPodDeleteExecutor struct{}
+
+func (e *DaemonSetPodDeleteExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseDaemonSetInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected daemonset format: %s", err.Error())
+	}
+
+	nodeNames, nodeLabel, err := parseNodeFilterArgs(args)
+	if err != nil {
+		return "", fmt.Errorf("args error: %s", err.Error())
+	}
+
+	ds := &v1.DaemonSet{}
+	if err := restclient.GetApiServerClientMap(v1alpha1.DaemonsetCloudTarget).Get().Namespace(ns).
+		Resource("daemonsets").Name(name).Do(ctx).Into(ds); err != nil {
+		return "", fmt.Errorf("get daemonset error: %s", err.Error())
+	}
+
+	podSelector, err := metav1.LabelSelectorAsSelector(ds.Spec.Selector)
+	if err != nil {
+		return "", fmt.Errorf("convert selector of daemonset error: %s", err.Error())
+	}
+
+	podList := &corev1.PodList{}
+	if err := restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget).Get().Namespace(ns).Resource("pods").
+		Param("labelSelector", podSelector.String()).Do(ctx).Into(podList); err != nil {
+		return "", fmt.Errorf("list pods of daemonset error: %s", err.Error())
+	}
+
+	if len(nodeLabel) != 0 {
+		nodeList := &corev1.NodeList{}
+		if err := restclient.GetApiServerClientMap(v1alpha1.NodeCloudTarget).Get().Resource("nodes").
+			Param("labelSelector", labels.SelectorFromSet(nodeLabel).String()).Do(ctx).Into(nodeList); err != nil {
+			return "", fmt.Errorf("list nodes by label error: %s", err.Error())
+		}
+
+		if len(nodeList.Items) == 0 {
+			return "", fmt.Errorf("no node matches label: %v", nodeLabel)
+		}
+
+		for _, node := range nodeList.Items {
+			nodeNames = append(nodeNames, node.Name)
+		}
+	}
+
+	plan := filterDaemonSetPods(podList.Items, ds.UID, nodeNames)
+	if len(plan) == 0 {
+		return "", fmt.Errorf("no pod of daemonset[%s] on the selected nodes", name)
+	}
+
+	backupBytes, err := json.Marshal(plan)
+	if err != nil {
+		return "", fmt.Errorf("backup to string error: %s", err.Error())
+	}
+
+	for _, unit := range plan {
+		if err := deletePodWithUID(ctx, ns, unit); err != nil {
+			return "", err
+		}
+	}
+
+	return string(backupBytes), nil
+}
+
+// Recover the deleted pods are recreated by the daemonset controller, nothing to restore
+func (e *DaemonSetPodDeleteExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	return nil
+}
+
+func (e *DaemonSetPodDeleteExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
+
+// filterDaemonSetPods return the pods owned by the daemonset on the nodes, empty nodes means all nodes
+func filterDaemonSetPods(pods []corev1.Pod, dsUID types.UID, nodeNames []string) []rollingDeleteUnit {
+	nodeMap := make(map[string]bool)
+	for _, n := range nodeNames {
+		nodeMap[n] = true
+	}
+
+	var re []rollingDeleteUnit
+	for _, pod := range pods {
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		owner := metav1.GetControllerOf(&pod)
+		if owner == nil || owner.Kind != "DaemonSet" || owner.UID != dsUID {
+			continue
+		}
+
+		if len(nodeMap) != 0 && !nodeMap[pod.Spec.NodeName] {
+			continue
+		}
+
+		re = append(re, rollingDeleteUnit{Name: pod.Name, UID: string(pod.UID)})
+	}
+
+	return re
+}
+
+// parseNodeFilterArgs "nodename" is a list of node names, "nodelabel" is a list of k=v
+func parseNodeFilterArgs(args []v1alpha1.ArgsUnit) ([]string, map[string]string, error) {
+	reArgs := common.GetArgs(args, []string{"nodename", "nodelabel"})
+	var nodeNames []string
+	if reArgs[0] != "" {
+		for _, n := range strings.Split(reArgs[0], v1alpha1.ArgsListSplit) {
+			if n = strings.TrimSpace(n); n != "" {
+				nodeNames = append(nodeNames, n)
+			}
+		}
+	}
+
+	var nodeLabel map[string]string
+	if reArgs[1] != "" {
+		nodeLabel = make(map[string]string)
+		for _, kv := range strings.Split(reArgs[1], v1alpha1.ArgsListSplit) {
+			kvList := strings.Split(kv, v1alpha1.LabelListSplit)
+			if len(kvList) != 2 || strings.TrimSpace(kvList[0]) == "" {
+				return nil, nil, fmt.Errorf("nodelabel[%s] is not in format: k=v", kv)
+			}
+			nodeLabel[strings.TrimSpace(kvList[0])] = strings.TrimSpace(kvList[1])
+		}
+	}
+
+	return nodeNames, nodeLabel, nil
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"testing"
+)
+
+func Test_parseNodeFilterArgs(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []v1alpha1.ArgsUnit
+		wantNames []string
+		wantLabel map[string]string
+		wantErr   bool
+	}{
+		{name: "empty", args: nil, wantNames: nil, wantLabel: nil},
+		{
+			name:      "name",
+			args:      []v1alpha1.ArgsUnit{{Key: "nodename", Value: "node1, node2,"}},
+			wantNames: []string{"node1", "node2"},
+		},
+		{
+			name:      "label",
+			args:      []v1alpha1.ArgsUnit{{Key: "nodelabel", Value: "zone=a,role=edge"}},
+			wantLabel: map[string]string{"zone": "a", "role": "edge"},
+		},
+		{name: "bad_label", args: []v1alpha1.ArgsUnit{{Key: "nodelabel", Value: "zone"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names, label, err := parseNodeFilterArgs(tt.args)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantNames, names)
+			assert.Equal(t, tt.wantLabel, label)
+		})
+	}
+}
+
+func Test_filterDaemonSetPods(t *testing.T) {
+	isController := true
+	newPod := func(name, node string, owner types.UID, deleting bool) corev1.Pod {
+		pod := corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, UID: types.UID(name + "-uid")},
+			Spec:       corev1.PodSpec{NodeName: node},
+		}
+		if owner != "" {
+			pod.OwnerReferences = []metav1.OwnerReference{{Kind: "DaemonSet", UID: owner, Controller: &isController}}
+		}
+		if deleting {
+			now := metav1.Now()
+			pod.DeletionTimestamp = &now
+		}
+		return pod
+	}
+
+	pods := []corev1.Pod{
+		newPod("ds-a", "node1", "ds", false),
+		newPod("ds-b", "node2", "ds", false),
+		newPod("ds-c", "node3", "ds", true),
+		newPod("other", "node1", "other", false),
+		newPod("bare", "node1", "", false),
+	}
+
+	tests := []struct {
+		name      string
+		nodeNames []string
+		want      []rollingDeleteUnit
+	}{
+		{
+			name: "all_nodes",
+			want: []rollingDeleteUnit{{Name: "ds-a", UID: "ds-a-uid"}, {Name: "ds-b", UID: "ds-b-uid"}},
+		},
+		{
+			name:      "selected_nodes",
+			nodeNames: []string{"node2", "node3"},
+			want:      []rollingDeleteUnit{{Name: "ds-b", UID: "ds-b-uid"}},
+		},
+		{name: "no_pod", nodeNames: []string{"node4"}, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, filterDaemonSetPods(pods, "ds", tt.nodeNames))
+		})
+	}
+}
@@ -0,0 +1,68 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"github.com/stretchr/testify/assert"
+	batchv1 "k8s.io/api/batch/v1"
+	"testing"
+)
+
+func Test_jobDeadlineBackup(t *testing.T) {
+	deadline := int64(600)
+	tests := []struct {
+		name string
+		job  *batchv1.Job
+		want string
+	}{
+		{name: "unset", job: &batchv1.Job{}, want: jobDeadlineUnset},
+		{name: "set", job: &batchv1.Job{Spec: batchv1.JobSpec{ActiveDeadlineSeconds: &deadline}}, want: "600"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backup := getJobDeadlineBackup(tt.job)
+			re, err := parseJobDeadlineBackup(backup)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, re)
+		})
+	}
+
+	_, err := parseJobDeadlineBackup("abc")
+	assert.Error(t, err)
+}
+
+func Test_jobSuspendBackup(t *testing.T) {
+	suspend, notSuspend := true, false
+	tests := []struct {
+		name string
+		job  *batchv1.Job
+		want bool
+	}{
+		{name: "unset", job: &batchv1.Job{}, want: false},
+		{name: "not_suspend", job: &batchv1.Job{Spec: batchv1.JobSpec{Suspend: &notSuspend}}, want: false},
+		{name: "suspend", job: &batchv1.Job{Spec: batchv1.JobSpec{Suspend: &suspend}}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := parseJobSuspendBackup(getJobSuspendBackup(tt.job))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, re)
+		})
+	}
+
+	_, err := parseJobSuspendBackup("")
+	assert.Error(t, err)
+}
@@ -0,0 +1,149 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"strconv"
+	"time"
+)
+
+const (
+	faultJobFail = "fail"
+
+	jobFailDeadlineSeconds = 1
+	jobDeadlineUnset       = "null"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.JobCloudTarget, faultJobFail, &JobFailExecutor{})
+}
+
+// JobFailExecutor makes the job failed with reason DeadlineExceeded by shortening its activeDeadlineSeconds.
+// The activeDeadlineSeconds is restored in recover, but a failed job is terminal in kubernetes and stays failed,
+// it has to be recreated by its owner, e.g. the cronjob
+type JobFailExecutor struct{}
+
+func (e *JobFailExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseJobInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected job format: %s", err.Error())
+	}
+
+	c, job := restclient.GetApiServerClientMap(v1alpha1.JobCloudTarget), &batchv1.Job{}
+	if err := c.Get().Namespace(ns).Resource("jobs").Name(name).Do(ctx).Into(job); err != nil {
+		return "", fmt.Errorf("get job error: %s", err.Error())
+	}
+
+	if isJobFinished(job) {
+		return "", fmt.Errorf("job[%s] is already finished", name)
+	}
+
+	return getJobDeadlineBackup(job), patchJobDeadline(ctx, ns, name, strconv.Itoa(jobFailDeadlineSeconds))
+}
+
+func (e *JobFailExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	ns, name, err := model.ParseJobInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected job format: %s", err.Error())
+	}
+
+	deadline, err := parseJobDeadlineBackup(backup)
+	if err != nil {
+		return err
+	}
+
+	return patchJobDeadline(ctx, ns, name, deadline)
+}
+
+// Query waits for the job to be failed in inject phase
+func (e *JobFailExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	re := &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}
+	if phase != v1alpha1.InjectPhaseType {
+		return re, nil
+	}
+
+	ns, name, err := model.ParseJobInfo(injectObject)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected job format: %s", err.Error())
+	}
+
+	job := &batchv1.Job{}
+	if err := restclient.GetApiServerClientMap(v1alpha1.JobCloudTarget).Get().Namespace(ns).
+		Resource("jobs").Name(name).Do(ctx).Into(job); err != nil {
+		return nil, fmt.Errorf("get job error: %s", err.Error())
+	}
+
+	if !isJobFinished(job) {
+		re.Status, re.Message = v1alpha1.RunningStatusType, fmt.Sprintf("waiting for job[%s] to be failed", name)
+	}
+
+	return re, nil
+}
+
+// getJobDeadlineBackup the backup is the old activeDeadlineSeconds, or "null" if it is not set
+func getJobDeadlineBackup(job *batchv1.Job) string {
+	if job.Spec.ActiveDeadlineSeconds == nil {
+		return jobDeadlineUnset
+	}
+
+	return strconv.FormatInt(*job.Spec.ActiveDeadlineSeconds, 10)
+}
+
+// parseJobDeadlineBackup return the value of activeDeadlineSeconds in the merge patch to restore the backup
+func parseJobDeadlineBackup(backup string) (string, error) {
+	if backup == jobDeadlineUnset {
+		return backup, nil
+	}
+
+	if _, err := strconv.ParseInt(backup, 10, 64); err != nil {
+		return "", fmt.Errorf("old activeDeadlineSeconds is not a num: %s", err.Error())
+	}
+
+	return backup, nil
+}
+
+func patchJobDeadline(ctx context.Context, ns, name, deadline string) error {
+	if err := restclient.GetApiServerClientMap(v1alpha1.JobCloudTarget).Patch(types.MergePatchType).Namespace(ns).
+		Resource("jobs").Name(name).Body([]byte(fmt.Sprintf(`{"spec":{"activeDeadlineSeconds":%s}}`, deadline))).
+		Do(ctx).Error(); err != nil {
+		return fmt.Errorf("patch job error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func isJobFinished(job *batchv1.Job) bool {
+	for _, c := range job.Status.Conditions {
+		if (c.Type == batchv1.JobComplete || c.Type == batchv1.JobFailed) && c.Status == corev1.ConditionTrue {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	batchv1 "k8s.io/api/batch/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"strconv"
+	"time"
+)
+
+const (
+	faultJobSuspend = "suspend"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.JobCloudTarget, faultJobSuspend, &JobSuspendExecutor{})
+}
+
+// JobSuspendExecutor suspends the job, the active pods are terminated and no new pod is created until recovered
+type JobSuspendExecutor struct{}
+
+func (e *JobSuspendExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseJobInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected job format: %s", err.Error())
+	}
+
+	c, job := restclient.GetApiServerClientMap(v1alpha1.JobCloudTarget), &batchv1.Job{}
+	if err := c.Get().Namespace(ns).Resource("jobs").Name(name).Do(ctx).Into(job); err != nil {
+		return "", fmt.Errorf("get job error: %s", err.Error())
+	}
+
+	backup := getJobSuspendBackup(job)
+	if job.Spec.Suspend != nil && *job.Spec.Suspend {
+		return backup, nil
+	}
+
+	return backup, patchJobSuspend(ctx, ns, name, true)
+}
+
+func (e *JobSuspendExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	ns, name, err := model.ParseJobInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected job format: %s", err.Error())
+	}
+
+	oldSuspend, err := parseJobSuspendBackup(backup)
+	if err != nil {
+		return err
+	}
+
+	if oldSuspend {
+		return nil
+	}
+
+	return patchJobSuspend(ctx, ns, name, false)
+}
+
+func (e *JobSuspendExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
+
+// getJobSuspendBackup the backup is the old suspend, an unset suspend is false
+func getJobSuspendBackup(job *batchv1.Job) string {
+	return strconv.FormatBool(job.Spec.Suspend != nil && *job.Spec.Suspend)
+}
+
+func parseJobSuspendBackup(backup string) (bool, error) {
+	oldSuspend, err := strconv.ParseBool(backup)
+	if err != nil {
+		return false, fmt.Errorf("old suspend is not a bool: %s", err.Error())
+	}
+
+	return oldSuspend, nil
+}
+
+func patchJobSuspend(ctx context.Context, ns, name string, suspend bool) error {
+	if err := restclient.GetApiServerClientMap(v1alpha1.JobCloudTarget).Patch(types.MergePatchType).Namespace(ns).
+		Resource("jobs").Name(name).Body([]byte(fmt.Sprintf(`{"spec":{"suspend":%t}}`, suspend))).Do(ctx).Error(); err != nil {
+		return fmt.Errorf("patch job error: %s", err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"testing"
+)
+
+func Test_parseLimitRangeArgs(t *testing.T) {
+	re, err := parseLimitRangeArgs(nil)
+	assert.NoError(t, err)
+	assert.Equal(t, defaultLimitRangeCPU, re.Cpu().String())
+	assert.Equal(t, defaultLimitRangeMemory, re.Memory().String())
+
+	re, err = parseLimitRangeArgs([]v1alpha1.ArgsUnit{{Key: "cpu", Value: "100m"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "100m", re.Cpu().String())
+
+	_, err = parseLimitRangeArgs([]v1alpha1.ArgsUnit{{Key: "memory", Value: "abc"}})
+	assert.Error(t, err)
+}
+
+func Test_parseQuotaArgs(t *testing.T) {
+	tests := []struct {
+		name    string
+		args    []v1alpha1.ArgsUnit
+		want    string
+		wantErr bool
+	}{
+		{name: "default", args: nil, want: defaultQuotaPods},
+		{name: "pods", args: []v1alpha1.ArgsUnit{{Key: "pods", Value: "3"}}, want: "3"},
+		{name: "invalid", args: []v1alpha1.ArgsUnit{{Key: "pods", Value: "abc"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := parseQuotaArgs(tt.args)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, re.String())
+		})
+	}
+}
+
+func Test_newNamespaceQuota(t *testing.T) {
+	pods, err := parseQuotaArgs([]v1alpha1.ArgsUnit{{Key: "pods", Value: "2"}})
+	assert.NoError(t, err)
+
+	quota := newNamespaceQuota("chaosmeta", "uid1", pods)
+	assert.Equal(t, "chaosmeta", quota.Namespace)
+	// the name is the backup, recover deletes the quota by it
+	assert.Equal(t, namespaceQuotaPrefix+"uid1", quota.Name)
+	hard := quota.Spec.Hard[corev1.ResourcePods]
+	assert.Equal(t, "2", hard.String())
+}
@@ -0,0 +1,108 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+const (
+	faultNamespaceLimitRange = "limitrange"
+
+	namespaceLimitRangePrefix = "chaosmeta-limitrange-"
+	defaultLimitRangeCPU      = "1m"
+	defaultLimitRangeMemory   = "4Mi"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.NamespaceCloudTarget, faultNamespaceLimitRange, &NamespaceLimitRangeExecutor{})
+}
+
+// NamespaceLimitRangeExecutor creates a LimitRange in the namespace with a tiny max of container "cpu" and "memory",
+// so that the new pods requesting more are rejected by the admission. The existing pods are not affected
+type NamespaceLimitRangeExecutor struct{}
+
+func (e *NamespaceLimitRangeExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	maxList, err := parseLimitRangeArgs(args)
+	if err != nil {
+		return "", fmt.Errorf("args error: %s", err.Error())
+	}
+
+	limitRange := &corev1.LimitRange{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      namespaceLimitRangePrefix + uid,
+			Namespace: injectObject,
+		},
+		Spec: corev1.LimitRangeSpec{
+			Limits: []corev1.LimitRangeItem{
+				{
+					Type: corev1.LimitTypeContainer,
+					Max:  maxList,
+				},
+			},
+		},
+	}
+
+	if err := restclient.GetApiServerClientMap(v1alpha1.NamespaceCloudTarget).Post().Namespace(injectObject).
+		Resource("limitranges").Body(limitRange).Do(ctx).Error(); err != nil {
+		return "", fmt.Errorf("create limitrange error: %s", err.Error())
+	}
+
+	return limitRange.Name, nil
+}
+
+func (e *NamespaceLimitRangeExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	return deleteNamespacedObject(ctx, injectObject, "limitranges", backup)
+}
+
+func (e *NamespaceLimitRangeExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
+
+func parseLimitRangeArgs(args []v1alpha1.ArgsUnit) (corev1.ResourceList, error) {
+	reArgs := common.GetArgs(args, []string{"cpu", "memory"})
+	defaults := []string{defaultLimitRangeCPU, defaultLimitRangeMemory}
+	names := []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory}
+
+	re := make(corev1.ResourceList)
+	for i, v := range reArgs {
+		if v == "" {
+			v = defaults[i]
+		}
+
+		q, err := resource.ParseQuantity(v)
+		if err != nil {
+			return nil, fmt.Errorf("%s[%s] is not a quantity: %s", names[i], v, err.Error())
+		}
+		re[names[i]] = q
+	}
+
+	return re, nil
+}
@@ -0,0 +1,116 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+const (
+	faultNamespaceQuota = "quota"
+
+	namespaceQuotaPrefix = "chaosmeta-quota-"
+	defaultQuotaPods     = "0"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.NamespaceCloudTarget, faultNamespaceQuota, &NamespaceQuotaExecutor{})
+}
+
+// NamespaceQuotaExecutor creates a ResourceQuota in the namespace, which limits the count of pods by "pods", 0 in
+// default, so that no new pod can be created. The existing pods are not affected
+type NamespaceQuotaExecutor struct{}
+
+func (e *NamespaceQuotaExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	podsQuantity, err := parseQuotaArgs(args)
+	if err != nil {
+		return "", fmt.Errorf("args error: %s", err.Error())
+	}
+
+	quota := newNamespaceQuota(injectObject, uid, podsQuantity)
+	if err := restclient.GetApiServerClientMap(v1alpha1.NamespaceCloudTarget).Post().Namespace(injectObject).
+		Resource("resourcequotas").Body(quota).Do(ctx).Error(); err != nil {
+		return "", fmt.Errorf("create resourcequota error: %s", err.Error())
+	}
+
+	return quota.Name, nil
+}
+
+func (e *NamespaceQuotaExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	return deleteNamespacedObject(ctx, injectObject, "resourcequotas", backup)
+}
+
+func (e *NamespaceQuotaExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
+
+func parseQuotaArgs(args []v1alpha1.ArgsUnit) (resource.Quantity, error) {
+	pods := common.GetArgs(args, []string{"pods"})[0]
+	if pods == "" {
+		pods = defaultQuotaPods
+	}
+
+	podsQuantity, err := resource.ParseQuantity(pods)
+	if err != nil {
+		return resource.Quantity{}, fmt.Errorf("pods[%s] is not a quantity: %s", pods, err.Error())
+	}
+
+	return podsQuantity, nil
+}
+
+// newNamespaceQuota the name of quota is the backup, which is deleted in recover
+func newNamespaceQuota(ns, uid string, pods resource.Quantity) *corev1.ResourceQuota {
+	return &corev1.ResourceQuota{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      namespaceQuotaPrefix + uid,
+			Namespace: ns,
+		},
+		Spec: corev1.ResourceQuotaSpec{
+			Hard: corev1.ResourceList{
+				corev1.ResourcePods: pods,
+			},
+		},
+	}
+}
+
+// deleteNamespacedObject the object created by inject is deleted, the name is the backup
+func deleteNamespacedObject(ctx context.Context, ns, resourceName, name string) error {
+	if name == "" {
+		return nil
+	}
+
+	err := restclient.GetApiServerClientMap(v1alpha1.NamespaceCloudTarget).Delete().Namespace(ns).
+		Resource(resourceName).Name(name).Do(ctx).Error()
+	if err != nil && !common.IsNotFoundErr(err) {
+		return fmt.Errorf("delete %s[%s] error: %s", resourceName, name, err.Error())
+	}
+
+	return nil
+}
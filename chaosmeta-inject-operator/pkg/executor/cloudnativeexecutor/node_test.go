@@ -0,0 +1,84 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+)
+
+func Test_parseDrainArgs(t *testing.T) {
+	gracePeriod := int64(10)
+	tests := []struct {
+		name    string
+		args    []v1alpha1.ArgsUnit
+		want    *drainArgs
+		wantErr bool
+	}{
+		{name: "default", want: &drainArgs{Timeout: defaultDrainTimeout}},
+		{
+			name: "all",
+			args: []v1alpha1.ArgsUnit{
+				{Key: "label", Value: "app=web"},
+				{Key: "graceperiod", Value: "10"},
+				{Key: "timeout", Value: "30s"},
+			},
+			want: &drainArgs{Label: map[string]string{"app": "web"}, GracePeriod: &gracePeriod, Timeout: "30s"},
+		},
+		{name: "bad_label", args: []v1alpha1.ArgsUnit{{Key: "label", Value: "app"}}, wantErr: true},
+		{name: "bad_graceperiod", args: []v1alpha1.ArgsUnit{{Key: "graceperiod", Value: "-1"}}, wantErr: true},
+		{name: "bad_timeout", args: []v1alpha1.ArgsUnit{{Key: "timeout", Value: "1d"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := parseDrainArgs(tt.args)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, re)
+		})
+	}
+}
+
+func Test_filterDrainPods(t *testing.T) {
+	isController := true
+	pods := []corev1.Pod{
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "web", UID: "1"}},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ds", UID: "2",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "DaemonSet", Controller: &isController}}},
+		},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "static", UID: "3", Annotations: map[string]string{mirrorPodAnnotation: "x"}}},
+		{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "done", UID: "4"}, Status: corev1.PodStatus{Phase: corev1.PodSucceeded}},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "rs", UID: "5",
+				OwnerReferences: []metav1.OwnerReference{{Kind: "ReplicaSet", Controller: &isController}}},
+		},
+	}
+
+	assert.Equal(t, []drainPod{{Namespace: "ns", Name: "web", UID: "1"}, {Namespace: "ns", Name: "rs", UID: "5"}}, filterDrainPods(pods))
+}
+
+func Test_getDrainMessage(t *testing.T) {
+	pending := []drainPod{{Namespace: "ns", Name: "a"}, {Namespace: "ns", Name: "b"}}
+	assert.Equal(t, "evicted 3/3 pods", getDrainMessage(3, nil, nil))
+	assert.Equal(t, "evicted 1/3 pods, blocked by PodDisruptionBudget: ns/a", getDrainMessage(3, pending, pending[:1]))
+}
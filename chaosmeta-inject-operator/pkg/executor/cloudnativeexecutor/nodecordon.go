@@ -0,0 +1,95 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"strconv"
+	"time"
+)
+
+const (
+	faultNodeCordon = "cordon"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.NodeCloudTarget, faultNodeCordon, &NodeCordonExecutor{})
+}
+
+// NodeCordonExecutor marks the node unschedulable, the original unschedulable state is the backup
+type NodeCordonExecutor struct{}
+
+func (e *NodeCordonExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	name, _, err := model.ParseNodeInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected node format: %s", err.Error())
+	}
+
+	node, err := getNode(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	return strconv.FormatBool(node.Spec.Unschedulable), patchUnschedulable(ctx, name, true)
+}
+
+func (e *NodeCordonExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	name, _, err := model.ParseNodeInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected node format: %s", err.Error())
+	}
+
+	oldUnschedulable, err := strconv.ParseBool(backup)
+	if err != nil {
+		return fmt.Errorf("old unschedulable is not a bool: %s", err.Error())
+	}
+
+	return patchUnschedulable(ctx, name, oldUnschedulable)
+}
+
+func (e *NodeCordonExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
+
+func getNode(ctx context.Context, name string) (*corev1.Node, error) {
+	node := &corev1.Node{}
+	if err := restclient.GetApiServerClientMap(v1alpha1.NodeCloudTarget).Get().Resource("nodes").Name(name).
+		Do(ctx).Into(node); err != nil {
+		return nil, fmt.Errorf("get node error: %s", err.Error())
+	}
+
+	return node, nil
+}
+
+func patchUnschedulable(ctx context.Context, name string, unschedulable bool) error {
+	if err := restclient.GetApiServerClientMap(v1alpha1.NodeCloudTarget).Patch(types.MergePatchType).Resource("nodes").
+		Name(name).Body([]byte(fmt.Sprintf(`{"spec":{"unschedulable":%t}}`, unschedulable))).Do(ctx).Error(); err != nil {
+		return fmt.Errorf("patch unschedulable of node error: %s", err.Error())
+	}
+
+	return nil
+}
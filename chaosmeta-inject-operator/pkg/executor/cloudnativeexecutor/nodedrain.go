@@ -0,0 +1,315 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	corev1 "k8s.io/api/core/v1"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	faultNodeDrain = "drain"
+
+	defaultDrainTimeout = "5m"
+	mirrorPodAnnotation = "kubernetes.io/config.mirror"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.NodeCloudTarget, faultNodeDrain, &NodeDrainExecutor{})
+}
+
+// NodeDrainExecutor cordons the node and evicts its pods through the eviction api, so the PodDisruptionBudgets are
+// respected. Only the pods matching "label" are evicted if provided, the pods of daemonset and the mirror pods are
+// skipped as kubectl drain does. The evictions blocked by PodDisruptionBudget are retried in query until "timeout"
+type NodeDrainExecutor struct{}
+
+type drainArgs struct {
+	Label       map[string]string
+	GracePeriod *int64
+	Timeout     string
+}
+
+type drainBackup struct {
+	Unschedulable bool       `json:"unschedulable"`
+	StartTime     string     `json:"startTime"`
+	GracePeriod   *int64     `json:"gracePeriod,omitempty"`
+	Timeout       string     `json:"timeout"`
+	Pods          []drainPod `json:"pods"`
+}
+
+type drainPod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	UID       string `json:"uid"`
+}
+
+func (p drainPod) String() string {
+	return fmt.Sprintf("%s/%s", p.Namespace, p.Name)
+}
+
+func (e *NodeDrainExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	name, _, err := model.ParseNodeInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected node format: %s", err.Error())
+	}
+
+	dArgs, err := parseDrainArgs(args)
+	if err != nil {
+		return "", fmt.Errorf("args error: %s", err.Error())
+	}
+
+	node, err := getNode(ctx, name)
+	if err != nil {
+		return "", err
+	}
+
+	podList := &corev1.PodList{}
+	req := restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget).Get().Resource("pods").
+		Param("fieldSelector", fmt.Sprintf("spec.nodeName=%s", name))
+	if len(dArgs.Label) != 0 {
+		req = req.Param("labelSelector", labels.SelectorFromSet(dArgs.Label).String())
+	}
+	if err := req.Do(ctx).Into(podList); err != nil {
+		return "", fmt.Errorf("list pods of node error: %s", err.Error())
+	}
+
+	backup := &drainBackup{
+		Unschedulable: node.Spec.Unschedulable,
+		StartTime:     time.Now().Format(model.TimeFormat),
+		GracePeriod:   dArgs.GracePeriod,
+		Timeout:       dArgs.Timeout,
+		Pods:          filterDrainPods(podList.Items),
+	}
+
+	backupBytes, err := json.Marshal(backup)
+	if err != nil {
+		return "", fmt.Errorf("backup to string error: %s", err.Error())
+	}
+
+	if err := patchUnschedulable(ctx, name, true); err != nil {
+		return "", err
+	}
+
+	// the failed evictions are retried in query
+	for _, pod := range backup.Pods {
+		if _, err := evictPod(ctx, pod, backup.GracePeriod); err != nil {
+			log.FromContext(ctx).Error(err, "evict pod error")
+		}
+	}
+
+	return string(backupBytes), nil
+}
+
+// Recover uncordons the node if it is schedulable before, the evicted pods are recreated by their controllers
+func (e *NodeDrainExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	name, _, err := model.ParseNodeInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected node format: %s", err.Error())
+	}
+
+	backupInfo, err := getDrainBackup(backup)
+	if err != nil {
+		return err
+	}
+
+	return patchUnschedulable(ctx, name, backupInfo.Unschedulable)
+}
+
+// Query retries the evictions in inject phase, it is running until all the pods are gone or the drain is timeout
+func (e *NodeDrainExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	re := &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}
+	if phase != v1alpha1.InjectPhaseType {
+		return re, nil
+	}
+
+	backupInfo, err := getDrainBackup(backup)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending, blocked []drainPod
+	for _, unit := range backupInfo.Pods {
+		pod, err := getPod(ctx, unit.Namespace, unit.Name)
+		if err != nil {
+			if common.IsNotFoundErr(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		if string(pod.UID) != unit.UID {
+			continue
+		}
+
+		pending = append(pending, unit)
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		isEvicted, err := evictPod(ctx, unit, backupInfo.GracePeriod)
+		if err != nil {
+			return nil, err
+		}
+		if !isEvicted {
+			blocked = append(blocked, unit)
+		}
+	}
+
+	re.Message = getDrainMessage(len(backupInfo.Pods), pending, blocked)
+	if len(pending) == 0 {
+		return re, nil
+	}
+
+	isTimeout, err := common.IsTimeout(backupInfo.StartTime, backupInfo.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("check drain timeout error: %s", err.Error())
+	}
+
+	if isTimeout {
+		re.Status, re.Message = v1alpha1.FailedStatusType, fmt.Sprintf("drain timeout, %s", re.Message)
+	} else {
+		re.Status = v1alpha1.RunningStatusType
+	}
+
+	return re, nil
+}
+
+func parseDrainArgs(args []v1alpha1.ArgsUnit) (*drainArgs, error) {
+	reArgs := common.GetArgs(args, []string{"label", "graceperiod", "timeout"})
+	re := &drainArgs{Timeout: defaultDrainTimeout}
+	if reArgs[0] != "" {
+		re.Label = make(map[string]string)
+		for _, kv := range strings.Split(reArgs[0], v1alpha1.ArgsListSplit) {
+			kvList := strings.Split(kv, v1alpha1.LabelListSplit)
+			if len(kvList) != 2 || strings.TrimSpace(kvList[0]) == "" {
+				return nil, fmt.Errorf("label[%s] is not in format: k=v", kv)
+			}
+			re.Label[strings.TrimSpace(kvList[0])] = strings.TrimSpace(kvList[1])
+		}
+	}
+
+	if reArgs[1] != "" {
+		gracePeriod, err := strconv.ParseInt(reArgs[1], 10, 64)
+		if err != nil || gracePeriod < 0 {
+			return nil, fmt.Errorf("graceperiod[%s] is not a non-negative num", reArgs[1])
+		}
+		re.GracePeriod = &gracePeriod
+	}
+
+	if reArgs[2] != "" {
+		if _, err := v1alpha1.ConvertDuration(reArgs[2]); err != nil {
+			return nil, fmt.Errorf("timeout[%s] is not a duration: %s", reArgs[2], err.Error())
+		}
+		re.Timeout = reArgs[2]
+	}
+
+	return re, nil
+}
+
+// filterDrainPods skips the pods which are finished, managed by daemonset or static
+func filterDrainPods(pods []corev1.Pod) []drainPod {
+	var re []drainPod
+	for _, pod := range pods {
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+
+		if _, ok := pod.Annotations[mirrorPodAnnotation]; ok {
+			continue
+		}
+
+		if owner := metav1.GetControllerOf(&pod); owner != nil && owner.Kind == "DaemonSet" {
+			continue
+		}
+
+		re = append(re, drainPod{Namespace: pod.Namespace, Name: pod.Name, UID: string(pod.UID)})
+	}
+
+	return re
+}
+
+func getDrainMessage(total int, pending, blocked []drainPod) string {
+	msg := fmt.Sprintf("evicted %d/%d pods", total-len(pending), total)
+	if len(blocked) != 0 {
+		var blockedArr []string
+		for _, p := range blocked {
+			blockedArr = append(blockedArr, p.String())
+		}
+		msg = fmt.Sprintf("%s, blocked by PodDisruptionBudget: %s", msg, strings.Join(blockedArr, v1alpha1.ArgsListSplit))
+	}
+
+	return msg
+}
+
+func getDrainBackup(backup string) (*drainBackup, error) {
+	re := &drainBackup{}
+	if err := json.Unmarshal([]byte(backup), re); err != nil {
+		return nil, fmt.Errorf("backup is not a json: %s", err.Error())
+	}
+
+	return re, nil
+}
+
+// evictPod return false if the eviction is blocked by PodDisruptionBudget
+func evictPod(ctx context.Context, pod drainPod, gracePeriod *int64) (bool, error) {
+	podUID := types.UID(pod.UID)
+	eviction := &policyv1.Eviction{
+		TypeMeta:   metav1.TypeMeta{APIVersion: policyv1.SchemeGroupVersion.String(), Kind: "Eviction"},
+		ObjectMeta: metav1.ObjectMeta{Namespace: pod.Namespace, Name: pod.Name},
+		DeleteOptions: &metav1.DeleteOptions{
+			GracePeriodSeconds: gracePeriod,
+			Preconditions:      &metav1.Preconditions{UID: &podUID},
+		},
+	}
+
+	// the eviction is encoded here because it is not in the group of the pod client
+	body, err := json.Marshal(eviction)
+	if err != nil {
+		return false, fmt.Errorf("get eviction payload error: %s", err.Error())
+	}
+
+	err = restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget).Post().Namespace(pod.Namespace).Resource("pods").
+		Name(pod.Name).SubResource("eviction").Body(body).Do(ctx).Error()
+	if err == nil || common.IsNotFoundErr(err) || apierrors.IsConflict(err) {
+		return true, nil
+	}
+
+	if apierrors.IsTooManyRequests(err) {
+		return false, nil
+	}
+
+	return false, fmt.Errorf("evict pod[%s] error: %s", pod, err.Error())
+}
@@ -0,0 +1,257 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"time"
+)
+
+const (
+	faultPodReadiness = "readiness"
+
+	readinessInjectReason  = "ChaosmetaInjected"
+	readinessRecoverReason = "ChaosmetaRecovered"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.PodCloudTarget, faultPodReadiness, &PodReadinessExecutor{})
+}
+
+// PodReadinessExecutor makes the pod NotReady by setting the readiness gate of chaosmeta to False, the containers are
+// not touched and keep running. The pod must declare the gate in spec.readinessGates and carry the label
+// v1alpha1.ReadinessGateLabel, then the gate is kept True by the operator out of the experiment. Query reports the
+// ready and unready count of the workload owning the pod
+type PodReadinessExecutor struct{}
+
+func (e *PodReadinessExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParsePodInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected pod format: %s", err.Error())
+	}
+
+	pod, err := getPod(ctx, ns, name)
+	if err != nil {
+		return "", err
+	}
+
+	if !common.HasReadinessGate(pod) {
+		return "", fmt.Errorf("pod[%s] does not declare readiness gate[%s] in spec.readinessGates with label[%s=true]", name, v1alpha1.ReadinessGateType, v1alpha1.ReadinessGateLabel)
+	}
+
+	if faultUID, ok := pod.Annotations[v1alpha1.ReadinessFaultAnnotation]; ok && faultUID != uid {
+		return "", fmt.Errorf("pod[%s] is under readiness fault of experiment[%s]", name, faultUID)
+	}
+
+	backup := string(common.GetPodConditionStatus(pod, v1alpha1.ReadinessGateType))
+	// mark the pod first, so that the gate is not set back to True by the operator
+	if err := patchReadinessFaultAnnotation(ctx, ns, name, fmt.Sprintf(`"%s"`, uid)); err != nil {
+		return "", err
+	}
+
+	if err := patchReadinessGate(ctx, ns, name, corev1.ConditionFalse, readinessInjectReason); err != nil {
+		// give the gate back to the operator, otherwise the mark is left on the pod by the failed injection
+		if rollbackErr := patchReadinessFaultAnnotation(ctx, ns, name, "null"); rollbackErr != nil {
+			return "", fmt.Errorf("%s, and rollback error: %s", err.Error(), rollbackErr.Error())
+		}
+		return "", err
+	}
+
+	return backup, nil
+}
+
+// Recover the gate is set to True whatever the backup is, because it is kept True by the operator out of experiment
+func (e *PodReadinessExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	ns, name, err := model.ParsePodInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected pod format: %s", err.Error())
+	}
+
+	if err := patchReadinessGate(ctx, ns, name, corev1.ConditionTrue, readinessRecoverReason); err != nil {
+		if common.IsNotFoundErr(err) {
+			return nil
+		}
+		return err
+	}
+
+	return patchReadinessFaultAnnotation(ctx, ns, name, "null")
+}
+
+func (e *PodReadinessExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	re := &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}
+
+	ns, name, err := model.ParsePodInfo(injectObject)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected pod format: %s", err.Error())
+	}
+
+	pod, err := getPod(ctx, ns, name)
+	if err != nil {
+		if common.IsNotFoundErr(err) {
+			return re, nil
+		}
+		return nil, err
+	}
+
+	workload, err := getPodWorkload(pod, func(resource, name string, obj runtime.Object) error {
+		return restclient.GetApiServerClientMap(v1alpha1.DeploymentCloudTarget).Get().Namespace(ns).Resource(resource).
+			Name(name).Do(ctx).Into(obj)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pods := []corev1.Pod{*pod}
+	if workload.selector != nil {
+		podList := &corev1.PodList{}
+		if err := restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget).Get().Namespace(ns).Resource("pods").
+			Param("labelSelector", workload.selector.String()).Do(ctx).Into(podList); err != nil {
+			return nil, fmt.Errorf("list pods of %s error: %s", workload.name, err.Error())
+		}
+		pods = podList.Items
+	}
+
+	ready, unready := countWorkloadReadiness(pods, workload.uid)
+	re.Message = fmt.Sprintf("%s: ready %d, unready %d", workload.name, ready, unready)
+
+	return re, nil
+}
+
+// podWorkload is the workload owning the pod, its pods are listed by selector, and only the pods controlled by uid
+// are counted if uid is not empty. Nil selector means the pod itself
+type podWorkload struct {
+	name     string
+	selector labels.Selector
+	uid      types.UID
+}
+
+func newPodWorkload(kind, name string, selector *metav1.LabelSelector, uid types.UID) (*podWorkload, error) {
+	s, err := metav1.LabelSelectorAsSelector(selector)
+	if err != nil {
+		return nil, fmt.Errorf("selector of %s/%s is invalid: %s", kind, name, err.Error())
+	}
+
+	re := &podWorkload{name: fmt.Sprintf("%s/%s", kind, name), uid: uid}
+	if !s.Empty() {
+		re.selector = s
+	}
+
+	return re, nil
+}
+
+// getPodWorkload follows the controller in ownerReferences of the pod, a replicaset controlled by deployment is
+// regarded as the deployment. get reads the owner of apps group by resource and name
+func getPodWorkload(pod *corev1.Pod, get func(resource, name string, obj runtime.Object) error) (*podWorkload, error) {
+	owner := metav1.GetControllerOf(pod)
+	if owner == nil {
+		return &podWorkload{name: fmt.Sprintf("Pod/%s", pod.Name)}, nil
+	}
+
+	switch owner.Kind {
+	case "ReplicaSet":
+		rs := &v1.ReplicaSet{}
+		if err := get("replicasets", owner.Name, rs); err != nil {
+			return nil, fmt.Errorf("get replicaset[%s] error: %s", owner.Name, err.Error())
+		}
+
+		if rsOwner := metav1.GetControllerOf(rs); rsOwner != nil && rsOwner.Kind == "Deployment" {
+			deploy := &v1.Deployment{}
+			if err := get("deployments", rsOwner.Name, deploy); err != nil {
+				return nil, fmt.Errorf("get deployment[%s] error: %s", rsOwner.Name, err.Error())
+			}
+
+			return newPodWorkload(rsOwner.Kind, deploy.Name, deploy.Spec.Selector, "")
+		}
+
+		return newPodWorkload(owner.Kind, rs.Name, rs.Spec.Selector, rs.UID)
+	case "StatefulSet":
+		sts := &v1.StatefulSet{}
+		if err := get("statefulsets", owner.Name, sts); err != nil {
+			return nil, fmt.Errorf("get statefulset[%s] error: %s", owner.Name, err.Error())
+		}
+
+		return newPodWorkload(owner.Kind, sts.Name, sts.Spec.Selector, sts.UID)
+	case "DaemonSet":
+		ds := &v1.DaemonSet{}
+		if err := get("daemonsets", owner.Name, ds); err != nil {
+			return nil, fmt.Errorf("get daemonset[%s] error: %s", owner.Name, err.Error())
+		}
+
+		return newPodWorkload(owner.Kind, ds.Name, ds.Spec.Selector, ds.UID)
+	default:
+		// the selector of other workloads is unknown, the pods with the same labels are checked by the controller
+		return newPodWorkload(owner.Kind, owner.Name, &metav1.LabelSelector{MatchLabels: pod.Labels}, owner.UID)
+	}
+}
+
+func countWorkloadReadiness(pods []corev1.Pod, uid types.UID) (ready, unready int) {
+	for i := range pods {
+		pod := &pods[i]
+		if pod.DeletionTimestamp != nil {
+			continue
+		}
+
+		if owner := metav1.GetControllerOf(pod); uid != "" && (owner == nil || owner.UID != uid) {
+			continue
+		}
+
+		if isPodReady(pod) {
+			ready++
+		} else {
+			unready++
+		}
+	}
+
+	return
+}
+
+func patchReadinessGate(ctx context.Context, ns, name string, status corev1.ConditionStatus, reason string) error {
+	payload := fmt.Sprintf(`{"status":{"conditions":[{"type":"%s","status":"%s","reason":"%s","lastTransitionTime":"%s"}]}}`,
+		v1alpha1.ReadinessGateType, status, reason, time.Now().UTC().Format(time.RFC3339))
+	if err := restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget).Patch(types.StrategicMergePatchType).Namespace(ns).
+		Resource("pods").Name(name).SubResource("status").Body([]byte(payload)).Do(ctx).Error(); err != nil {
+		return fmt.Errorf("patch readiness gate of pod error: %s", err.Error())
+	}
+
+	return nil
+}
+
+// patchReadinessFaultAnnotation value is a json value, null means to remove the annotation
+func patchReadinessFaultAnnotation(ctx context.Context, ns, name, value string) error {
+	payload := fmt.Sprintf(`{"metadata":{"annotations":{"%s":%s}}}`, v1alpha1.ReadinessFaultAnnotation, value)
+	if err := restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget).Patch(types.MergePatchType).Namespace(ns).
+		Resource("pods").Name(name).Body([]byte(payload)).Do(ctx).Error(); err != nil {
+		return fmt.Errorf("patch annotation of pod error: %s", err.Error())
+	}
+
+	return nil
+}
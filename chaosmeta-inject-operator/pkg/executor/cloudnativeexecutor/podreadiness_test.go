@@ -0,0 +1,108 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"reflect"
+	"testing"
+)
+
+func Test_countWorkloadReadiness(t *testing.T) {
+	isController := true
+	ownedBy := func(kind, name string, uid types.UID) []metav1.OwnerReference {
+		return []metav1.OwnerReference{{Kind: kind, Name: name, UID: uid, Controller: &isController}}
+	}
+	newPod := func(name string, owners []metav1.OwnerReference, ready bool) corev1.Pod {
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Labels: map[string]string{"app": "web"}, OwnerReferences: owners},
+			Status:     corev1.PodStatus{Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}},
+		}
+	}
+	webSelector := &metav1.LabelSelector{MatchLabels: map[string]string{"app": "web"}}
+
+	owners := map[string]runtime.Object{
+		"replicasets/web-1":   &v1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "web-1", UID: "rs-1", OwnerReferences: ownedBy("Deployment", "web", "deploy")}},
+		"deployments/web":     &v1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "web"}, Spec: v1.DeploymentSpec{Selector: webSelector}},
+		"replicasets/bare-rs": &v1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Name: "bare-rs", UID: "rs-bare"}, Spec: v1.ReplicaSetSpec{Selector: webSelector}},
+		"statefulsets/db":     &v1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Name: "db", UID: "sts"}, Spec: v1.StatefulSetSpec{Selector: webSelector}},
+	}
+	get := func(resource, name string, obj runtime.Object) error {
+		o, ok := owners[resource+"/"+name]
+		if !ok {
+			return fmt.Errorf("%s/%s not found", resource, name)
+		}
+		reflect.ValueOf(obj).Elem().Set(reflect.ValueOf(o).Elem())
+		return nil
+	}
+
+	// the pods listed by the selector "app=web"
+	pods := []corev1.Pod{
+		newPod("web-1-a", ownedBy("ReplicaSet", "web-1", "rs-1"), true),
+		newPod("web-1-b", ownedBy("ReplicaSet", "web-1", "rs-1"), false),
+		newPod("web-2-a", ownedBy("ReplicaSet", "web-2", "rs-2"), true),
+		newPod("bare-rs-a", ownedBy("ReplicaSet", "bare-rs", "rs-bare"), true),
+		newPod("db-0", ownedBy("StatefulSet", "db", "sts"), false),
+		newPod("single", nil, true),
+		newPod("orphan", ownedBy("ReplicaSet", "gone", "rs-gone"), true),
+	}
+
+	tests := []struct {
+		name         string
+		pod          int
+		wantWorkload string
+		wantList     bool
+		wantReady    int
+		wantUnready  int
+		wantErr      bool
+	}{
+		{name: "deployment", pod: 1, wantWorkload: "Deployment/web", wantList: true, wantReady: 5, wantUnready: 2},
+		{name: "replicaset", pod: 3, wantWorkload: "ReplicaSet/bare-rs", wantList: true, wantReady: 1, wantUnready: 0},
+		{name: "statefulset", pod: 4, wantWorkload: "StatefulSet/db", wantList: true, wantReady: 0, wantUnready: 1},
+		{name: "no_owner", pod: 5, wantWorkload: "Pod/single", wantList: false, wantReady: 1, wantUnready: 0},
+		{name: "owner_not_found", pod: 6, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			workload, err := getPodWorkload(&pods[tt.pod], get)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.wantWorkload, workload.name)
+			assert.Equal(t, tt.wantList, workload.selector != nil)
+
+			listed := []corev1.Pod{pods[tt.pod]}
+			if workload.selector != nil {
+				listed = pods
+			}
+			ready, unready := countWorkloadReadiness(listed, workload.uid)
+			assert.Equal(t, tt.wantReady, ready)
+			assert.Equal(t, tt.wantUnready, unready)
+		})
+	}
+}
@@ -0,0 +1,118 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"time"
+)
+
+func init() {
+	for _, fault := range []string{faultDataSet, faultDataDelete, faultDataCorrupt, faultDataReplace} {
+		registerCloudExecutor(v1alpha1.SecretCloudTarget, fault, &SecretDataExecutor{fault: fault})
+	}
+}
+
+// SecretDataExecutor changes the data of secret, the args are the same as ConfigMapDataExecutor, the values in
+// "data" are plain text and encoded by the apiserver
+type SecretDataExecutor struct {
+	fault string
+}
+
+func (e *SecretDataExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseSecretInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected secret format: %s", err.Error())
+	}
+
+	dArgs, err := parseDataFaultArgs(e.fault, args)
+	if err != nil {
+		return "", fmt.Errorf("args error: %s", err.Error())
+	}
+
+	c, secret := restclient.GetApiServerClientMap(v1alpha1.SecretCloudTarget), &corev1.Secret{}
+	if err := c.Get().Namespace(ns).Resource("secrets").Name(name).Do(ctx).Into(secret); err != nil {
+		return "", fmt.Errorf("get secret error: %s", err.Error())
+	}
+
+	if secret.Immutable != nil && *secret.Immutable {
+		return "", fmt.Errorf("secret[%s] is immutable", name)
+	}
+
+	newData, err := applyDataFault(secret.Data, e.fault, dArgs)
+	if err != nil {
+		return "", err
+	}
+
+	backup := &dataBackup{ByteData: secret.Data}
+	secret.Data = newData
+
+	// the original data of secret is kept in the backup secret instead of the status of experiment
+	owner := metav1.OwnerReference{APIVersion: "v1", Kind: "Secret", Name: secret.Name, UID: secret.UID}
+	return injectDataWithBackup(ctx, ns, uid, owner, backup, true, func() (string, error) {
+		if err := c.Put().Namespace(ns).Resource("secrets").Name(name).Body(secret).Do(ctx).Into(secret); err != nil {
+			return "", fmt.Errorf("update secret error: %s", err.Error())
+		}
+		return dataDigest(nil, secret.Data), nil
+	})
+}
+
+func (e *SecretDataExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	ns, name, err := model.ParseSecretInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected secret format: %s", err.Error())
+	}
+
+	backupInfo, err := loadDataBackup(ctx, ns, backup)
+	if err != nil {
+		return err
+	}
+
+	c, secret := restclient.GetApiServerClientMap(v1alpha1.SecretCloudTarget), &corev1.Secret{}
+	if err := c.Get().Namespace(ns).Resource("secrets").Name(name).Do(ctx).Into(secret); err != nil {
+		return fmt.Errorf("get secret error: %s", err.Error())
+	}
+
+	needRestore, err := checkDataConflict(dataDigest(nil, secret.Data), backupInfo)
+	if err != nil {
+		return err
+	}
+
+	// the update is rejected with conflict if the secret is changed after the check
+	if needRestore {
+		secret.Data = backupInfo.ByteData
+		if err := c.Put().Namespace(ns).Resource("secrets").Name(name).Body(secret).Do(ctx).Error(); err != nil {
+			return fmt.Errorf("restore secret error: %s", err.Error())
+		}
+	}
+
+	return deleteNamespacedObject(ctx, ns, "secrets", backupInfo.BackupObject)
+}
+
+func (e *SecretDataExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
@@ -0,0 +1,212 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"testing"
+)
+
+func Test_getBrokenSelector(t *testing.T) {
+	old := map[string]string{"app": "web"}
+	re, err := getBrokenSelector(old, "123", "")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"app": "web", brokenSelectorKey: "123"}, re)
+	assert.Equal(t, map[string]string{"app": "web"}, old)
+
+	re, err = getBrokenSelector(old, "123", "app=none,tier=x")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"app": "none", "tier": "x"}, re)
+
+	_, err = getBrokenSelector(old, "123", "app")
+	assert.Error(t, err)
+}
+
+func Test_getNewServicePorts(t *testing.T) {
+	oldPorts := []corev1.ServicePort{
+		{Name: "http", Port: 80, TargetPort: intstr.FromInt(8080)},
+		{Name: "grpc", Port: 9090, TargetPort: intstr.FromInt(9090)},
+	}
+
+	tests := []struct {
+		name       string
+		port       string
+		targetPort string
+		want       []intstr.IntOrString
+		wantErr    bool
+	}{
+		{name: "all", port: "", targetPort: "1", want: []intstr.IntOrString{intstr.FromInt(1), intstr.FromInt(1)}},
+		{name: "by_name", port: "grpc", targetPort: "web", want: []intstr.IntOrString{intstr.FromInt(8080), intstr.FromString("web")}},
+		{name: "by_number", port: "80", targetPort: "81", want: []intstr.IntOrString{intstr.FromInt(81), intstr.FromInt(9090)}},
+		{name: "not_match", port: "443", targetPort: "81", wantErr: true},
+		{name: "empty_target", port: "80", targetPort: "", wantErr: true},
+		{name: "out_of_range", port: "80", targetPort: "70000", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := getNewServicePorts(oldPorts, tt.port, tt.targetPort)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			for i := range re {
+				assert.Equal(t, tt.want[i], re[i].TargetPort)
+			}
+			assert.Equal(t, intstr.FromInt(8080), oldPorts[0].TargetPort)
+		})
+	}
+}
+
+func Test_setAndRestoreServiceType(t *testing.T) {
+	allocate := true
+	oldSpec := corev1.ServiceSpec{
+		Type:                          corev1.ServiceTypeLoadBalancer,
+		ExternalTrafficPolicy:         corev1.ServiceExternalTrafficPolicyTypeLocal,
+		HealthCheckNodePort:           30001,
+		AllocateLoadBalancerNodePorts: &allocate,
+		LoadBalancerSourceRanges:      []string{"10.0.0.0/8"},
+		Ports:                         []corev1.ServicePort{{Protocol: corev1.ProtocolTCP, Port: 80, NodePort: 30080}},
+	}
+
+	spec := *oldSpec.DeepCopy()
+	setServiceType(&spec, corev1.ServiceTypeClusterIP)
+	assert.Equal(t, corev1.ServiceTypeClusterIP, spec.Type)
+	assert.Equal(t, corev1.ServiceExternalTrafficPolicyType(""), spec.ExternalTrafficPolicy)
+	assert.Equal(t, int32(0), spec.HealthCheckNodePort)
+	assert.Nil(t, spec.AllocateLoadBalancerNodePorts)
+	assert.Nil(t, spec.LoadBalancerSourceRanges)
+	assert.Equal(t, int32(0), spec.Ports[0].NodePort)
+
+	restoreServiceType(&spec, &oldSpec, false)
+	assert.Equal(t, oldSpec, spec)
+
+	setServiceType(&spec, corev1.ServiceTypeNodePort)
+	assert.Equal(t, int32(30080), spec.Ports[0].NodePort)
+	assert.Equal(t, corev1.ServiceExternalTrafficPolicyTypeLocal, spec.ExternalTrafficPolicy)
+
+	restoreServiceType(&spec, &oldSpec, true)
+	assert.Equal(t, corev1.ServiceTypeLoadBalancer, spec.Type)
+	assert.Equal(t, int32(0), spec.Ports[0].NodePort)
+	assert.Equal(t, int32(0), spec.HealthCheckNodePort)
+
+	_, err := parseServiceType("externalname")
+	assert.Error(t, err)
+	re, err := parseServiceType("nodeport")
+	assert.NoError(t, err)
+	assert.Equal(t, corev1.ServiceTypeNodePort, re)
+}
+
+func Test_parseServiceEndpointArgs(t *testing.T) {
+	tests := []struct {
+		name        string
+		args        []v1alpha1.ArgsUnit
+		wantPercent int
+		wantHold    bool
+		wantErr     bool
+	}{
+		{name: "default", wantPercent: 100, wantHold: true},
+		{name: "set", args: []v1alpha1.ArgsUnit{{Key: "percent", Value: "30"}, {Key: "hold", Value: "false"}}, wantPercent: 30, wantHold: false},
+		{name: "zero_percent", args: []v1alpha1.ArgsUnit{{Key: "percent", Value: "0"}}, wantErr: true},
+		{name: "bad_hold", args: []v1alpha1.ArgsUnit{{Key: "hold", Value: "yes"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			percent, hold, err := parseServiceEndpointArgs(tt.args)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.wantPercent, percent)
+			assert.Equal(t, tt.wantHold, hold)
+		})
+	}
+}
+
+func Test_getEndpointsToRemove(t *testing.T) {
+	notReady := false
+	newEndpoint := func(addr string) discoveryv1.Endpoint {
+		return discoveryv1.Endpoint{Addresses: []string{addr}}
+	}
+	slices := []discoveryv1.EndpointSlice{
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "s1"},
+			Endpoints: []discoveryv1.Endpoint{
+				newEndpoint("10.0.0.3"),
+				newEndpoint("10.0.0.1"),
+				{Addresses: []string{"10.0.0.0"}, Conditions: discoveryv1.EndpointConditions{Ready: &notReady}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Name: "s2"},
+			Endpoints:  []discoveryv1.Endpoint{newEndpoint("10.0.0.2"), newEndpoint("10.0.0.4")},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		percent int
+		want    map[string][]discoveryv1.Endpoint
+	}{
+		{
+			name:    "round_up",
+			percent: 1,
+			want:    map[string][]discoveryv1.Endpoint{"s1": {newEndpoint("10.0.0.1")}},
+		},
+		{
+			name:    "half",
+			percent: 50,
+			want: map[string][]discoveryv1.Endpoint{
+				"s1": {newEndpoint("10.0.0.1")},
+				"s2": {newEndpoint("10.0.0.2")},
+			},
+		},
+		{
+			name:    "all",
+			percent: 100,
+			want: map[string][]discoveryv1.Endpoint{
+				"s1": {newEndpoint("10.0.0.1"), newEndpoint("10.0.0.3")},
+				"s2": {newEndpoint("10.0.0.2"), newEndpoint("10.0.0.4")},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getEndpointsToRemove(slices, tt.percent))
+		})
+	}
+
+	assert.Empty(t, getEndpointsToRemove(nil, 100))
+}
+
+func Test_removeAndAddEndpoints(t *testing.T) {
+	endpoints := []discoveryv1.Endpoint{
+		{Addresses: []string{"10.0.0.1"}},
+		{Addresses: []string{"10.0.0.2"}},
+	}
+	removed := []discoveryv1.Endpoint{{Addresses: []string{"10.0.0.2"}}}
+
+	left := removeEndpoints(endpoints, removed)
+	assert.Equal(t, endpoints[:1], left)
+	assert.Equal(t, endpoints, addEndpoints(left, removed))
+	assert.Equal(t, endpoints, addEndpoints(endpoints, removed))
+}
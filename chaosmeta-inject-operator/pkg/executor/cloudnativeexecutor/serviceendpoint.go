@@ -0,0 +1,274 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	faultServiceEndpoint = "endpoint"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.ServiceCloudTarget, faultServiceEndpoint, &ServiceEndpointExecutor{})
+}
+
+// ServiceEndpointExecutor removes "percent" of the ready addresses from the EndpointSlices of service.
+// The endpoints controllers rebuild the EndpointSlices from the pods at once, so the selector of service is detached
+// during the experiment if "hold" is true(default), the controllers skip the service without selector and leave the
+// EndpointSlices as they are. With "hold" false the fault is only a transient removal
+type ServiceEndpointExecutor struct{}
+
+type serviceEndpointBackup struct {
+	// Selector is the detached selector, nil means the selector is not detached
+	Selector map[string]string                 `json:"selector,omitempty"`
+	Removed  map[string][]discoveryv1.Endpoint `json:"removed"`
+}
+
+func (e *ServiceEndpointExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseServiceInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected service format: %s", err.Error())
+	}
+
+	percent, hold, err := parseServiceEndpointArgs(args)
+	if err != nil {
+		return "", fmt.Errorf("args error: %s", err.Error())
+	}
+
+	sliceList, err := listEndpointSlices(ctx, ns, name)
+	if err != nil {
+		return "", err
+	}
+
+	backup := &serviceEndpointBackup{Removed: getEndpointsToRemove(sliceList.Items, percent)}
+	if len(backup.Removed) == 0 {
+		return "", fmt.Errorf("no ready address in endpointslices of service[%s]", name)
+	}
+
+	if hold {
+		svc, err := getService(ctx, ns, name)
+		if err != nil {
+			return "", err
+		}
+
+		if len(svc.Spec.Selector) != 0 {
+			backup.Selector = svc.Spec.Selector
+			svc.Spec.Selector = nil
+			if err := updateService(ctx, svc); err != nil {
+				return "", err
+			}
+		}
+	}
+
+	backupBytes, err := json.Marshal(backup)
+	if err != nil {
+		return "", fmt.Errorf("backup to string error: %s", err.Error())
+	}
+
+	// list again, the slices may be changed by the controllers before the selector is detached
+	if sliceList, err = listEndpointSlices(ctx, ns, name); err != nil {
+		return string(backupBytes), err
+	}
+
+	for i := range sliceList.Items {
+		slice := &sliceList.Items[i]
+		removed, ok := backup.Removed[slice.Name]
+		if !ok {
+			continue
+		}
+
+		slice.Endpoints = removeEndpoints(slice.Endpoints, removed)
+		if err := updateEndpointSlice(ctx, slice); err != nil {
+			return string(backupBytes), err
+		}
+	}
+
+	return string(backupBytes), nil
+}
+
+func (e *ServiceEndpointExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	ns, name, err := model.ParseServiceInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected service format: %s", err.Error())
+	}
+
+	backupInfo := &serviceEndpointBackup{}
+	if err := json.Unmarshal([]byte(backup), backupInfo); err != nil {
+		return fmt.Errorf("backup is not a json: %s", err.Error())
+	}
+
+	// add the addresses back first, the slices managed by others are not rebuilt by the endpoints controllers
+	sliceList, err := listEndpointSlices(ctx, ns, name)
+	if err != nil {
+		return err
+	}
+
+	for i := range sliceList.Items {
+		slice := &sliceList.Items[i]
+		removed, ok := backupInfo.Removed[slice.Name]
+		if !ok {
+			continue
+		}
+
+		newEndpoints := addEndpoints(slice.Endpoints, removed)
+		if len(newEndpoints) == len(slice.Endpoints) {
+			continue
+		}
+
+		slice.Endpoints = newEndpoints
+		if err := updateEndpointSlice(ctx, slice); err != nil {
+			return err
+		}
+	}
+
+	if backupInfo.Selector == nil {
+		return nil
+	}
+
+	svc, err := getService(ctx, ns, name)
+	if err != nil {
+		return err
+	}
+
+	svc.Spec.Selector = backupInfo.Selector
+	return updateService(ctx, svc)
+}
+
+func (e *ServiceEndpointExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
+
+func parseServiceEndpointArgs(args []v1alpha1.ArgsUnit) (percent int, hold bool, err error) {
+	reArgs := common.GetArgs(args, []string{"percent", "hold"})
+	percent, hold = 100, true
+	if reArgs[0] != "" {
+		if percent, err = strconv.Atoi(reArgs[0]); err != nil || percent <= 0 || percent > 100 {
+			return 0, false, fmt.Errorf("percent[%s] is not in range: 1-100", reArgs[0])
+		}
+	}
+
+	if reArgs[1] != "" {
+		if hold, err = strconv.ParseBool(reArgs[1]); err != nil {
+			return 0, false, fmt.Errorf("hold[%s] is not a bool", reArgs[1])
+		}
+	}
+
+	return percent, hold, nil
+}
+
+// getEndpointsToRemove return the endpoints to remove grouped by slice name. The ready endpoints are sorted by
+// address so that the result is stable, and at least one is selected if any
+func getEndpointsToRemove(slices []discoveryv1.EndpointSlice, percent int) map[string][]discoveryv1.Endpoint {
+	type sliceEndpoint struct {
+		slice    string
+		endpoint discoveryv1.Endpoint
+	}
+
+	var ready []sliceEndpoint
+	for _, slice := range slices {
+		for _, ep := range slice.Endpoints {
+			if len(ep.Addresses) == 0 || (ep.Conditions.Ready != nil && !*ep.Conditions.Ready) {
+				continue
+			}
+			ready = append(ready, sliceEndpoint{slice: slice.Name, endpoint: ep})
+		}
+	}
+
+	sort.SliceStable(ready, func(i, j int) bool {
+		return ready[i].endpoint.Addresses[0] < ready[j].endpoint.Addresses[0]
+	})
+
+	count := (len(ready)*percent + 99) / 100
+	re := make(map[string][]discoveryv1.Endpoint)
+	for _, unit := range ready[:count] {
+		re[unit.slice] = append(re[unit.slice], unit.endpoint)
+	}
+
+	return re
+}
+
+func removeEndpoints(endpoints, removed []discoveryv1.Endpoint) []discoveryv1.Endpoint {
+	removedMap := make(map[string]bool)
+	for _, ep := range removed {
+		removedMap[ep.Addresses[0]] = true
+	}
+
+	re := make([]discoveryv1.Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		if len(ep.Addresses) != 0 && removedMap[ep.Addresses[0]] {
+			continue
+		}
+		re = append(re, ep)
+	}
+
+	return re
+}
+
+// addEndpoints the endpoints already in the slice are skipped, they may be added back by the controllers
+func addEndpoints(endpoints, removed []discoveryv1.Endpoint) []discoveryv1.Endpoint {
+	existMap := make(map[string]bool)
+	for _, ep := range endpoints {
+		if len(ep.Addresses) != 0 {
+			existMap[ep.Addresses[0]] = true
+		}
+	}
+
+	re := append([]discoveryv1.Endpoint{}, endpoints...)
+	for _, ep := range removed {
+		if !existMap[ep.Addresses[0]] {
+			re = append(re, ep)
+		}
+	}
+
+	return re
+}
+
+func listEndpointSlices(ctx context.Context, ns, serviceName string) (*discoveryv1.EndpointSliceList, error) {
+	sliceList := &discoveryv1.EndpointSliceList{}
+	if err := restclient.GetEndpointSliceClient().Get().Namespace(ns).Resource("endpointslices").
+		Param("labelSelector", fmt.Sprintf("%s=%s", discoveryv1.LabelServiceName, serviceName)).
+		Do(ctx).Into(sliceList); err != nil {
+		return nil, fmt.Errorf("list endpointslices error: %s", err.Error())
+	}
+
+	return sliceList, nil
+}
+
+func updateEndpointSlice(ctx context.Context, slice *discoveryv1.EndpointSlice) error {
+	if err := restclient.GetEndpointSliceClient().Put().Namespace(slice.Namespace).Resource("endpointslices").
+		Name(slice.Name).Body(slice).Do(ctx).Error(); err != nil {
+		return fmt.Errorf("update endpointslice[%s] error: %s", slice.Name, err.Error())
+	}
+
+	return nil
+}
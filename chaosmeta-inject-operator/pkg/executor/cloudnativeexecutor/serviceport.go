@@ -0,0 +1,139 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"strconv"
+	"time"
+)
+
+const (
+	faultServicePort = "port"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.ServiceCloudTarget, faultServicePort, &ServicePortExecutor{})
+}
+
+// ServicePortExecutor changes the port mapping of service, the "targetport" is set to the ports matching "port",
+// which is a port number or name, all the ports are changed if "port" is empty
+type ServicePortExecutor struct{}
+
+func (e *ServicePortExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseServiceInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected service format: %s", err.Error())
+	}
+
+	svc, err := getService(ctx, ns, name)
+	if err != nil {
+		return "", err
+	}
+
+	backup, err := json.Marshal(svc.Spec)
+	if err != nil {
+		return "", fmt.Errorf("backup to string error: %s", err.Error())
+	}
+
+	reArgs := common.GetArgs(args, []string{"port", "targetport"})
+	newPorts, err := getNewServicePorts(svc.Spec.Ports, reArgs[0], reArgs[1])
+	if err != nil {
+		return "", fmt.Errorf("args error: %s", err.Error())
+	}
+
+	svc.Spec.Ports = newPorts
+	return string(backup), updateService(ctx, svc)
+}
+
+func (e *ServicePortExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	ns, name, err := model.ParseServiceInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected service format: %s", err.Error())
+	}
+
+	oldSpec, err := getBackupServiceSpec(backup)
+	if err != nil {
+		return err
+	}
+
+	svc, err := getService(ctx, ns, name)
+	if err != nil {
+		return err
+	}
+
+	// only the target port is restored, the node port may be reallocated by other faults
+	oldTargetPort := make(map[string]intstr.IntOrString)
+	for _, p := range oldSpec.Ports {
+		oldTargetPort[getServicePortKey(p)] = p.TargetPort
+	}
+
+	for i := range svc.Spec.Ports {
+		if tp, ok := oldTargetPort[getServicePortKey(svc.Spec.Ports[i])]; ok {
+			svc.Spec.Ports[i].TargetPort = tp
+		}
+	}
+
+	return updateService(ctx, svc)
+}
+
+func (e *ServicePortExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
+
+func getNewServicePorts(oldPorts []corev1.ServicePort, port, targetPort string) ([]corev1.ServicePort, error) {
+	if targetPort == "" {
+		return nil, fmt.Errorf("targetport is empty")
+	}
+
+	newTargetPort := intstr.Parse(targetPort)
+	if newTargetPort.Type == intstr.Int && (newTargetPort.IntVal <= 0 || newTargetPort.IntVal > 65535) {
+		return nil, fmt.Errorf("targetport[%s] is not in range: 1-65535", targetPort)
+	}
+
+	var changed bool
+	newPorts := make([]corev1.ServicePort, len(oldPorts))
+	for i, p := range oldPorts {
+		newPorts[i] = p
+		if port != "" && port != p.Name && port != strconv.Itoa(int(p.Port)) {
+			continue
+		}
+
+		newPorts[i].TargetPort, changed = newTargetPort, true
+	}
+
+	if !changed {
+		return nil, fmt.Errorf("no port of service matches: %s", port)
+	}
+
+	return newPorts, nil
+}
+
+func getServicePortKey(p corev1.ServicePort) string {
+	return fmt.Sprintf("%s/%d", p.Protocol, p.Port)
+}
@@ -0,0 +1,152 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	corev1 "k8s.io/api/core/v1"
+	"strings"
+	"time"
+)
+
+const (
+	faultServiceSelector = "selector"
+
+	brokenSelectorKey = "chaosmeta.io/broken-selector"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.ServiceCloudTarget, faultServiceSelector, &ServiceSelectorExecutor{})
+}
+
+// ServiceSelectorExecutor breaks the selector of service so that it matches no pod, the selector is replaced by
+// "selector" in format k=v,k=v if provided, otherwise a key which no pod has is added to the original selector
+type ServiceSelectorExecutor struct{}
+
+func (e *ServiceSelectorExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseServiceInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected service format: %s", err.Error())
+	}
+
+	svc, err := getService(ctx, ns, name)
+	if err != nil {
+		return "", err
+	}
+
+	if len(svc.Spec.Selector) == 0 {
+		return "", fmt.Errorf("service[%s] has no selector", name)
+	}
+
+	backup, err := json.Marshal(svc.Spec)
+	if err != nil {
+		return "", fmt.Errorf("backup to string error: %s", err.Error())
+	}
+
+	newSelector, err := getBrokenSelector(svc.Spec.Selector, uid, common.GetArgs(args, []string{"selector"})[0])
+	if err != nil {
+		return "", fmt.Errorf("args error: %s", err.Error())
+	}
+
+	svc.Spec.Selector = newSelector
+	return string(backup), updateService(ctx, svc)
+}
+
+func (e *ServiceSelectorExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	ns, name, err := model.ParseServiceInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected service format: %s", err.Error())
+	}
+
+	oldSpec, err := getBackupServiceSpec(backup)
+	if err != nil {
+		return err
+	}
+
+	svc, err := getService(ctx, ns, name)
+	if err != nil {
+		return err
+	}
+
+	svc.Spec.Selector = oldSpec.Selector
+	return updateService(ctx, svc)
+}
+
+func (e *ServiceSelectorExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
+
+// getBrokenSelector the custom selector is used if provided, otherwise add a key to the original selector
+func getBrokenSelector(oldSelector map[string]string, uid, customSelector string) (map[string]string, error) {
+	re := make(map[string]string)
+	if customSelector != "" {
+		for _, kv := range strings.Split(customSelector, v1alpha1.ArgsListSplit) {
+			kvList := strings.Split(kv, v1alpha1.LabelListSplit)
+			if len(kvList) != 2 || strings.TrimSpace(kvList[0]) == "" {
+				return nil, fmt.Errorf("selector[%s] is not in format: k=v", kv)
+			}
+			re[strings.TrimSpace(kvList[0])] = strings.TrimSpace(kvList[1])
+		}
+
+		return re, nil
+	}
+
+	for k, v := range oldSelector {
+		re[k] = v
+	}
+	re[brokenSelectorKey] = uid
+
+	return re, nil
+}
+
+func getService(ctx context.Context, ns, name string) (*corev1.Service, error) {
+	svc := &corev1.Service{}
+	if err := restclient.GetApiServerClientMap(v1alpha1.ServiceCloudTarget).Get().Namespace(ns).
+		Resource("services").Name(name).Do(ctx).Into(svc); err != nil {
+		return nil, fmt.Errorf("get service error: %s", err.Error())
+	}
+
+	return svc, nil
+}
+
+func updateService(ctx context.Context, svc *corev1.Service) error {
+	if err := restclient.GetApiServerClientMap(v1alpha1.ServiceCloudTarget).Put().Namespace(svc.Namespace).
+		Resource("services").Name(svc.Name).Body(svc).Do(ctx).Into(svc); err != nil {
+		return fmt.Errorf("update service error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func getBackupServiceSpec(backup string) (*corev1.ServiceSpec, error) {
+	spec := &corev1.ServiceSpec{}
+	if err := json.Unmarshal([]byte(backup), spec); err != nil {
+		return nil, fmt.Errorf("backup spec of service is not a json: %s", err.Error())
+	}
+
+	return spec, nil
+}
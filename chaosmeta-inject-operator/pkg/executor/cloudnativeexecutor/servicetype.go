@@ -0,0 +1,170 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	corev1 "k8s.io/api/core/v1"
+	"strings"
+	"time"
+)
+
+const (
+	faultServiceType = "type"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.ServiceCloudTarget, faultServiceType, &ServiceTypeExecutor{})
+}
+
+// ServiceTypeExecutor switches the type of service among ClusterIP, NodePort and LoadBalancer by "type". ExternalName
+// is not supported because the cluster ip would be released and could not be restored
+type ServiceTypeExecutor struct{}
+
+func (e *ServiceTypeExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseServiceInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected service format: %s", err.Error())
+	}
+
+	newType, err := parseServiceType(common.GetArgs(args, []string{"type"})[0])
+	if err != nil {
+		return "", fmt.Errorf("args error: %s", err.Error())
+	}
+
+	svc, err := getService(ctx, ns, name)
+	if err != nil {
+		return "", err
+	}
+
+	if svc.Spec.Type == newType {
+		return "", fmt.Errorf("type of service[%s] is already %s", name, newType)
+	}
+
+	if svc.Spec.Type == corev1.ServiceTypeExternalName {
+		return "", fmt.Errorf("service[%s] of type ExternalName is not supported", name)
+	}
+
+	backup, err := json.Marshal(svc.Spec)
+	if err != nil {
+		return "", fmt.Errorf("backup to string error: %s", err.Error())
+	}
+
+	setServiceType(&svc.Spec, newType)
+	return string(backup), updateService(ctx, svc)
+}
+
+func (e *ServiceTypeExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	ns, name, err := model.ParseServiceInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected service format: %s", err.Error())
+	}
+
+	oldSpec, err := getBackupServiceSpec(backup)
+	if err != nil {
+		return err
+	}
+
+	svc, err := getService(ctx, ns, name)
+	if err != nil {
+		return err
+	}
+
+	restoreServiceType(&svc.Spec, oldSpec, false)
+	if err := updateService(ctx, svc); err != nil {
+		if !strings.Contains(err.Error(), "already allocated") {
+			return err
+		}
+
+		// the original node ports are taken by others after released, reallocate them
+		if svc, err = getService(ctx, ns, name); err != nil {
+			return err
+		}
+		restoreServiceType(&svc.Spec, oldSpec, true)
+		return updateService(ctx, svc)
+	}
+
+	return nil
+}
+
+func (e *ServiceTypeExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
+
+func parseServiceType(str string) (corev1.ServiceType, error) {
+	for _, t := range []corev1.ServiceType{corev1.ServiceTypeClusterIP, corev1.ServiceTypeNodePort, corev1.ServiceTypeLoadBalancer} {
+		if strings.EqualFold(str, string(t)) {
+			return t, nil
+		}
+	}
+
+	return "", fmt.Errorf("type[%s] is not one of: ClusterIP, NodePort, LoadBalancer", str)
+}
+
+// setServiceType clears the fields which are not allowed by the apiserver for the new type
+func setServiceType(spec *corev1.ServiceSpec, newType corev1.ServiceType) {
+	if newType != corev1.ServiceTypeLoadBalancer {
+		spec.AllocateLoadBalancerNodePorts = nil
+		spec.LoadBalancerClass = nil
+		spec.LoadBalancerSourceRanges = nil
+		spec.HealthCheckNodePort = 0
+	}
+
+	if newType == corev1.ServiceTypeClusterIP {
+		spec.ExternalTrafficPolicy = ""
+		for i := range spec.Ports {
+			spec.Ports[i].NodePort = 0
+		}
+	}
+
+	spec.Type = newType
+}
+
+// restoreServiceType restores the fields related to type, the node ports are reallocated if reallocate is true
+func restoreServiceType(spec *corev1.ServiceSpec, oldSpec *corev1.ServiceSpec, reallocate bool) {
+	spec.Type = oldSpec.Type
+	spec.ExternalTrafficPolicy = oldSpec.ExternalTrafficPolicy
+	spec.AllocateLoadBalancerNodePorts = oldSpec.AllocateLoadBalancerNodePorts
+	spec.LoadBalancerClass = oldSpec.LoadBalancerClass
+	spec.LoadBalancerSourceRanges = oldSpec.LoadBalancerSourceRanges
+	spec.HealthCheckNodePort = oldSpec.HealthCheckNodePort
+
+	oldNodePort := make(map[string]int32)
+	for _, p := range oldSpec.Ports {
+		oldNodePort[getServicePortKey(p)] = p.NodePort
+	}
+
+	for i := range spec.Ports {
+		spec.Ports[i].NodePort = 0
+		if !reallocate {
+			spec.Ports[i].NodePort = oldNodePort[getServicePortKey(spec.Ports[i])]
+		}
+	}
+
+	if reallocate {
+		spec.HealthCheckNodePort = 0
+	}
+}
@@ -0,0 +1,88 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package cloudnativeexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	v1 "k8s.io/api/apps/v1"
+	"time"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.StatefulsetCloudTarget, "label", &StatefulSetLabelExecutor{})
+}
+
+type StatefulSetLabelExecutor struct{}
+
+func (e *StatefulSetLabelExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseStatefulSetInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected statefulset format: %s", err.Error())
+	}
+
+	c, sts := restclient.GetApiServerClientMap(v1alpha1.StatefulsetCloudTarget), &v1.StatefulSet{}
+	if err := c.Get().Namespace(ns).Resource("statefulsets").Name(name).Do(ctx).Into(sts); err != nil {
+		return "", fmt.Errorf("get statefulset error: %s", err.Error())
+	}
+
+	var backupBytes []byte
+	if sts.ObjectMeta.Labels != nil {
+		backupBytes, err = json.Marshal(sts.ObjectMeta.Labels)
+		if err != nil {
+			return "", fmt.Errorf("backup to string error: %s", err.Error())
+		}
+	}
+
+	newLabels, err := getNewLabels(ctx, sts.ObjectMeta.Labels, args)
+	if err != nil {
+		return "", fmt.Errorf("get new labels error: %s", err.Error())
+	}
+
+	return string(backupBytes), patchLabels(ctx, c, "statefulsets", ns, name, newLabels)
+}
+
+func (e *StatefulSetLabelExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	ns, name, err := model.ParseStatefulSetInfo(injectObject)
+	if err != nil {
+		return fmt.Errorf("unexpected statefulset format: %s", err.Error())
+	}
+
+	c, sts := restclient.GetApiServerClientMap(v1alpha1.StatefulsetCloudTarget), &v1.StatefulSet{}
+	if err := c.Get().Namespace(ns).Resource("statefulsets").Name(name).Do(ctx).Into(sts); err != nil {
+		return fmt.Errorf("get statefulset error: %s", err.Error())
+	}
+
+	backupBytes, err := getBackupLabels([]byte(backup), sts.ObjectMeta.Labels)
+	if err != nil {
+		return fmt.Errorf("get backup labels error: %s", err.Error())
+	}
+
+	return patchLabels(ctx, c, "statefulsets", ns, name, backupBytes)
+}
+
+func (e *StatefulSetLabelExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	return &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}, nil
+}
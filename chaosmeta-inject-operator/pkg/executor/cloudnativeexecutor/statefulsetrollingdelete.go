@@ -0,0 +1,278 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/common"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"strconv"
+	"time"
+)
+
+func init() {
+	registerCloudExecutor(v1alpha1.StatefulsetCloudTarget, faultStatefulSetRollingDelete, &StatefulSetRollingDeleteExecutor{})
+}
+
+const (
+	faultStatefulSetRollingDelete = "rollingdelete"
+
+	defaultRollingDeleteTimeout = "10m"
+)
+
+// StatefulSetRollingDeleteExecutor deletes the pods of statefulset one by one in the order of rolling update, from the
+// largest ordinal to the partition, the next pod is deleted only after the previous one is recreated and ready.
+// The pods below the partition are not touched, so a partitioned canary is not disturbed. The rolling is failed if it
+// is not finished in "timeout"
+type StatefulSetRollingDeleteExecutor struct{}
+
+type rollingDeleteArgs struct {
+	Partition int
+	Count     int
+	Timeout   string
+}
+
+type rollingDeleteBackup struct {
+	StartTime string              `json:"startTime"`
+	Timeout   string              `json:"timeout"`
+	Plan      []rollingDeleteUnit `json:"plan"`
+}
+
+type rollingDeleteUnit struct {
+	Name string `json:"name"`
+	UID  string `json:"uid"`
+}
+
+func (e *StatefulSetRollingDeleteExecutor) Inject(ctx context.Context, injectObject, uid, timeout string, args []v1alpha1.ArgsUnit) (string, error) {
+	ns, name, err := model.ParseStatefulSetInfo(injectObject)
+	if err != nil {
+		return "", fmt.Errorf("unexpected statefulset format: %s", err.Error())
+	}
+
+	sts := &v1.StatefulSet{}
+	if err := restclient.GetApiServerClientMap(v1alpha1.StatefulsetCloudTarget).Get().Namespace(ns).
+		Resource("statefulsets").Name(name).Do(ctx).Into(sts); err != nil {
+		return "", fmt.Errorf("get statefulset error: %s", err.Error())
+	}
+
+	rArgs, err := parseRollingDeleteArgs(sts, args)
+	if err != nil {
+		return "", fmt.Errorf("args error: %s", err.Error())
+	}
+
+	var plan []rollingDeleteUnit
+	for _, ordinal := range getRollingOrdinals(int(*sts.Spec.Replicas), rArgs.Partition, rArgs.Count) {
+		pod, err := getPod(ctx, ns, fmt.Sprintf("%s-%d", name, ordinal))
+		if err != nil {
+			if common.IsNotFoundErr(err) {
+				continue
+			}
+			return "", err
+		}
+
+		plan = append(plan, rollingDeleteUnit{Name: pod.Name, UID: string(pod.UID)})
+	}
+
+	if len(plan) == 0 {
+		return "", fmt.Errorf("no pod of statefulset[%s] in the range: ordinal >= %d", name, rArgs.Partition)
+	}
+
+	backupBytes, err := json.Marshal(&rollingDeleteBackup{
+		StartTime: time.Now().Format(model.TimeFormat),
+		Timeout:   rArgs.Timeout,
+		Plan:      plan,
+	})
+	if err != nil {
+		return "", fmt.Errorf("backup to string error: %s", err.Error())
+	}
+
+	return string(backupBytes), deletePodWithUID(ctx, ns, plan[0])
+}
+
+// Recover the deleted pods are recreated by the statefulset controller, nothing to restore
+func (e *StatefulSetRollingDeleteExecutor) Recover(ctx context.Context, injectObject, uid, backup string) error {
+	return nil
+}
+
+// Query drives the rolling delete in inject phase, it is running until all the pods in plan are recreated and ready,
+// or failed if the rolling is timeout
+func (e *StatefulSetRollingDeleteExecutor) Query(ctx context.Context, injectObject, uid, backup string, phase v1alpha1.PhaseType) (*model.SubExpInfo, error) {
+	re := &model.SubExpInfo{
+		UID:        uid,
+		Status:     v1alpha1.SuccessStatusType,
+		UpdateTime: time.Now().Format(model.TimeFormat),
+	}
+	if phase != v1alpha1.InjectPhaseType {
+		return re, nil
+	}
+
+	ns, _, err := model.ParseStatefulSetInfo(injectObject)
+	if err != nil {
+		return nil, fmt.Errorf("unexpected statefulset format: %s", err.Error())
+	}
+
+	backupInfo := &rollingDeleteBackup{}
+	if err := json.Unmarshal([]byte(backup), backupInfo); err != nil {
+		return nil, fmt.Errorf("rolling plan is not a json: %s", err.Error())
+	}
+
+	pods := make(map[string]*corev1.Pod)
+	for _, unit := range backupInfo.Plan {
+		pod, err := getPod(ctx, ns, unit.Name)
+		if err != nil {
+			if common.IsNotFoundErr(err) {
+				continue
+			}
+			return nil, err
+		}
+		pods[unit.Name] = pod
+	}
+
+	next, msg := getNextRollingStep(backupInfo.Plan, pods)
+	if next == nil && msg == "" {
+		return re, nil
+	}
+
+	isTimeout, err := common.IsTimeout(backupInfo.StartTime, backupInfo.Timeout)
+	if err != nil {
+		return nil, fmt.Errorf("check rolling delete timeout error: %s", err.Error())
+	}
+
+	if isTimeout {
+		if next != nil {
+			msg = fmt.Sprintf("pod[%s] is not deleted", next.Name)
+		}
+		re.Status, re.Message = v1alpha1.FailedStatusType, fmt.Sprintf("rolling delete timeout, %s", msg)
+		return re, nil
+	}
+
+	if next != nil {
+		if err := deletePodWithUID(ctx, ns, *next); err != nil {
+			return nil, err
+		}
+		msg = fmt.Sprintf("pod[%s] is deleted", next.Name)
+	}
+
+	re.Status, re.Message = v1alpha1.RunningStatusType, msg
+	return re, nil
+}
+
+// getNextRollingStep return the pod to delete, or the reason of waiting. Both empty means the rolling is finished
+func getNextRollingStep(plan []rollingDeleteUnit, pods map[string]*corev1.Pod) (*rollingDeleteUnit, string) {
+	for i, unit := range plan {
+		pod, ok := pods[unit.Name]
+		if !ok {
+			return nil, fmt.Sprintf("waiting for pod[%s] to be recreated", unit.Name)
+		}
+
+		if string(pod.UID) == unit.UID {
+			if pod.DeletionTimestamp != nil {
+				return nil, fmt.Sprintf("waiting for pod[%s] to be terminated", unit.Name)
+			}
+			return &plan[i], ""
+		}
+
+		if !isPodReady(pod) {
+			return nil, fmt.Sprintf("waiting for pod[%s] to be ready", unit.Name)
+		}
+	}
+
+	return nil, ""
+}
+
+// getRollingOrdinals return the ordinals in the order of rolling update, count <= 0 means all
+func getRollingOrdinals(replicas, partition, count int) []int {
+	var re []int
+	for ordinal := replicas - 1; ordinal >= partition && ordinal >= 0; ordinal-- {
+		if count > 0 && len(re) >= count {
+			break
+		}
+		re = append(re, ordinal)
+	}
+
+	return re
+}
+
+// parseRollingDeleteArgs the partition of the rolling update strategy is used if "partition" is not provided
+func parseRollingDeleteArgs(sts *v1.StatefulSet, args []v1alpha1.ArgsUnit) (*rollingDeleteArgs, error) {
+	reArgs := common.GetArgs(args, []string{"partition", "count", "timeout"})
+	re := &rollingDeleteArgs{Timeout: defaultRollingDeleteTimeout}
+	var err error
+	if reArgs[0] != "" {
+		if re.Partition, err = strconv.Atoi(reArgs[0]); err != nil || re.Partition < 0 {
+			return nil, fmt.Errorf("partition[%s] is not a non-negative num", reArgs[0])
+		}
+	} else if sts.Spec.UpdateStrategy.RollingUpdate != nil && sts.Spec.UpdateStrategy.RollingUpdate.Partition != nil {
+		re.Partition = int(*sts.Spec.UpdateStrategy.RollingUpdate.Partition)
+	}
+
+	if reArgs[1] != "" {
+		if re.Count, err = strconv.Atoi(reArgs[1]); err != nil || re.Count < 0 {
+			return nil, fmt.Errorf("count[%s] is not a non-negative num", reArgs[1])
+		}
+	}
+
+	if reArgs[2] != "" {
+		if _, err := v1alpha1.ConvertDuration(reArgs[2]); err != nil {
+			return nil, fmt.Errorf("timeout[%s] is not a duration: %s", reArgs[2], err.Error())
+		}
+		re.Timeout = reArgs[2]
+	}
+
+	return re, nil
+}
+
+func getPod(ctx context.Context, ns, name string) (*corev1.Pod, error) {
+	pod := &corev1.Pod{}
+	if err := restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget).Get().Namespace(ns).
+		Resource("pods").Name(name).Do(ctx).Into(pod); err != nil {
+		return nil, fmt.Errorf("get pod[%s] error: %s", name, err.Error())
+	}
+
+	return pod, nil
+}
+
+// deletePodWithUID the precondition avoids deleting the recreated pod with the same name, the conflict means the pod
+// has been recreated, which is checked by the next query
+func deletePodWithUID(ctx context.Context, ns string, unit rollingDeleteUnit) error {
+	podUID := types.UID(unit.UID)
+	err := restclient.GetApiServerClientMap(v1alpha1.PodCloudTarget).Delete().Namespace(ns).Resource("pods").Name(unit.Name).
+		Body(&metav1.DeleteOptions{Preconditions: &metav1.Preconditions{UID: &podUID}}).Do(ctx).Error()
+	if err != nil && !common.IsNotFoundErr(err) && !apierrors.IsConflict(err) {
+		return fmt.Errorf("delete pod[%s] error: %s", unit.Name, err.Error())
+	}
+
+	return nil
+}
+
+func isPodReady(pod *corev1.Pod) bool {
+	for _, c := range pod.Status.Conditions {
+		if c.Type == corev1.PodReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+
+	return false
+}
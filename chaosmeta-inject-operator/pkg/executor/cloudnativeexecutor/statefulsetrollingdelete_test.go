@@ -0,0 +1,115 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package cloudnativeexecutor
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	v1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"testing"
+)
+
+func Test_getRollingOrdinals(t *testing.T) {
+	tests := []struct {
+		name      string
+		replicas  int
+		partition int
+		count     int
+		want      []int
+	}{
+		{name: "all", replicas: 3, partition: 0, count: 0, want: []int{2, 1, 0}},
+		{name: "partition", replicas: 5, partition: 3, count: 0, want: []int{4, 3}},
+		{name: "count", replicas: 5, partition: 0, count: 2, want: []int{4, 3}},
+		{name: "partition_over_replicas", replicas: 2, partition: 3, count: 0, want: nil},
+		{name: "empty", replicas: 0, partition: 0, count: 1, want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equalf(t, tt.want, getRollingOrdinals(tt.replicas, tt.partition, tt.count), "getRollingOrdinals(%v, %v, %v)", tt.replicas, tt.partition, tt.count)
+		})
+	}
+}
+
+func Test_parseRollingDeleteArgs(t *testing.T) {
+	partition := int32(2)
+	sts := &v1.StatefulSet{Spec: v1.StatefulSetSpec{UpdateStrategy: v1.StatefulSetUpdateStrategy{
+		RollingUpdate: &v1.RollingUpdateStatefulSetStrategy{Partition: &partition},
+	}}}
+
+	tests := []struct {
+		name    string
+		args    []v1alpha1.ArgsUnit
+		want    *rollingDeleteArgs
+		wantErr bool
+	}{
+		{name: "strategy_partition", args: nil, want: &rollingDeleteArgs{Partition: 2, Timeout: defaultRollingDeleteTimeout}},
+		{name: "override", args: []v1alpha1.ArgsUnit{{Key: "partition", Value: "0"}, {Key: "count", Value: "1"}, {Key: "timeout", Value: "30s"}}, want: &rollingDeleteArgs{Partition: 0, Count: 1, Timeout: "30s"}},
+		{name: "invalid_partition", args: []v1alpha1.ArgsUnit{{Key: "partition", Value: "-1"}}, wantErr: true},
+		{name: "invalid_count", args: []v1alpha1.ArgsUnit{{Key: "count", Value: "a"}}, wantErr: true},
+		{name: "invalid_timeout", args: []v1alpha1.ArgsUnit{{Key: "timeout", Value: "1x"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseRollingDeleteArgs(sts, tt.args)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_getNextRollingStep(t *testing.T) {
+	plan := []rollingDeleteUnit{{Name: "db-2", UID: "u2"}, {Name: "db-1", UID: "u1"}}
+	newPod := func(uid string, ready, deleting bool) *corev1.Pod {
+		pod := &corev1.Pod{ObjectMeta: metav1.ObjectMeta{UID: types.UID(uid)}}
+		if deleting {
+			pod.DeletionTimestamp = &metav1.Time{}
+		}
+
+		status := corev1.ConditionFalse
+		if ready {
+			status = corev1.ConditionTrue
+		}
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: status}}
+		return pod
+	}
+
+	tests := []struct {
+		name     string
+		pods     map[string]*corev1.Pod
+		wantNext *rollingDeleteUnit
+		wantWait bool
+	}{
+		{name: "terminating", pods: map[string]*corev1.Pod{"db-2": newPod("u2", true, true), "db-1": newPod("u1", true, false)}, wantWait: true},
+		{name: "recreating", pods: map[string]*corev1.Pod{"db-1": newPod("u1", true, false)}, wantWait: true},
+		{name: "not_ready", pods: map[string]*corev1.Pod{"db-2": newPod("n2", false, false), "db-1": newPod("u1", true, false)}, wantWait: true},
+		{name: "next", pods: map[string]*corev1.Pod{"db-2": newPod("n2", true, false), "db-1": newPod("u1", true, false)}, wantNext: &plan[1]},
+		{name: "finished", pods: map[string]*corev1.Pod{"db-2": newPod("n2", true, false), "db-1": newPod("n1", true, false)}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next, msg := getNextRollingStep(plan, tt.pods)
+			assert.Equal(t, tt.wantNext, next)
+			assert.Equal(t, tt.wantWait, msg != "")
+		})
+	}
+}
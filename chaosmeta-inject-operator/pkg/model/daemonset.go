@@ -1,6 +1,9 @@
 package model
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
+)
 
 type DaemonSetObject struct {
 	DaemonSetName string
@@ -8,5 +11,16 @@ type DaemonSetObject struct {
 }
 
 func (d *DaemonSetObject) GetObjectName() string {
-	return fmt.Sprintf("%s%s%s%s%s", "deployment", ObjectNameSplit, d.Namespace, ObjectNameSplit, d.DaemonSetName)
+	return fmt.Sprintf("%s%s%s%s%s", "daemonset", ObjectNameSplit, d.Namespace, ObjectNameSplit, d.DaemonSetName)
+}
+
+func ParseDaemonSetInfo(str string) (namespace, name string, err error) {
+	tmpArr := strings.Split(str, ObjectNameSplit)
+	if len(tmpArr) == 3 {
+		namespace, name = tmpArr[1], tmpArr[2]
+	} else {
+		err = fmt.Errorf("unexpected format of daemonset string: %s", str)
+	}
+
+	return
 }
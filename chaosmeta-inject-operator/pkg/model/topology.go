@@ -0,0 +1,27 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package model
+
+// Topology the location of an inject object, empty means unknown or not applicable
+type Topology struct {
+	Zone string
+	Node string
+	// Workload the controller of pod, like "Deployment/name", a pod of deployment belongs to the deployment
+	Workload string
+	// Pod the containers in the same pod are counted once by the limit of workload
+	Pod string
+}
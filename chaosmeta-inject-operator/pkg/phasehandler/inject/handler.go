@@ -48,7 +48,7 @@ func (h *InjectPhaseHandler) SolveCreated(ctx context.Context, exp *v1alpha1.Exp
 	)
 
 	for i := range exp.Status.Detail.Inject {
-		if targetSubExp[i].Status != v1alpha1.CreatedStatusType {
+		if targetSubExp[i].Status != v1alpha1.CreatedStatusType || !isReleased(exp, &targetSubExp[i]) {
 			continue
 		}
 
@@ -63,7 +63,7 @@ func (h *InjectPhaseHandler) SolveCreated(ctx context.Context, exp *v1alpha1.Exp
 	for i := range targetSubExp {
 		if targetSubExp[i].Status == v1alpha1.FailedStatusType {
 			failCount++
-		} else if targetSubExp[i].Status == v1alpha1.CreatedStatusType {
+		} else if targetSubExp[i].Status == v1alpha1.CreatedStatusType && isReleased(exp, &targetSubExp[i]) {
 			createdCount++
 		}
 	}
@@ -160,19 +160,29 @@ func (h *InjectPhaseHandler) SolveRunning(ctx context.Context, exp *v1alpha1.Exp
 
 	wg.Wait()
 
-	var runCount, failCount int
+	if solveRollout(ctx, exp) {
+		exp.Status.Status, exp.Status.Message = v1alpha1.CreatedStatusType, fmt.Sprintf("wave %d/%d started", exp.Status.Rollout.Wave, exp.Status.Rollout.TotalWave)
+		exp.Status.UpdateTime = time.Now().Format(model.TimeFormat)
+		return
+	}
+
+	var runCount, failCount, waitCount int
 	for i := range targetSubExp {
 		if targetSubExp[i].Status == v1alpha1.RunningStatusType {
 			runCount++
 		} else if targetSubExp[i].Status == v1alpha1.FailedStatusType {
 			failCount++
+		} else if !isReleased(exp, &targetSubExp[i]) {
+			waitCount++
 		}
 	}
 
-	logger.Info(fmt.Sprintf("experiment: %s/%s, SolveRunning: totalCount[%d], failCount[%d], runCount[%d]", exp.Namespace, exp.Name, len(targetSubExp), failCount, runCount))
+	logger.Info(fmt.Sprintf("experiment: %s/%s, SolveRunning: totalCount[%d], failCount[%d], runCount[%d], waitCount[%d]", exp.Namespace, exp.Name, len(targetSubExp), failCount, runCount, waitCount))
 
 	if runCount > 0 {
 		exp.Status.Status, exp.Status.Message = v1alpha1.RunningStatusType, "run count is more than 0, need to retry"
+	} else if waitCount > 0 {
+		exp.Status.Status, exp.Status.Message = v1alpha1.RunningStatusType, fmt.Sprintf("wait for wave %d/%d", exp.Status.Rollout.Wave+1, exp.Status.Rollout.TotalWave)
 	} else {
 		if failCount == 0 {
 			exp.Status.Status, exp.Status.Message = v1alpha1.SuccessStatusType, "run success"
@@ -270,16 +280,22 @@ func solveFinalStatus(ctx context.Context, exp *v1alpha1.Experiment) {
 	exp.Status.Phase, exp.Status.UpdateTime = v1alpha1.RecoverPhaseType, nowTime
 
 	if len(injectDetail) != 0 {
-		recoverDetail := make([]v1alpha1.ExperimentDetailUnit, len(injectDetail))
+		recoverDetail := make([]v1alpha1.ExperimentDetailUnit, 0, len(injectDetail))
 		for i := range injectDetail {
-			recoverDetail[i] = v1alpha1.ExperimentDetailUnit{
+			// the waves not started because of the pause of rollout have nothing to recover
+			if !isReleased(exp, &injectDetail[i]) {
+				continue
+			}
+
+			recoverDetail = append(recoverDetail, v1alpha1.ExperimentDetailUnit{
 				InjectObjectName: injectDetail[i].InjectObjectName,
 				UID:              injectDetail[i].UID,
 				Status:           v1alpha1.CreatedStatusType,
 				Message:          "start to recover",
 				StartTime:        nowTime,
 				Backup:           injectDetail[i].Backup,
-			}
+				Wave:             injectDetail[i].Wave,
+			})
 		}
 
 		exp.Status.Status, exp.Status.Detail.Recover = v1alpha1.CreatedStatusType, recoverDetail
@@ -0,0 +1,167 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injecthandler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/restclient"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"time"
+)
+
+const (
+	measureResource     = "commonmeasures"
+	measureFailedStatus = "failed"
+	// maxMeasureErrors the rollout is paused if the measure of pause condition can not be got for so many times in a row
+	maxMeasureErrors = 5
+)
+
+// measureStatusGetter is replaced in tests
+var measureStatusGetter = getMeasureStatus
+
+// isReleased the sub experiments of the waves not started yet are not injected
+func isReleased(exp *v1alpha1.Experiment, unit *v1alpha1.ExperimentDetailUnit) bool {
+	return exp.Status.Rollout == nil || unit.Wave <= exp.Status.Rollout.Wave
+}
+
+// solveRollout start the next wave when the interval is passed, or stop further waves when the pause condition is met
+// or the experiment is being recovered or deleted. return true if a new wave is started
+func solveRollout(ctx context.Context, exp *v1alpha1.Experiment) bool {
+	var (
+		logger  = log.FromContext(ctx)
+		rollout = exp.Status.Rollout
+	)
+
+	if rollout == nil || exp.Spec.Rollout == nil || rollout.Paused != "" || rollout.Wave >= rollout.TotalWave {
+		return false
+	}
+
+	if exp.Spec.TargetPhase == v1alpha1.RecoverPhaseType || !exp.DeletionTimestamp.IsZero() {
+		pauseRollout(ctx, exp, "experiment is recovering")
+		return false
+	}
+
+	reason, err := getPauseReason(ctx, exp)
+	if err != nil {
+		// further waves keep waiting until the pause condition is known, the rollout is paused if it keeps unknown
+		rollout.MeasureErrors++
+		logger.Error(err, fmt.Sprintf("check pause condition of rollout error, times: %d/%d", rollout.MeasureErrors, maxMeasureErrors))
+		if rollout.MeasureErrors < maxMeasureErrors {
+			return false
+		}
+
+		reason = err.Error()
+	} else {
+		rollout.MeasureErrors = 0
+	}
+
+	if reason != "" {
+		pauseRollout(ctx, exp, reason)
+		return false
+	}
+
+	if exp.Spec.Rollout.Interval != "" {
+		interval, err := v1alpha1.ConvertDuration(exp.Spec.Rollout.Interval)
+		if err != nil {
+			logger.Error(err, "get interval of rollout error")
+			return false
+		}
+
+		waveTime, err := time.ParseInLocation(model.TimeFormat, rollout.WaveTime, time.Local)
+		if err != nil {
+			logger.Error(err, "get start time of wave error")
+			return false
+		}
+
+		if time.Since(waveTime) < interval {
+			return false
+		}
+	}
+
+	nowTime := time.Now().Format(model.TimeFormat)
+	rollout.Wave, rollout.WaveTime = rollout.Wave+1, nowTime
+	for i := range exp.Status.Detail.Inject {
+		if exp.Status.Detail.Inject[i].Wave == rollout.Wave {
+			exp.Status.Detail.Inject[i].StartTime, exp.Status.Detail.Inject[i].Message = nowTime, fmt.Sprintf("wave %d started", rollout.Wave)
+		}
+	}
+
+	logger.Info(fmt.Sprintf("experiment: %s/%s, rollout start wave %d/%d", exp.Namespace, exp.Name, rollout.Wave, rollout.TotalWave))
+	return true
+}
+
+// pauseRollout stop further waves, the sub experiments of them are marked as not injected
+func pauseRollout(ctx context.Context, exp *v1alpha1.Experiment, reason string) {
+	log.FromContext(ctx).Info(fmt.Sprintf("experiment: %s/%s, rollout is paused at wave %d: %s", exp.Namespace, exp.Name, exp.Status.Rollout.Wave, reason))
+	exp.Status.Rollout.Paused = reason
+	for i := range exp.Status.Detail.Inject {
+		if !isReleased(exp, &exp.Status.Detail.Inject[i]) {
+			exp.Status.Detail.Inject[i].Status, exp.Status.Detail.Inject[i].Message = v1alpha1.FailedStatusType, fmt.Sprintf("not injected, rollout is paused: %s", reason)
+		}
+	}
+}
+
+// getPauseReason return empty if further waves can be started
+func getPauseReason(ctx context.Context, exp *v1alpha1.Experiment) (string, error) {
+	pause := exp.Spec.Rollout.Pause
+	if pause == nil {
+		return "", nil
+	}
+
+	if pause.OnFailure {
+		for i, unit := range exp.Status.Detail.Inject {
+			if isReleased(exp, &exp.Status.Detail.Inject[i]) && unit.Status == v1alpha1.FailedStatusType {
+				return fmt.Sprintf("sub experiment of %s failed", unit.InjectObjectName), nil
+			}
+		}
+	}
+
+	if pause.Measure != "" {
+		status, err := measureStatusGetter(ctx, exp.Namespace, pause.Measure)
+		if err != nil {
+			return "", fmt.Errorf("get status of measure %s/%s error: %s", exp.Namespace, pause.Measure, err.Error())
+		}
+
+		if status == measureFailedStatus {
+			return fmt.Sprintf("measure %s failed", pause.Measure), nil
+		}
+	}
+
+	return "", nil
+}
+
+func getMeasureStatus(ctx context.Context, namespace, name string) (string, error) {
+	result, err := restclient.GetMeasureClient().Get().Namespace(namespace).Resource(measureResource).Name(name).Do(ctx).Raw()
+	if err != nil {
+		return "", err
+	}
+
+	measure := &struct {
+		Status struct {
+			Status string `json:"status"`
+		} `json:"status"`
+	}{}
+	if err := json.Unmarshal(result, measure); err != nil {
+		return "", fmt.Errorf("unmarshal measure error: %s", err.Error())
+	}
+
+	return measure.Status.Status, nil
+}
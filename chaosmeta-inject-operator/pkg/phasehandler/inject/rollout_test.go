@@ -0,0 +1,183 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injecthandler
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"testing"
+	"time"
+)
+
+func newRolloutExperiment(interval string, pause *v1alpha1.PauseCondition, waveTime time.Time, status ...v1alpha1.StatusType) *v1alpha1.Experiment {
+	exp := &v1alpha1.Experiment{
+		Spec: v1alpha1.ExperimentSpec{
+			Rollout: &v1alpha1.RolloutStrategy{
+				BatchType: v1alpha1.CountRangeType,
+				BatchSize: 1,
+				Interval:  interval,
+				Pause:     pause,
+			},
+		},
+		Status: v1alpha1.ExperimentStatus{
+			Rollout: &v1alpha1.RolloutStatus{
+				Wave:      1,
+				TotalWave: len(status),
+				WaveTime:  waveTime.Format(model.TimeFormat),
+			},
+		},
+	}
+
+	for i, s := range status {
+		exp.Status.Detail.Inject = append(exp.Status.Detail.Inject, v1alpha1.ExperimentDetailUnit{
+			InjectObjectName: "pod/chaosmeta/chaosmeta-" + string(rune('0'+i)),
+			Status:           s,
+			Wave:             i + 1,
+		})
+	}
+
+	return exp
+}
+
+func TestSolveRollout_StartNextWave(t *testing.T) {
+	exp := newRolloutExperiment("1m", nil, time.Now().Add(-2*time.Minute), v1alpha1.RunningStatusType, v1alpha1.CreatedStatusType, v1alpha1.CreatedStatusType)
+
+	assert.True(t, solveRollout(context.Background(), exp))
+	assert.Equal(t, 2, exp.Status.Rollout.Wave)
+	assert.True(t, isReleased(exp, &exp.Status.Detail.Inject[1]))
+	assert.False(t, isReleased(exp, &exp.Status.Detail.Inject[2]))
+	assert.Equal(t, "wave 2 started", exp.Status.Detail.Inject[1].Message)
+}
+
+func TestSolveRollout_WaitInterval(t *testing.T) {
+	exp := newRolloutExperiment("10m", nil, time.Now(), v1alpha1.RunningStatusType, v1alpha1.CreatedStatusType)
+
+	assert.False(t, solveRollout(context.Background(), exp))
+	assert.Equal(t, 1, exp.Status.Rollout.Wave)
+	assert.Equal(t, "", exp.Status.Rollout.Paused)
+}
+
+func TestSolveRollout_LastWave(t *testing.T) {
+	exp := newRolloutExperiment("", nil, time.Now(), v1alpha1.RunningStatusType)
+
+	assert.False(t, solveRollout(context.Background(), exp))
+	assert.Equal(t, 1, exp.Status.Rollout.Wave)
+}
+
+func TestSolveRollout_PauseOnFailure(t *testing.T) {
+	exp := newRolloutExperiment("", &v1alpha1.PauseCondition{OnFailure: true}, time.Now(), v1alpha1.FailedStatusType, v1alpha1.CreatedStatusType, v1alpha1.CreatedStatusType)
+
+	assert.False(t, solveRollout(context.Background(), exp))
+	assert.Equal(t, 1, exp.Status.Rollout.Wave)
+	assert.Equal(t, "sub experiment of pod/chaosmeta/chaosmeta-0 failed", exp.Status.Rollout.Paused)
+	for _, unit := range exp.Status.Detail.Inject[1:] {
+		assert.Equal(t, v1alpha1.FailedStatusType, unit.Status)
+	}
+
+	// a paused rollout never starts further waves
+	exp.Status.Detail.Inject[0].Status = v1alpha1.SuccessStatusType
+	assert.False(t, solveRollout(context.Background(), exp))
+}
+
+func TestSolveRollout_PauseOnMeasure(t *testing.T) {
+	defer func(getter func(ctx context.Context, namespace, name string) (string, error)) {
+		measureStatusGetter = getter
+	}(measureStatusGetter)
+
+	status, err := "running", error(nil)
+	measureStatusGetter = func(ctx context.Context, namespace, name string) (string, error) {
+		return status, err
+	}
+
+	exp := newRolloutExperiment("", &v1alpha1.PauseCondition{Measure: "m1"}, time.Now(), v1alpha1.RunningStatusType, v1alpha1.CreatedStatusType, v1alpha1.CreatedStatusType)
+	assert.True(t, solveRollout(context.Background(), exp))
+	assert.Equal(t, 2, exp.Status.Rollout.Wave)
+
+	status = measureFailedStatus
+	assert.False(t, solveRollout(context.Background(), exp))
+	assert.Equal(t, "measure m1 failed", exp.Status.Rollout.Paused)
+	assert.Equal(t, v1alpha1.FailedStatusType, exp.Status.Detail.Inject[2].Status)
+}
+
+func TestSolveRollout_MeasureError(t *testing.T) {
+	defer func(getter func(ctx context.Context, namespace, name string) (string, error)) {
+		measureStatusGetter = getter
+	}(measureStatusGetter)
+
+	err := fmt.Errorf(`commonmeasures.chaosmeta.io "m1" not found`)
+	measureStatusGetter = func(ctx context.Context, namespace, name string) (string, error) {
+		return "", err
+	}
+
+	exp := newRolloutExperiment("", &v1alpha1.PauseCondition{Measure: "m1"}, time.Now(), v1alpha1.RunningStatusType, v1alpha1.CreatedStatusType)
+	for i := 1; i < maxMeasureErrors; i++ {
+		assert.False(t, solveRollout(context.Background(), exp))
+		assert.Equal(t, i, exp.Status.Rollout.MeasureErrors)
+		assert.Equal(t, "", exp.Status.Rollout.Paused)
+	}
+
+	// a successful check resets the count
+	err = nil
+	assert.True(t, solveRollout(context.Background(), exp))
+	assert.Equal(t, 0, exp.Status.Rollout.MeasureErrors)
+
+	exp = newRolloutExperiment("", &v1alpha1.PauseCondition{Measure: "m1"}, time.Now(), v1alpha1.RunningStatusType, v1alpha1.CreatedStatusType)
+	err = fmt.Errorf(`commonmeasures.chaosmeta.io "m1" not found`)
+	for i := 0; i < maxMeasureErrors; i++ {
+		assert.False(t, solveRollout(context.Background(), exp))
+	}
+	assert.Equal(t, `get status of measure /m1 error: commonmeasures.chaosmeta.io "m1" not found`, exp.Status.Rollout.Paused)
+	assert.Equal(t, v1alpha1.FailedStatusType, exp.Status.Detail.Inject[1].Status)
+	assert.False(t, solveRollout(context.Background(), exp))
+}
+
+func TestSolveRollout_PauseOnRecover(t *testing.T) {
+	exp := newRolloutExperiment("", nil, time.Now(), v1alpha1.RunningStatusType, v1alpha1.CreatedStatusType, v1alpha1.CreatedStatusType)
+	exp.Spec.TargetPhase = v1alpha1.RecoverPhaseType
+
+	assert.False(t, solveRollout(context.Background(), exp))
+	assert.Equal(t, 1, exp.Status.Rollout.Wave)
+	assert.Equal(t, "experiment is recovering", exp.Status.Rollout.Paused)
+	assert.Equal(t, v1alpha1.RunningStatusType, exp.Status.Detail.Inject[0].Status)
+	for _, unit := range exp.Status.Detail.Inject[1:] {
+		assert.Equal(t, v1alpha1.FailedStatusType, unit.Status)
+	}
+}
+
+func TestSolveRollout_PauseOnDelete(t *testing.T) {
+	exp := newRolloutExperiment("", nil, time.Now(), v1alpha1.RunningStatusType, v1alpha1.CreatedStatusType)
+	exp.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+
+	assert.False(t, solveRollout(context.Background(), exp))
+	assert.Equal(t, 1, exp.Status.Rollout.Wave)
+	assert.Equal(t, v1alpha1.FailedStatusType, exp.Status.Detail.Inject[1].Status)
+}
+
+func TestSolveFinalStatus_SkipNotReleased(t *testing.T) {
+	exp := newRolloutExperiment("", nil, time.Now(), v1alpha1.SuccessStatusType, v1alpha1.FailedStatusType)
+	exp.Spec.TargetPhase, exp.Status.Phase = v1alpha1.RecoverPhaseType, v1alpha1.InjectPhaseType
+
+	solveFinalStatus(context.Background(), exp)
+	assert.Equal(t, v1alpha1.RecoverPhaseType, exp.Status.Phase)
+	assert.Equal(t, 1, len(exp.Status.Detail.Recover))
+	assert.Equal(t, "pod/chaosmeta/chaosmeta-0", exp.Status.Detail.Recover[0].InjectObjectName)
+	assert.Equal(t, 1, exp.Status.Detail.Recover[0].Wave)
+}
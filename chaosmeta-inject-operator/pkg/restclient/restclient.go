@@ -27,13 +27,28 @@ import (
 )
 
 var (
-	apiServerClientMap = make(map[v1alpha1.CloudTargetType]rest.Interface)
+	apiServerClientMap  = make(map[v1alpha1.CloudTargetType]rest.Interface)
+	endpointSliceClient rest.Interface
+	webhookConfigClient rest.Interface
+	measureClient       rest.Interface
 )
 
 func GetApiServerClientMap(targetType v1alpha1.CloudTargetType) rest.Interface {
 	return apiServerClientMap[targetType]
 }
 
+func GetEndpointSliceClient() rest.Interface {
+	return endpointSliceClient
+}
+
+func GetWebhookConfigClient() rest.Interface {
+	return webhookConfigClient
+}
+
+func GetMeasureClient() rest.Interface {
+	return measureClient
+}
+
 func SetApiServerClientMap(c *rest.Config, s *runtime.Scheme, t []v1alpha1.CloudTargetType) error {
 	for _, unitTarget := range t {
 		e, err := newClient(unitTarget, c, s)
@@ -42,6 +57,24 @@ func SetApiServerClientMap(c *rest.Config, s *runtime.Scheme, t []v1alpha1.Cloud
 		}
 
 		apiServerClientMap[unitTarget] = e
+
+		// the faults of service need to operate the EndpointSlices which are not a cloud target
+		if unitTarget == v1alpha1.ServiceCloudTarget {
+			if endpointSliceClient, err = newRESTClientForGVK("discovery.k8s.io", "v1", "EndpointSlice", c, s); err != nil {
+				return fmt.Errorf("create apiserver client for endpointslice error: %s", err.Error())
+			}
+		}
+	}
+
+	// the api chaos of cluster registers ValidatingWebhookConfigurations, which are not a cloud target
+	var err error
+	if webhookConfigClient, err = newRESTClientForGVK("admissionregistration.k8s.io", "v1", "ValidatingWebhookConfiguration", c, s); err != nil {
+		return fmt.Errorf("create apiserver client for validatingwebhookconfiguration error: %s", err.Error())
+	}
+
+	// the pause condition of rollout reads the CommonMeasures of measure operator
+	if measureClient, err = newRESTClientForGVK("chaosmeta.io", "v1alpha1", "CommonMeasure", c, s); err != nil {
+		return fmt.Errorf("create apiserver client for commonmeasure error: %s", err.Error())
 	}
 
 	return nil
@@ -53,12 +86,22 @@ func newClient(target v1alpha1.CloudTargetType, c *rest.Config, s *runtime.Schem
 		e, err = newRESTClientForGVK("", "v1", "Pod", c, s)
 	case v1alpha1.DeploymentCloudTarget:
 		e, err = newRESTClientForGVK("apps", "v1", "Deployment", c, s)
+	case v1alpha1.StatefulsetCloudTarget:
+		e, err = newRESTClientForGVK("apps", "v1", "StatefulSet", c, s)
+	case v1alpha1.DaemonsetCloudTarget:
+		e, err = newRESTClientForGVK("apps", "v1", "DaemonSet", c, s)
 	case v1alpha1.NodeCloudTarget:
 		e, err = newRESTClientForGVK("", "v1", "Node", c, s)
 	case v1alpha1.NamespaceCloudTarget:
 		e, err = newRESTClientForGVK("", "v1", "Namespace", c, s)
 	case v1alpha1.JobCloudTarget:
 		e, err = newRESTClientForGVK("batch", "v1", "Job", c, s)
+	case v1alpha1.ServiceCloudTarget:
+		e, err = newRESTClientForGVK("", "v1", "Service", c, s)
+	case v1alpha1.ConfigMapCloudTarget:
+		e, err = newRESTClientForGVK("", "v1", "ConfigMap", c, s)
+	case v1alpha1.SecretCloudTarget:
+		e, err = newRESTClientForGVK("", "v1", "Secret", c, s)
 	default:
 		err = fmt.Errorf("not support target: %s", target)
 	}
@@ -43,6 +43,20 @@ func (k KubernetesScopeHandler) ConvertSelector(ctx context.Context, spec *v1alp
 		return pod.GetGlobalPodHandler().ConvertSelector(ctx, spec)
 	case v1alpha1.DeploymentCloudTarget:
 		return convertDeploy(ctx, spec)
+	case v1alpha1.StatefulsetCloudTarget:
+		return convertStatefulSet(ctx, spec)
+	case v1alpha1.DaemonsetCloudTarget:
+		return convertDaemonSet(ctx, spec)
+	case v1alpha1.JobCloudTarget:
+		return convertJob(ctx, spec)
+	case v1alpha1.ServiceCloudTarget:
+		return convertService(ctx, spec)
+	case v1alpha1.ConfigMapCloudTarget:
+		return convertConfigMap(ctx, spec)
+	case v1alpha1.SecretCloudTarget:
+		return convertSecret(ctx, spec)
+	case v1alpha1.NamespaceCloudTarget:
+		return convertNamespace(ctx, spec)
 	case v1alpha1.NodeCloudTarget:
 		return node.GetGlobalNodeHandler().ConvertSelector(ctx, spec)
 	case v1alpha1.ClusterCloudTarget:
@@ -66,6 +80,70 @@ func (k KubernetesScopeHandler) GetInjectObject(ctx context.Context, exp *v1alph
 			Namespace:      ns,
 			DeploymentName: name,
 		}, nil
+	case v1alpha1.StatefulsetCloudTarget:
+		ns, name, err := model.ParseStatefulSetInfo(objectName)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected statefulset object name: %s", objectName)
+		}
+
+		return &model.StatefulSetObject{
+			Namespace:       ns,
+			StatefulSetName: name,
+		}, nil
+	case v1alpha1.DaemonsetCloudTarget:
+		ns, name, err := model.ParseDaemonSetInfo(objectName)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected daemonset object name: %s", objectName)
+		}
+
+		return &model.DaemonSetObject{
+			Namespace:     ns,
+			DaemonSetName: name,
+		}, nil
+	case v1alpha1.JobCloudTarget:
+		ns, name, err := model.ParseJobInfo(objectName)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected job object name: %s", objectName)
+		}
+
+		return &model.JobObject{
+			Namespace: ns,
+			JobName:   name,
+		}, nil
+	case v1alpha1.ServiceCloudTarget:
+		ns, name, err := model.ParseServiceInfo(objectName)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected service object name: %s", objectName)
+		}
+
+		return &model.ServiceObject{
+			Namespace:   ns,
+			ServiceName: name,
+		}, nil
+	case v1alpha1.ConfigMapCloudTarget:
+		ns, name, err := model.ParseConfigMapInfo(objectName)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected configmap object name: %s", objectName)
+		}
+
+		return &model.ConfigMapObject{
+			Namespace:     ns,
+			ConfigMapName: name,
+		}, nil
+	case v1alpha1.SecretCloudTarget:
+		ns, name, err := model.ParseSecretInfo(objectName)
+		if err != nil {
+			return nil, fmt.Errorf("unexpected secret object name: %s", objectName)
+		}
+
+		return &model.SecretObject{
+			Namespace:  ns,
+			SecretName: name,
+		}, nil
+	case v1alpha1.NamespaceCloudTarget:
+		return &model.NamespaceObject{
+			Namespace: objectName,
+		}, nil
 	case v1alpha1.NodeCloudTarget:
 		return node.GetGlobalNodeHandler().GetInjectObject(ctx, exp, objectName)
 	case v1alpha1.ClusterCloudTarget:
@@ -142,7 +220,12 @@ func getDeployObjectFromSelector(ctx context.Context, selectorUnit v1alpha1.Sele
 	var err error
 	analyzer := selector.GetAnalyzer()
 	var reList []*model.DeploymentObject
-	if len(selectorUnit.Name) != 0 {
+	if selectorUnit.IsExtended() {
+		reList, err = analyzer.GetDeploymentListBySelector(ctx, selectorUnit)
+		if err != nil {
+			return nil, fmt.Errorf("get deployment info by selector error: %s", err.Error())
+		}
+	} else if len(selectorUnit.Name) != 0 {
 		reList, err = analyzer.GetDeploymentListByName(ctx, selectorUnit.Namespace, selectorUnit.Name)
 		if err != nil {
 			return nil, fmt.Errorf("get pod info by podname list error: %s", err.Error())
@@ -161,3 +244,379 @@ func getDeployObjectFromSelector(ctx context.Context, selectorUnit v1alpha1.Sele
 
 	return result, err
 }
+
+func convertStatefulSet(ctx context.Context, spec *v1alpha1.ExperimentSpec) ([]model.AtomicObject, error) {
+	var (
+		result  []model.AtomicObject
+		isExist = make(map[string]bool)
+	)
+
+	for _, unitSelector := range spec.Selector {
+		if unitSelector.Namespace == "" {
+			return nil, fmt.Errorf("selector of scope statefulset must provide namespace")
+		}
+
+		resultUnitSelector, err := getStatefulSetObjectFromSelector(ctx, unitSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, unitObj := range resultUnitSelector {
+			// Deduplication
+			if isExist[unitObj.GetObjectName()] {
+				continue
+			}
+			isExist[unitObj.GetObjectName()] = true
+			result = append(result, unitObj)
+		}
+	}
+
+	return result, nil
+}
+
+func getStatefulSetObjectFromSelector(ctx context.Context, selectorUnit v1alpha1.SelectorUnit) ([]model.AtomicObject, error) {
+	var err error
+	analyzer := selector.GetAnalyzer()
+	var reList []*model.StatefulSetObject
+	if len(selectorUnit.Name) != 0 {
+		reList, err = analyzer.GetStatefulSetListByName(ctx, selectorUnit.Namespace, selectorUnit.Name)
+		if err != nil {
+			return nil, fmt.Errorf("get statefulset info by name list error: %s", err.Error())
+		}
+	} else {
+		reList, err = analyzer.GetStatefulSetListByLabel(ctx, selectorUnit.Namespace, selectorUnit.Label)
+		if err != nil {
+			return nil, fmt.Errorf("get statefulset info by label error: %s", err.Error())
+		}
+	}
+
+	var result = make([]model.AtomicObject, len(reList))
+	for i := range reList {
+		result[i] = reList[i]
+	}
+
+	return result, err
+}
+
+func convertDaemonSet(ctx context.Context, spec *v1alpha1.ExperimentSpec) ([]model.AtomicObject, error) {
+	var (
+		result  []model.AtomicObject
+		isExist = make(map[string]bool)
+	)
+
+	for _, unitSelector := range spec.Selector {
+		if unitSelector.Namespace == "" {
+			return nil, fmt.Errorf("selector of scope daemonset must provide namespace")
+		}
+
+		resultUnitSelector, err := getDaemonSetObjectFromSelector(ctx, unitSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, unitObj := range resultUnitSelector {
+			// Deduplication
+			if isExist[unitObj.GetObjectName()] {
+				continue
+			}
+			isExist[unitObj.GetObjectName()] = true
+			result = append(result, unitObj)
+		}
+	}
+
+	return result, nil
+}
+
+func getDaemonSetObjectFromSelector(ctx context.Context, selectorUnit v1alpha1.SelectorUnit) ([]model.AtomicObject, error) {
+	var err error
+	analyzer := selector.GetAnalyzer()
+	var reList []*model.DaemonSetObject
+	if len(selectorUnit.Name) != 0 {
+		reList, err = analyzer.GetDaemonSetListByName(ctx, selectorUnit.Namespace, selectorUnit.Name)
+		if err != nil {
+			return nil, fmt.Errorf("get daemonset info by name list error: %s", err.Error())
+		}
+	} else {
+		reList, err = analyzer.GetDaemonSetListByLabel(ctx, selectorUnit.Namespace, selectorUnit.Label)
+		if err != nil {
+			return nil, fmt.Errorf("get daemonset info by label error: %s", err.Error())
+		}
+	}
+
+	var result = make([]model.AtomicObject, len(reList))
+	for i := range reList {
+		result[i] = reList[i]
+	}
+
+	return result, err
+}
+
+func convertJob(ctx context.Context, spec *v1alpha1.ExperimentSpec) ([]model.AtomicObject, error) {
+	var (
+		result  []model.AtomicObject
+		isExist = make(map[string]bool)
+	)
+
+	for _, unitSelector := range spec.Selector {
+		if unitSelector.Namespace == "" {
+			return nil, fmt.Errorf("selector of scope job must provide namespace")
+		}
+
+		resultUnitSelector, err := getJobObjectFromSelector(ctx, unitSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, unitObj := range resultUnitSelector {
+			// Deduplication
+			if isExist[unitObj.GetObjectName()] {
+				continue
+			}
+			isExist[unitObj.GetObjectName()] = true
+			result = append(result, unitObj)
+		}
+	}
+
+	return result, nil
+}
+
+func getJobObjectFromSelector(ctx context.Context, selectorUnit v1alpha1.SelectorUnit) ([]model.AtomicObject, error) {
+	var err error
+	analyzer := selector.GetAnalyzer()
+	var reList []*model.JobObject
+	if len(selectorUnit.Name) != 0 {
+		reList, err = analyzer.GetJobListByName(ctx, selectorUnit.Namespace, selectorUnit.Name)
+		if err != nil {
+			return nil, fmt.Errorf("get job info by name list error: %s", err.Error())
+		}
+	} else {
+		reList, err = analyzer.GetJobListByLabel(ctx, selectorUnit.Namespace, selectorUnit.Label)
+		if err != nil {
+			return nil, fmt.Errorf("get job info by label error: %s", err.Error())
+		}
+	}
+
+	var result = make([]model.AtomicObject, len(reList))
+	for i := range reList {
+		result[i] = reList[i]
+	}
+
+	return result, err
+}
+
+func convertService(ctx context.Context, spec *v1alpha1.ExperimentSpec) ([]model.AtomicObject, error) {
+	var (
+		result  []model.AtomicObject
+		isExist = make(map[string]bool)
+	)
+
+	for _, unitSelector := range spec.Selector {
+		if unitSelector.Namespace == "" {
+			return nil, fmt.Errorf("selector of scope service must provide namespace")
+		}
+
+		resultUnitSelector, err := getServiceObjectFromSelector(ctx, unitSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, unitObj := range resultUnitSelector {
+			// Deduplication
+			if isExist[unitObj.GetObjectName()] {
+				continue
+			}
+			isExist[unitObj.GetObjectName()] = true
+			result = append(result, unitObj)
+		}
+	}
+
+	return result, nil
+}
+
+func getServiceObjectFromSelector(ctx context.Context, selectorUnit v1alpha1.SelectorUnit) ([]model.AtomicObject, error) {
+	var err error
+	analyzer := selector.GetAnalyzer()
+	var reList []*model.ServiceObject
+	if len(selectorUnit.Name) != 0 {
+		reList, err = analyzer.GetServiceListByName(ctx, selectorUnit.Namespace, selectorUnit.Name)
+		if err != nil {
+			return nil, fmt.Errorf("get service info by name list error: %s", err.Error())
+		}
+	} else {
+		reList, err = analyzer.GetServiceListByLabel(ctx, selectorUnit.Namespace, selectorUnit.Label)
+		if err != nil {
+			return nil, fmt.Errorf("get service info by label error: %s", err.Error())
+		}
+	}
+
+	var result = make([]model.AtomicObject, len(reList))
+	for i := range reList {
+		result[i] = reList[i]
+	}
+
+	return result, err
+}
+
+func convertConfigMap(ctx context.Context, spec *v1alpha1.ExperimentSpec) ([]model.AtomicObject, error) {
+	var (
+		result  []model.AtomicObject
+		isExist = make(map[string]bool)
+	)
+
+	for _, unitSelector := range spec.Selector {
+		if unitSelector.Namespace == "" {
+			return nil, fmt.Errorf("selector of scope configmap must provide namespace")
+		}
+
+		resultUnitSelector, err := getConfigMapObjectFromSelector(ctx, unitSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, unitObj := range resultUnitSelector {
+			// Deduplication
+			if isExist[unitObj.GetObjectName()] {
+				continue
+			}
+			isExist[unitObj.GetObjectName()] = true
+			result = append(result, unitObj)
+		}
+	}
+
+	return result, nil
+}
+
+func getConfigMapObjectFromSelector(ctx context.Context, selectorUnit v1alpha1.SelectorUnit) ([]model.AtomicObject, error) {
+	var err error
+	analyzer := selector.GetAnalyzer()
+	var reList []*model.ConfigMapObject
+	if len(selectorUnit.Name) != 0 {
+		reList, err = analyzer.GetConfigMapListByName(ctx, selectorUnit.Namespace, selectorUnit.Name)
+		if err != nil {
+			return nil, fmt.Errorf("get configmap info by name list error: %s", err.Error())
+		}
+	} else {
+		reList, err = analyzer.GetConfigMapListByLabel(ctx, selectorUnit.Namespace, selectorUnit.Label)
+		if err != nil {
+			return nil, fmt.Errorf("get configmap info by label error: %s", err.Error())
+		}
+	}
+
+	var result = make([]model.AtomicObject, len(reList))
+	for i := range reList {
+		result[i] = reList[i]
+	}
+
+	return result, err
+}
+
+func convertSecret(ctx context.Context, spec *v1alpha1.ExperimentSpec) ([]model.AtomicObject, error) {
+	var (
+		result  []model.AtomicObject
+		isExist = make(map[string]bool)
+	)
+
+	for _, unitSelector := range spec.Selector {
+		if unitSelector.Namespace == "" {
+			return nil, fmt.Errorf("selector of scope secret must provide namespace")
+		}
+
+		resultUnitSelector, err := getSecretObjectFromSelector(ctx, unitSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, unitObj := range resultUnitSelector {
+			// Deduplication
+			if isExist[unitObj.GetObjectName()] {
+				continue
+			}
+			isExist[unitObj.GetObjectName()] = true
+			result = append(result, unitObj)
+		}
+	}
+
+	return result, nil
+}
+
+func getSecretObjectFromSelector(ctx context.Context, selectorUnit v1alpha1.SelectorUnit) ([]model.AtomicObject, error) {
+	var err error
+	analyzer := selector.GetAnalyzer()
+	var reList []*model.SecretObject
+	if len(selectorUnit.Name) != 0 {
+		reList, err = analyzer.GetSecretListByName(ctx, selectorUnit.Namespace, selectorUnit.Name)
+		if err != nil {
+			return nil, fmt.Errorf("get secret info by name list error: %s", err.Error())
+		}
+	} else {
+		reList, err = analyzer.GetSecretListByLabel(ctx, selectorUnit.Namespace, selectorUnit.Label)
+		if err != nil {
+			return nil, fmt.Errorf("get secret info by label error: %s", err.Error())
+		}
+	}
+
+	var result = make([]model.AtomicObject, len(reList))
+	for i := range reList {
+		result[i] = reList[i]
+	}
+
+	return result, err
+}
+
+func convertNamespace(ctx context.Context, spec *v1alpha1.ExperimentSpec) ([]model.AtomicObject, error) {
+	var (
+		result  []model.AtomicObject
+		isExist = make(map[string]bool)
+	)
+
+	for _, unitSelector := range spec.Selector {
+		resultUnitSelector, err := getNamespaceObjectFromSelector(ctx, unitSelector)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, unitObj := range resultUnitSelector {
+			// Deduplication
+			if isExist[unitObj.GetObjectName()] {
+				continue
+			}
+			isExist[unitObj.GetObjectName()] = true
+			result = append(result, unitObj)
+		}
+	}
+
+	return result, nil
+}
+
+// getNamespaceObjectFromSelector treats Name as namespace names and Label as namespace labels.
+// A selector with only Namespace set selects that namespace.
+func getNamespaceObjectFromSelector(ctx context.Context, selectorUnit v1alpha1.SelectorUnit) ([]model.AtomicObject, error) {
+	var err error
+	analyzer := selector.GetAnalyzer()
+	var reList []*model.NamespaceObject
+	if len(selectorUnit.Name) != 0 {
+		reList, err = analyzer.GetNamespaceListByName(ctx, selectorUnit.Name)
+		if err != nil {
+			return nil, fmt.Errorf("get namespace info by name list error: %s", err.Error())
+		}
+	} else if len(selectorUnit.Label) != 0 {
+		reList, err = analyzer.GetNamespaceListByLabel(ctx, selectorUnit.Label)
+		if err != nil {
+			return nil, fmt.Errorf("get namespace info by label error: %s", err.Error())
+		}
+	} else if selectorUnit.Namespace != "" {
+		reList, err = analyzer.GetNamespaceListByName(ctx, []string{selectorUnit.Namespace})
+		if err != nil {
+			return nil, fmt.Errorf("get namespace info by name error: %s", err.Error())
+		}
+	} else {
+		return nil, fmt.Errorf("selector of scope namespace must provide name, label or namespace")
+	}
+
+	var result = make([]model.AtomicObject, len(reList))
+	for i := range reList {
+		result[i] = reList[i]
+	}
+
+	return result, err
+}
@@ -123,7 +123,12 @@ func getPodObjectList(ctx context.Context, selectorUnit v1alpha1.SelectorUnit) (
 	var err error
 	analyzer := selector.GetAnalyzer()
 	var podList []*model.PodObject
-	if len(selectorUnit.Name) != 0 {
+	if selectorUnit.IsExtended() {
+		podList, err = analyzer.GetPodListBySelector(ctx, selectorUnit)
+		if err != nil {
+			return nil, fmt.Errorf("get pod info by selector error: %s", err.Error())
+		}
+	} else if len(selectorUnit.Name) != 0 {
 		podList, err = analyzer.GetPodListByPodName(ctx, selectorUnit.Namespace, selectorUnit.Name, selectorUnit.SubName)
 		if err != nil {
 			return nil, fmt.Errorf("get pod info by podname list error: %s", err.Error())
@@ -0,0 +1,280 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package selector
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"strconv"
+)
+
+// GetPodListBySelector all the conditions of the selector unit are "AND", the empty ones are ignored
+func (a *Analyzer) GetPodListBySelector(ctx context.Context, selectorUnit v1alpha1.SelectorUnit) ([]*model.PodObject, error) {
+	opts, fieldSelector, err := getListOptions(selectorUnit)
+	if err != nil {
+		return nil, err
+	}
+
+	podList := &corev1.PodList{}
+	if err := a.ApiServer.List(ctx, podList, append(opts, client.InNamespace(selectorUnit.Namespace))...); err != nil {
+		return nil, fmt.Errorf("list pod info by selector error: %s", err.Error())
+	}
+
+	var nodeMap map[string]bool
+	if len(selectorUnit.NodeLabel) != 0 {
+		nodeList := &corev1.NodeList{}
+		if err := a.ApiServer.List(ctx, nodeList, client.MatchingLabels(selectorUnit.NodeLabel)); err != nil {
+			return nil, fmt.Errorf("list node by label error: %s", err.Error())
+		}
+
+		nodeMap = make(map[string]bool)
+		for _, unitNode := range nodeList.Items {
+			nodeMap[unitNode.Name] = true
+		}
+	}
+
+	var ownerFilter func(pod *corev1.Pod) bool
+	if selectorUnit.Owner != nil {
+		if ownerFilter, err = a.getPodOwnerFilter(ctx, selectorUnit.Namespace, selectorUnit.Owner); err != nil {
+			return nil, err
+		}
+	}
+
+	nameMap := listToMap(selectorUnit.Name)
+	var result []*model.PodObject
+	for i := range podList.Items {
+		unitPod := &podList.Items[i]
+		if len(nameMap) != 0 && !nameMap[unitPod.Name] {
+			continue
+		}
+
+		if !matchAnnotation(unitPod.Annotations, selectorUnit.Annotation) || !fieldSelector.Matches(getPodFields(unitPod)) {
+			continue
+		}
+
+		if (nodeMap != nil && !nodeMap[unitPod.Spec.NodeName]) || (ownerFilter != nil && !ownerFilter(unitPod)) {
+			continue
+		}
+
+		podInfo := &model.PodObject{
+			PodName:   unitPod.Name,
+			PodUID:    string(unitPod.UID),
+			PodIP:     unitPod.Status.PodIP,
+			Namespace: unitPod.Namespace,
+			NodeName:  unitPod.Spec.NodeName,
+			NodeIP:    unitPod.Status.HostIP,
+		}
+
+		if selectorUnit.SubName != "" {
+			containers, err := GetTargetContainers(selectorUnit.SubName, unitPod.Status.ContainerStatuses)
+			if err != nil {
+				return nil, fmt.Errorf("get target container[%s] in pod[%s] error: %s", selectorUnit.SubName, unitPod.Name, err.Error())
+			}
+			podInfo.Containers = containers
+		}
+
+		result = append(result, podInfo)
+	}
+
+	return result, nil
+}
+
+// GetNodeListBySelector all the conditions of the selector unit are "AND", the empty ones are ignored
+func (a *Analyzer) GetNodeListBySelector(ctx context.Context, selectorUnit v1alpha1.SelectorUnit, containerName string) ([]*model.NodeObject, error) {
+	opts, fieldSelector, err := getListOptions(selectorUnit)
+	if err != nil {
+		return nil, err
+	}
+
+	nodeList := &corev1.NodeList{}
+	if err := a.ApiServer.List(ctx, nodeList, opts...); err != nil {
+		return nil, fmt.Errorf("list node by selector error: %s", err.Error())
+	}
+
+	nameMap, ipMap := listToMap(selectorUnit.Name), listToMap(selectorUnit.IP)
+	var result []*model.NodeObject
+	for i := range nodeList.Items {
+		unitNode := &nodeList.Items[i]
+		if len(nameMap) != 0 && !nameMap[unitNode.Name] {
+			continue
+		}
+
+		if !matchAnnotation(unitNode.Annotations, selectorUnit.Annotation) || !fieldSelector.Matches(getNodeFields(unitNode)) {
+			continue
+		}
+
+		tmpNode := &model.NodeObject{
+			NodeName: unitNode.Name,
+		}
+
+		for _, unitAddress := range unitNode.Status.Addresses {
+			if unitAddress.Type == "InternalIP" {
+				tmpNode.NodeInternalIP = unitAddress.Address
+			} else if unitAddress.Type == "Hostname" {
+				tmpNode.HostName = unitAddress.Address
+			}
+		}
+
+		if len(ipMap) != 0 && !ipMap[tmpNode.NodeInternalIP] {
+			continue
+		}
+
+		if containerName != "" {
+			r, id, err := model.ParseContainerID(containerName)
+			if err != nil {
+				return nil, fmt.Errorf("parse container info error: %s", err.Error())
+			}
+
+			tmpNode.ContainerRuntime, tmpNode.ContainerID = r, id
+		}
+
+		result = append(result, tmpNode)
+	}
+
+	return result, nil
+}
+
+// GetDeploymentListBySelector all the conditions of the selector unit are "AND", the empty ones are ignored
+func (a *Analyzer) GetDeploymentListBySelector(ctx context.Context, selectorUnit v1alpha1.SelectorUnit) ([]*model.DeploymentObject, error) {
+	opts, fieldSelector, err := getListOptions(selectorUnit)
+	if err != nil {
+		return nil, err
+	}
+
+	deployList := &appsv1.DeploymentList{}
+	if err := a.ApiServer.List(ctx, deployList, append(opts, client.InNamespace(selectorUnit.Namespace))...); err != nil {
+		return nil, fmt.Errorf("list deployment info by selector error: %s", err.Error())
+	}
+
+	nameMap := listToMap(selectorUnit.Name)
+	var result []*model.DeploymentObject
+	for i := range deployList.Items {
+		unitDeploy := &deployList.Items[i]
+		if len(nameMap) != 0 && !nameMap[unitDeploy.Name] {
+			continue
+		}
+
+		if !matchAnnotation(unitDeploy.Annotations, selectorUnit.Annotation) || !fieldSelector.Matches(getDeploymentFields(unitDeploy)) {
+			continue
+		}
+
+		result = append(result, &model.DeploymentObject{
+			DeploymentName: unitDeploy.Name,
+			Namespace:      unitDeploy.Namespace,
+		})
+	}
+
+	return result, nil
+}
+
+// getPodOwnerFilter the pods of deployment are controlled by the replicasets controlled by the deployment. The
+// replicasets are keyed by namespace too, because they are listed in all namespaces if namespace is empty
+func (a *Analyzer) getPodOwnerFilter(ctx context.Context, namespace string, owner *v1alpha1.OwnerSelector) (func(pod *corev1.Pod) bool, error) {
+	ownerNameMap := listToMap(owner.Name)
+	if owner.Kind != "Deployment" {
+		return func(pod *corev1.Pod) bool {
+			ref := metav1.GetControllerOf(pod)
+			return ref != nil && ref.Kind == owner.Kind && ownerNameMap[ref.Name]
+		}, nil
+	}
+
+	rsList := &appsv1.ReplicaSetList{}
+	if err := a.ApiServer.List(ctx, rsList, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("list replicaset error: %s", err.Error())
+	}
+
+	rsMap := make(map[types.NamespacedName]bool)
+	for i := range rsList.Items {
+		ref := metav1.GetControllerOf(&rsList.Items[i])
+		if ref != nil && ref.Kind == owner.Kind && ownerNameMap[ref.Name] {
+			rsMap[types.NamespacedName{Namespace: rsList.Items[i].Namespace, Name: rsList.Items[i].Name}] = true
+		}
+	}
+
+	return func(pod *corev1.Pod) bool {
+		ref := metav1.GetControllerOf(pod)
+		return ref != nil && ref.Kind == "ReplicaSet" && rsMap[types.NamespacedName{Namespace: pod.Namespace, Name: ref.Name}]
+	}, nil
+}
+
+// getListOptions the labels are filtered by the apiserver, the fields are filtered locally because the fields
+// supported by apiserver are different between resources and the cache of client only supports indexed fields
+func getListOptions(selectorUnit v1alpha1.SelectorUnit) ([]client.ListOption, fields.Selector, error) {
+	labelSelector, err := selectorUnit.GetLabelSelector()
+	if err != nil {
+		return nil, nil, fmt.Errorf("label selector is invalid: %s", err.Error())
+	}
+
+	fieldSelector, err := selectorUnit.GetFieldSelector()
+	if err != nil {
+		return nil, nil, fmt.Errorf("field selector is invalid: %s", err.Error())
+	}
+
+	return []client.ListOption{client.MatchingLabelsSelector{Selector: labelSelector}}, fieldSelector, nil
+}
+
+func matchAnnotation(annotations, target map[string]string) bool {
+	for k, v := range target {
+		if value, ok := annotations[k]; !ok || value != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+func listToMap(list []string) map[string]bool {
+	re := make(map[string]bool)
+	for _, unit := range list {
+		re[unit] = true
+	}
+
+	return re
+}
+
+func getPodFields(pod *corev1.Pod) fields.Set {
+	return fields.Set{
+		v1alpha1.FieldName:      pod.Name,
+		v1alpha1.FieldNamespace: pod.Namespace,
+		v1alpha1.FieldNodeName:  pod.Spec.NodeName,
+		v1alpha1.FieldPhase:     string(pod.Status.Phase),
+		v1alpha1.FieldPodIP:     pod.Status.PodIP,
+		v1alpha1.FieldHostIP:    pod.Status.HostIP,
+	}
+}
+
+func getNodeFields(node *corev1.Node) fields.Set {
+	return fields.Set{
+		v1alpha1.FieldName:          node.Name,
+		v1alpha1.FieldUnschedulable: strconv.FormatBool(node.Spec.Unschedulable),
+	}
+}
+
+func getDeploymentFields(deploy *appsv1.Deployment) fields.Set {
+	return fields.Set{
+		v1alpha1.FieldName:      deploy.Name,
+		v1alpha1.FieldNamespace: deploy.Namespace,
+	}
+}
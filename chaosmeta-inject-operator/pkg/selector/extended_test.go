@@ -0,0 +1,218 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package selector
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/api/v1alpha1"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"testing"
+)
+
+func newTestAnalyzer(objs ...client.Object) *Analyzer {
+	scheme := runtime.NewScheme()
+	_ = corev1.AddToScheme(scheme)
+	_ = appsv1.AddToScheme(scheme)
+	return &Analyzer{ApiServer: fake.NewClientBuilder().WithScheme(scheme).WithObjects(objs...).Build()}
+}
+
+func newOwnerRef(kind, name string) []metav1.OwnerReference {
+	isController := true
+	return []metav1.OwnerReference{{Kind: kind, Name: name, Controller: &isController}}
+}
+
+func TestAnalyzer_GetPodListBySelector(t *testing.T) {
+	objs := []client.Object{
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n1", Labels: map[string]string{"zone": "a"}}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "n2", Labels: map[string]string{"zone": "b"}}},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "foo-1", OwnerReferences: newOwnerRef("Deployment", "foo")}},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "bar-1", OwnerReferences: newOwnerRef("Deployment", "bar")}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "foo-1-a", Labels: map[string]string{"app": "foo", "tier": "web"},
+				Annotations: map[string]string{"team": "x"}, OwnerReferences: newOwnerRef("ReplicaSet", "foo-1")},
+			Spec:   corev1.PodSpec{NodeName: "n1"},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "foo-1-b", Labels: map[string]string{"app": "foo"},
+				OwnerReferences: newOwnerRef("ReplicaSet", "foo-1")},
+			Spec:   corev1.PodSpec{NodeName: "n2"},
+			Status: corev1.PodStatus{Phase: corev1.PodPending},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "bar-1-a", Labels: map[string]string{"app": "bar"},
+				OwnerReferences: newOwnerRef("ReplicaSet", "bar-1")},
+			Spec:   corev1.PodSpec{NodeName: "n1"},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "db-0", Labels: map[string]string{"app": "db"},
+				OwnerReferences: newOwnerRef("StatefulSet", "db")},
+			Spec:   corev1.PodSpec{NodeName: "n2"},
+			Status: corev1.PodStatus{Phase: corev1.PodRunning},
+		},
+	}
+	a := newTestAnalyzer(objs...)
+
+	tests := []struct {
+		name string
+		unit v1alpha1.SelectorUnit
+		want []string
+	}{
+		{
+			name: "expression_in",
+			unit: v1alpha1.SelectorUnit{LabelExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "app", Operator: metav1.LabelSelectorOpIn, Values: []string{"foo", "db"}}}},
+			want: []string{"db-0", "foo-1-a", "foo-1-b"},
+		},
+		{
+			name: "expression_with_label",
+			unit: v1alpha1.SelectorUnit{Label: map[string]string{"app": "foo"}, LabelExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "tier", Operator: metav1.LabelSelectorOpExists}}},
+			want: []string{"foo-1-a"},
+		},
+		{
+			name: "expression_not_in",
+			unit: v1alpha1.SelectorUnit{LabelExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "app", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"foo"}}}},
+			want: []string{"bar-1-a", "db-0"},
+		},
+		{name: "annotation", unit: v1alpha1.SelectorUnit{Annotation: map[string]string{"team": "x"}}, want: []string{"foo-1-a"}},
+		{name: "field", unit: v1alpha1.SelectorUnit{Field: "spec.nodeName=n2,status.phase=Running"}, want: []string{"db-0"}},
+		{name: "field_not_equal", unit: v1alpha1.SelectorUnit{Field: "status.phase!=Running"}, want: []string{"foo-1-b"}},
+		{name: "owner_deployment", unit: v1alpha1.SelectorUnit{Owner: &v1alpha1.OwnerSelector{Kind: "Deployment", Name: []string{"foo"}}}, want: []string{"foo-1-a", "foo-1-b"}},
+		{name: "owner_statefulset", unit: v1alpha1.SelectorUnit{Owner: &v1alpha1.OwnerSelector{Kind: "StatefulSet", Name: []string{"db"}}}, want: []string{"db-0"}},
+		{name: "node_label", unit: v1alpha1.SelectorUnit{NodeLabel: map[string]string{"zone": "a"}}, want: []string{"bar-1-a", "foo-1-a"}},
+		{
+			name: "owner_and_node_label",
+			unit: v1alpha1.SelectorUnit{Owner: &v1alpha1.OwnerSelector{Kind: "Deployment", Name: []string{"foo"}}, NodeLabel: map[string]string{"zone": "b"}},
+			want: []string{"foo-1-b"},
+		},
+		{name: "name_and_field", unit: v1alpha1.SelectorUnit{Name: []string{"foo-1-a", "db-0"}, Field: "spec.nodeName=n1"}, want: []string{"foo-1-a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.unit.Namespace = "ns"
+			re, err := a.GetPodListBySelector(context.Background(), tt.unit)
+			assert.NoError(t, err)
+			var names []string
+			for _, pod := range re {
+				names = append(names, pod.PodName)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestAnalyzer_GetPodListBySelector_OwnerInAllNamespaces(t *testing.T) {
+	a := newTestAnalyzer(
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "foo-1", OwnerReferences: newOwnerRef("Deployment", "foo")}},
+		&appsv1.ReplicaSet{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "foo-1", OwnerReferences: newOwnerRef("Deployment", "bar")}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "foo-1-a", OwnerReferences: newOwnerRef("ReplicaSet", "foo-1")}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "foo-1-b", OwnerReferences: newOwnerRef("ReplicaSet", "foo-1")}},
+	)
+
+	// the replicaset with the same name in another namespace is controlled by another deployment
+	re, err := a.GetPodListBySelector(context.Background(), v1alpha1.SelectorUnit{Owner: &v1alpha1.OwnerSelector{Kind: "Deployment", Name: []string{"foo"}}})
+	assert.NoError(t, err)
+	assert.Len(t, re, 1)
+	assert.Equal(t, "ns", re[0].Namespace)
+	assert.Equal(t, "foo-1-a", re[0].PodName)
+}
+
+func TestAnalyzer_GetNodeListBySelector(t *testing.T) {
+	a := newTestAnalyzer(
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "n1", Labels: map[string]string{"zone": "a"}, Annotations: map[string]string{"rack": "1"}},
+			Status:     corev1.NodeStatus{Addresses: []corev1.NodeAddress{{Type: "InternalIP", Address: "1.1.1.1"}}},
+		},
+		&corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{Name: "n2", Labels: map[string]string{"zone": "b"}},
+			Spec:       corev1.NodeSpec{Unschedulable: true},
+			Status:     corev1.NodeStatus{Addresses: []corev1.NodeAddress{{Type: "InternalIP", Address: "1.1.1.2"}}},
+		},
+	)
+
+	tests := []struct {
+		name string
+		unit v1alpha1.SelectorUnit
+		want []string
+	}{
+		{
+			name: "expression",
+			unit: v1alpha1.SelectorUnit{LabelExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "zone", Operator: metav1.LabelSelectorOpNotIn, Values: []string{"a"}}}},
+			want: []string{"n2"},
+		},
+		{name: "annotation", unit: v1alpha1.SelectorUnit{Annotation: map[string]string{"rack": "1"}}, want: []string{"n1"}},
+		{name: "field", unit: v1alpha1.SelectorUnit{Field: "spec.unschedulable=false"}, want: []string{"n1"}},
+		{name: "ip_and_field", unit: v1alpha1.SelectorUnit{IP: []string{"1.1.1.2"}, Field: "metadata.name=n1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			re, err := a.GetNodeListBySelector(context.Background(), tt.unit, "")
+			assert.NoError(t, err)
+			var names []string
+			for _, node := range re {
+				names = append(names, node.NodeName)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
+
+func TestAnalyzer_GetDeploymentListBySelector(t *testing.T) {
+	a := newTestAnalyzer(
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "foo", Labels: map[string]string{"app": "foo"}}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "bar", Labels: map[string]string{"app": "bar"},
+			Annotations: map[string]string{"team": "x"}}},
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "other", Name: "baz", Labels: map[string]string{"app": "baz"}}},
+	)
+
+	tests := []struct {
+		name string
+		unit v1alpha1.SelectorUnit
+		want []string
+	}{
+		{
+			name: "expression_exists",
+			unit: v1alpha1.SelectorUnit{LabelExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "app", Operator: metav1.LabelSelectorOpExists}}},
+			want: []string{"bar", "foo"},
+		},
+		{name: "annotation", unit: v1alpha1.SelectorUnit{Annotation: map[string]string{"team": "x"}}, want: []string{"bar"}},
+		{name: "field", unit: v1alpha1.SelectorUnit{Field: "metadata.name!=bar"}, want: []string{"foo"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.unit.Namespace = "ns"
+			re, err := a.GetDeploymentListBySelector(context.Background(), tt.unit)
+			assert.NoError(t, err)
+			var names []string
+			for _, deploy := range re {
+				names = append(names, deploy.DeploymentName)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
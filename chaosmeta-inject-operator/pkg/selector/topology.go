@@ -0,0 +1,134 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package selector
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmeta-inject-operator/pkg/model"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// zoneLabels the labels of node in order of priority
+var zoneLabels = []string{corev1.LabelTopologyZone, corev1.LabelFailureDomainBetaZone}
+
+// GetTopologies return the topology of the objects in a map whose key is the object name.
+// Only pod and node have topology, the others get an empty one
+func (a *Analyzer) GetTopologies(ctx context.Context, objects []model.AtomicObject) (map[string]*model.Topology, error) {
+	var (
+		re       = make(map[string]*model.Topology)
+		nodeZone = make(map[string]string)
+		rsOwner  = make(map[string]string)
+	)
+
+	getZone := func(nodeName string) (string, error) {
+		if nodeName == "" {
+			return "", nil
+		}
+
+		if zone, ok := nodeZone[nodeName]; ok {
+			return zone, nil
+		}
+
+		node := &corev1.Node{}
+		if err := a.ApiServer.Get(ctx, client.ObjectKey{Name: nodeName}, node); err != nil {
+			return "", fmt.Errorf("get node[%s] error: %s", nodeName, err.Error())
+		}
+
+		nodeZone[nodeName] = getNodeZone(node)
+		return nodeZone[nodeName], nil
+	}
+
+	for _, obj := range objects {
+		var ns, podName, nodeName string
+		switch o := obj.(type) {
+		case *model.ContainerObject:
+			ns, podName, nodeName = o.Namespace, o.PodName, o.NodeName
+		case *model.PodObject:
+			ns, podName, nodeName = o.Namespace, o.PodName, o.NodeName
+		case *model.NodeObject:
+			nodeName = o.NodeName
+		default:
+			re[obj.GetObjectName()] = &model.Topology{}
+			continue
+		}
+
+		zone, err := getZone(nodeName)
+		if err != nil {
+			return nil, err
+		}
+
+		topology := &model.Topology{Zone: zone, Node: nodeName}
+		if podName != "" {
+			pod := &corev1.Pod{}
+			if err := a.ApiServer.Get(ctx, client.ObjectKey{Namespace: ns, Name: podName}, pod); err != nil {
+				return nil, fmt.Errorf("get pod[%s/%s] error: %s", ns, podName, err.Error())
+			}
+
+			if topology.Workload, err = a.getPodWorkload(ctx, pod, rsOwner); err != nil {
+				return nil, err
+			}
+			topology.Pod = fmt.Sprintf("%s/%s", ns, podName)
+		}
+
+		re[obj.GetObjectName()] = topology
+	}
+
+	return re, nil
+}
+
+// getPodWorkload return empty if the pod has no controller. rsOwner caches the workload of replicaset
+func (a *Analyzer) getPodWorkload(ctx context.Context, pod *corev1.Pod, rsOwner map[string]string) (string, error) {
+	ref := metav1.GetControllerOf(pod)
+	if ref == nil {
+		return "", nil
+	}
+
+	workload := fmt.Sprintf("%s/%s/%s", ref.Kind, pod.Namespace, ref.Name)
+	if ref.Kind != "ReplicaSet" {
+		return workload, nil
+	}
+
+	if owner, ok := rsOwner[workload]; ok {
+		return owner, nil
+	}
+
+	rs := &appsv1.ReplicaSet{}
+	if err := a.ApiServer.Get(ctx, client.ObjectKey{Namespace: pod.Namespace, Name: ref.Name}, rs); err != nil {
+		return "", fmt.Errorf("get replicaset[%s/%s] error: %s", pod.Namespace, ref.Name, err.Error())
+	}
+
+	rsOwner[workload] = workload
+	if rsRef := metav1.GetControllerOf(rs); rsRef != nil {
+		rsOwner[workload] = fmt.Sprintf("%s/%s/%s", rsRef.Kind, pod.Namespace, rsRef.Name)
+	}
+
+	return rsOwner[workload], nil
+}
+
+func getNodeZone(node *corev1.Node) string {
+	for _, label := range zoneLabels {
+		if zone, ok := node.Labels[label]; ok {
+			return zone
+		}
+	}
+
+	return ""
+}
@@ -17,6 +17,7 @@
 package inject
 
 import (
+	"context"
 	"github.com/spf13/cobra"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
 	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/container"
@@ -29,14 +30,20 @@ import (
 	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/kernel"
 	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/mem"
 	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/network"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/plugin"
 	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/process"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 )
 
 // NewInjectCommand injectCmd represents the inject command
 func NewInjectCommand() *cobra.Command {
 	var injectCmd = &cobra.Command{
-		Use:   "inject",
-		Short: "experiment create command",
+		Use:         "inject",
+		Short:       "experiment create command",
+		Annotations: map[string]string{plugin.LoadAnnotation: "true"},
+		PersistentPreRun: func(cmd *cobra.Command, args []string) {
+			plugin.LogLoadErrors(utils.GetCtxWithTraceId(context.Background(), utils.TraceId))
+		},
 	}
 
 	targets := injector.GetTargets()
@@ -49,6 +56,11 @@ func NewInjectCommand() *cobra.Command {
 	injectCmd.PersistentFlags().StringVar(&args.ContainerId, "container-id", "", "if attack a container of local host, need to provide the container id of target container")
 
 	injectCmd.PersistentFlags().StringVar(&args.Uid, "uid", "", "if not provide, it will automatically generate an uid")
+	injectCmd.PersistentFlags().StringVar(&args.Intermittent.On, "on", "", "intermittent mode, the duration of fault injected in each cycle, support unit: \"s、m、h\"(default s)")
+	injectCmd.PersistentFlags().StringVar(&args.Intermittent.Off, "off", "", "intermittent mode, the duration of fault recovered in each cycle, support unit: \"s、m、h\"(default s)")
+	injectCmd.PersistentFlags().StringVar(&args.Intermittent.Jitter, "on-off-jitter", "", "intermittent mode, random jitter added to \"on\" and \"off\", support unit: \"s、m、h\"(default s)")
+	injectCmd.PersistentFlags().Float64Var(&args.Intermittent.Probability, "probability", 0, "intermittent mode, the probability to inject in each cycle, range: (0, 1]（default 1）")
+	injectCmd.PersistentFlags().BoolVar(&args.GuardrailOverride, "guardrail-override", false, "allow to attack the resources protected by guardrail, the violations will be recorded in the experiment")
 	//var args = make([]string, 2)
 	//injectCmd.PersistentFlags().StringVarP(&args[0], "timeout", "t", "", "experiment's duration（default 0, means need to stop manually）")
 	//injectCmd.PersistentFlags().StringVar(&args[1], "creator", "", "experiment's creator（default the cmd exec user）")
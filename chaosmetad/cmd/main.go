@@ -19,37 +19,69 @@ package main
 import (
 	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/crashloop"
 	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/inject"
+	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/intermittent"
 	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/query"
 	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/recover"
 	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/server"
+	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/transfer"
 	"github.com/traas-stack/chaosmeta/chaosmetad/cmd/version"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/guardrail"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/plugin"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
 	"os"
 )
 
-// rootCmd represents the base command when called without any subcommands
-var rootCmd = &cobra.Command{
-	Use:   utils.RootName,
-	Short: fmt.Sprintf("a command line client to create %s experiment", utils.RootName),
-}
+func newRootCmd() *cobra.Command {
+	// rootCmd represents the base command when called without any subcommands
+	var rootCmd = &cobra.Command{
+		Use:   utils.RootName,
+		Short: fmt.Sprintf("a command line client to create %s experiment", utils.RootName),
+	}
 
-func initRootCmd() {
 	rootCmd.PersistentFlags().StringVar(&log.Level, "log-level", "info", "value support: debug, info, warn, error")
 	rootCmd.PersistentFlags().StringVar(&log.Path, "log-path", "", "log file's path, eg: /tmp/chaosmetad.log")
 	rootCmd.PersistentFlags().StringVar(&utils.TraceId, "trace-id", "", "trace id")
+	rootCmd.PersistentFlags().StringVar(&guardrail.Path, "guardrail-file", "", "guardrail policy file's path（default [install path]/guardrail.json）")
+	rootCmd.PersistentFlags().StringVar(&storage.DBPath, "db-path", "", "experiment db file's path（default [install path]/chaosmetad.dat）")
 
 	rootCmd.AddCommand(inject.NewInjectCommand())
 	rootCmd.AddCommand(query.NewQueryCommand())
 	rootCmd.AddCommand(recover.NewRecoverCommand())
 	rootCmd.AddCommand(server.NewServerCommand())
 	rootCmd.AddCommand(version.NewVersionCommand())
+	rootCmd.AddCommand(transfer.NewExportCommand())
+	rootCmd.AddCommand(transfer.NewImportCommand())
+	rootCmd.AddCommand(intermittent.NewIntermittentCommand())
+	rootCmd.AddCommand(crashloop.NewCrashLoopCommand())
+
+	return rootCmd
+}
+
+// needPlugin whether the command or its parent is marked by plugin.LoadAnnotation
+func needPlugin(cmd *cobra.Command) bool {
+	for ; cmd != nil; cmd = cmd.Parent() {
+		if cmd.Annotations[plugin.LoadAnnotation] == "true" {
+			return true
+		}
+	}
+
+	return false
 }
 
 func main() {
-	initRootCmd()
+	rootCmd := newRootCmd()
+
+	// the plugins are loaded after all the built-in injectors are registered, and only for the commands which need
+	// them. The commands are built again, so that the inject command contains the faults of plugins
+	if cmd, _, err := rootCmd.Find(os.Args[1:]); err == nil && needPlugin(cmd) {
+		plugin.LoadDefault()
+		rootCmd = newRootCmd()
+	}
 
 	err := rootCmd.Execute()
 	if err != nil {
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/plugin"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/query"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 )
@@ -30,13 +31,25 @@ func NewQueryCommand() *cobra.Command {
 		optionQuery = &query.OptionExpQuery{}
 		ifAll       bool
 		format      string
+		ifWatch     bool
+		revision    int64
+		auditUid    string
 	)
 
 	queryCmd := &cobra.Command{
-		Use:   "query",
-		Short: "experiment query command",
+		Use:         "query",
+		Short:       "experiment query command",
+		Annotations: map[string]string{plugin.LoadAnnotation: "true"},
 		Run: func(cmd *cobra.Command, args []string) {
-			query.PrintExpByOption(utils.GetCtxWithTraceId(context.Background(), utils.TraceId), optionQuery, ifAll, format)
+			ctx := utils.GetCtxWithTraceId(context.Background(), utils.TraceId)
+			plugin.LogLoadErrors(ctx)
+			if auditUid != "" {
+				query.PrintAuditByUid(ctx, auditUid, format)
+			} else if ifWatch {
+				query.WatchExpByOption(ctx, optionQuery, revision, format)
+			} else {
+				query.PrintExpByOption(ctx, optionQuery, ifAll, format)
+			}
 		},
 	}
 
@@ -48,6 +61,9 @@ func NewQueryCommand() *cobra.Command {
 	queryCmd.Flags().UintVarP(&optionQuery.Offset, "offset", "o", 0, "query experiment records with offset, eg: chaosmetad query -o 5")
 	queryCmd.Flags().UintVarP(&optionQuery.Limit, "limit", "l", 10, "query experiment records with limit, eg: chaosmetad query -o 5 -l 5")
 	queryCmd.Flags().BoolVarP(&ifAll, "all", "a", false, "if show all")
+	queryCmd.Flags().BoolVarP(&ifWatch, "watch", "w", false, "watch the status transitions of experiments, only \"uid\", \"target\" and \"fault\" are used to filter. if \"uid\" provided, exit when the experiment is destroyed or error")
+	queryCmd.Flags().Int64Var(&revision, "revision", 0, "used with \"watch\", only show the status transitions after the revision, -1 means from now")
+	queryCmd.Flags().StringVar(&auditUid, "audit", "", "show the commands, file writes, cgroup changes and container execs performed for the experiment, eg: chaosmetad query --audit [uid]")
 	queryCmd.Flags().StringVar(&format, "format", query.TableFormat, fmt.Sprintf("data show format, support: %s(default), %s", query.TableFormat, query.JsonFormat))
 
 	return queryCmd
@@ -21,17 +21,20 @@ import (
 	"fmt"
 	"github.com/spf13/cobra"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/plugin"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
 )
 
 func NewRecoverCommand() *cobra.Command {
 	recoverCmd := &cobra.Command{
-		Use:   "recover",
-		Short: "experiment recover command",
-		Long:  "experiment recover command, usage: recover [uid]",
+		Use:         "recover",
+		Short:       "experiment recover command",
+		Long:        "experiment recover command, usage: recover [uid]",
+		Annotations: map[string]string{plugin.LoadAnnotation: "true"},
 		Run: func(cmd *cobra.Command, args []string) {
 			ctx := utils.GetCtxWithTraceId(context.Background(), utils.TraceId)
+			plugin.LogLoadErrors(ctx)
 			if len(args) != 1 {
 				errutil.SolveErr(ctx, errutil.BadArgsErr, fmt.Sprintf("please add target experiment's uid, eg: recover [uid]"))
 			}
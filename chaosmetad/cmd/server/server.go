@@ -19,11 +19,18 @@ package server
 import (
 	"context"
 	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/auth"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/guardrail"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/plugin"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/rpc"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/watchdog"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -52,16 +59,54 @@ func watchSignal(ctx context.Context) {
 
 // NewServerCommand serverCmd represents the server command
 func NewServerCommand() *cobra.Command {
-	var addr, port string
+	var addr, port, grpcPort, grpcSocket string
 	//var cert, key string
 	var isPprof bool
+	var retention = &storage.RetentionPolicy{}
 	cmd := &cobra.Command{
-		Use:   "server",
-		Short: "start up daemon service",
+		Use:         "server",
+		Short:       "start up daemon service",
+		Annotations: map[string]string{plugin.LoadAnnotation: "true"},
 		Run: func(cmd *cobra.Command, args []string) {
 			ctx := utils.GetCtxWithTraceId(context.Background(), "system")
 			go watchSignal(ctx)
 
+			if err := auth.LoadToken(); err != nil {
+				errutil.SolveErr(ctx, errutil.BadArgsErr, err.Error())
+			}
+			log.GetLogger(ctx).Infof("authentication enabled: %t", auth.IsEnabled())
+
+			if _, err := guardrail.LoadPolicy(); err != nil {
+				errutil.SolveErr(ctx, errutil.BadArgsErr, err.Error())
+			}
+			log.GetLogger(ctx).Infof("guardrail policy loaded from: %s", guardrail.GetPolicyPath())
+
+			plugin.LogLoadErrors(ctx)
+
+			if err := retention.Validate(); err != nil {
+				errutil.SolveErr(ctx, errutil.BadArgsErr, err.Error())
+			}
+			go storage.RunRetention(ctx, retention)
+
+			watchdogConfig, err := watchdog.LoadConfig()
+			if err != nil {
+				errutil.SolveErr(ctx, errutil.BadArgsErr, err.Error())
+			}
+
+			if watchdogConfig != nil {
+				go watchdog.Run(ctx, watchdogConfig)
+			}
+
+			if grpcPort != "" {
+				if !auth.IsEnabled() {
+					log.GetLogger(ctx).Warnf("gRPC service listens on tcp without authentication, please set \"auth-token-file\"")
+				}
+				go startGRPCService(ctx, "tcp", net.JoinHostPort(addr, grpcPort))
+			}
+			if grpcSocket != "" {
+				go startGRPCService(ctx, "unix", grpcSocket)
+			}
+
 			//if cert != "" && key != "" {
 			//	startHTTPSServer(addr, port, isPprof, cert, key)
 			//} else {
@@ -73,6 +118,13 @@ func NewServerCommand() *cobra.Command {
 	cmd.Flags().StringVarP(&addr, "addr", "a", "0.0.0.0", "service bind addr")
 	cmd.Flags().StringVarP(&port, "port", "p", "29595", "service bind port")
 	cmd.Flags().BoolVar(&isPprof, "enable-pprof", true, "if open pprof service")
+	cmd.Flags().StringVar(&grpcPort, "grpc-port", "", "gRPC service bind port, eg: 29596. It is recommended to enable authentication by \"auth-token-file\" at the same time（default not listen on tcp）")
+	cmd.Flags().StringVar(&grpcSocket, "grpc-socket", "", "gRPC service unix socket path（default not listen on unix socket）")
+	cmd.Flags().StringVar(&auth.TokenPath, "auth-token-file", "", "file of the bearer token required by the HTTP and gRPC service（default no authentication）")
+	cmd.Flags().StringVar(&watchdog.Path, "watchdog-file", "", "watchdog config file's path, watchdog is disabled if file not exist（default [install path]/watchdog.json）")
+	cmd.Flags().StringVar(&retention.MaxAge, "retention-age", "", "delete the destroyed or error experiments not updated for this time, support unit: \"s、m、h\"(default s), eg: 168h（default not delete）")
+	cmd.Flags().IntVar(&retention.MaxCount, "retention-count", 0, "only keep the newest count of destroyed or error experiments（default 0, means not delete）")
+	cmd.Flags().StringVar(&retention.Interval, "retention-interval", storage.DefaultRetentionInterval, "interval to check the retention policy, support unit: \"s、m、h\"(default s)")
 	//cmd.Flags().StringVarP(&cert, "cert", "c", "", "path to certificate file")
 	//cmd.Flags().StringVarP(&key, "key", "k", "", "path to private key file")
 	// HTTPS
@@ -92,6 +144,25 @@ func startHTTPService(ctx context.Context, addr string, port string, isPprof boo
 	}
 }
 
+func startGRPCService(ctx context.Context, network, address string) {
+	logger := log.GetLogger(ctx)
+	if network == "unix" {
+		if err := os.Remove(address); err != nil && !os.IsNotExist(err) {
+			logger.Fatalf("remove old unix socket[%s] error: %s", address, err.Error())
+		}
+	}
+
+	lis, err := net.Listen(network, address)
+	if err != nil {
+		logger.Fatalf("listen %s[%s] error: %s", network, address, err.Error())
+	}
+
+	logger.Infof("gRPC Service Listen on %s[%s]", network, address)
+	if err := rpc.NewServer().Serve(lis); err != nil {
+		logger.Fatalf("start grpc service fail: %s", err.Error())
+	}
+}
+
 //func startHTTPSServer(addr string, port string, isPprof bool, cert, key string) {
 //	logger := tools.GetLogger()
 //	logger.Infof("HTTPS Service Listen on %s:%s, pprof: %t, cert: %s, key: %s", addr, port, isPprof, cert, key)
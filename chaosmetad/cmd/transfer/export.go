@@ -0,0 +1,39 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package transfer
+
+import (
+	"context"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/history"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+)
+
+// NewExportCommand exportCmd represents the export command
+func NewExportCommand() *cobra.Command {
+	var file string
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "export experiment history as json",
+		Run: func(cmd *cobra.Command, args []string) {
+			history.ExportExperiments(utils.GetCtxWithTraceId(context.Background(), utils.TraceId), file)
+		},
+	}
+
+	exportCmd.Flags().StringVarP(&file, "file", "f", "", "file to save the history, print to stdout if not provide, eg: chaosmetad export -f /tmp/chaosmetad.json")
+
+	return exportCmd
+}
@@ -0,0 +1,43 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package transfer
+
+import (
+	"context"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/history"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+)
+
+// NewImportCommand importCmd represents the import command
+func NewImportCommand() *cobra.Command {
+	var (
+		file      string
+		overwrite bool
+	)
+	importCmd := &cobra.Command{
+		Use:   "import",
+		Short: "import experiment history from the json file created by export command",
+		Run: func(cmd *cobra.Command, args []string) {
+			history.ImportExperiments(utils.GetCtxWithTraceId(context.Background(), utils.TraceId), file, overwrite)
+		},
+	}
+
+	importCmd.Flags().StringVarP(&file, "file", "f", "", "file created by export command, eg: chaosmetad import -f /tmp/chaosmetad.json")
+	importCmd.Flags().BoolVar(&overwrite, "overwrite", false, "overwrite the experiment if uid already exists（default skip）")
+
+	return importCmd
+}
@@ -0,0 +1,110 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package audit
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"time"
+)
+
+// kind of the audited operation
+const (
+	KindCmd           = "cmd"
+	KindContainerExec = "container-exec"
+	KindFile          = "file"
+	KindCgroup        = "cgroup"
+)
+
+const (
+	CtxExpUid = "ExpUid"
+	CtxPhase  = "Phase"
+	CtxKind   = "AuditKind"
+
+	// NoExitCode is used for the operations which are started in background or not a process
+	NoExitCode = -1
+	// MaxOutputLen is the max length of output saved in audit, the rest will be truncated
+	MaxOutputLen = 4096
+)
+
+// WithExperiment binds the experiment to ctx, operations performed with the ctx will be recorded to its audit trail
+func WithExperiment(ctx context.Context, uid, phase string) context.Context {
+	return context.WithValue(context.WithValue(ctx, CtxExpUid, uid), CtxPhase, phase)
+}
+
+// WithKind overwrites the kind of the operations performed with the ctx, eg: file write by shell command
+func WithKind(ctx context.Context, kind string) context.Context {
+	return context.WithValue(ctx, CtxKind, kind)
+}
+
+func getCtxStr(ctx context.Context, key string) string {
+	if ctx.Value(key) == nil {
+		return ""
+	}
+
+	return ctx.Value(key).(string)
+}
+
+// Record save the operation to the audit trail of the experiment bound to ctx, nothing will be recorded if no experiment bound.
+// The default kind is used if the kind is not overwritten by WithKind
+func Record(ctx context.Context, kind, cId, cmd string, exitCode int, output string, err error, startTime time.Time) {
+	uid := getCtxStr(ctx, CtxExpUid)
+	if uid == "" {
+		return
+	}
+
+	if ctxKind := getCtxStr(ctx, CtxKind); ctxKind != "" {
+		kind = ctxKind
+	}
+
+	var errMsg string
+	if err != nil {
+		errMsg = truncate(err.Error())
+	}
+
+	logger := log.GetLogger(ctx)
+	db, dbErr := storage.GetExperimentStore()
+	if dbErr != nil {
+		logger.Warnf("get db to record audit of experiment[%s] error: %s", uid, dbErr.Error())
+		return
+	}
+
+	if dbErr := db.InsertAudit(&storage.ExperimentAudit{
+		Uid:         uid,
+		Phase:       getCtxStr(ctx, CtxPhase),
+		Kind:        kind,
+		ContainerId: cId,
+		Command:     cmd,
+		ExitCode:    exitCode,
+		Output:      truncate(output),
+		Error:       errMsg,
+		StartTime:   startTime.Format(utils.TimeFormat),
+		EndTime:     time.Now().Format(utils.TimeFormat),
+	}); dbErr != nil {
+		logger.Warnf("record audit of experiment[%s] error: %s", uid, dbErr.Error())
+	}
+}
+
+func truncate(s string) string {
+	if len(s) <= MaxOutputLen {
+		return s
+	}
+
+	return fmt.Sprintf("%s...[truncated %d bytes]", s[:MaxOutputLen], len(s)-MaxOutputLen)
+}
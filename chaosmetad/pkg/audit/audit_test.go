@@ -0,0 +1,68 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package audit
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecord(t *testing.T) {
+	storage.DBPath = filepath.Join(t.TempDir(), "chaosmetad.dat")
+	defer func() {
+		storage.DBPath = ""
+	}()
+
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		t.Fatalf("GetExperimentStore() error = %v", err)
+	}
+
+	uid := "audit-test"
+	if err := db.Insert(&storage.Experiment{Uid: uid, Target: "file", Fault: "add", Status: utils.StatusCreated}); err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+
+	ctx := context.Background()
+	Record(ctx, KindCmd, "", "echo unbound", 0, "", nil, time.Now())
+	ctx = WithExperiment(ctx, uid, utils.MethodInject)
+	Record(ctx, KindCmd, "", "echo 1", 0, "1", nil, time.Now())
+	Record(WithKind(ctx, KindFile), KindContainerExec, "c1", "touch /tmp/a", 1, strings.Repeat("a", MaxOutputLen+10), fmt.Errorf("exit 1"), time.Now())
+
+	audits, err := db.ListAudits(uid)
+	if err != nil {
+		t.Fatalf("ListAudits() error = %v", err)
+	}
+
+	if len(audits) != 2 {
+		t.Fatalf("ListAudits() got %d audits, want 2", len(audits))
+	}
+
+	if audits[0].Command != "echo 1" || audits[0].Kind != KindCmd || audits[0].Phase != utils.MethodInject || audits[0].Output != "1" {
+		t.Errorf("ListAudits()[0] = %+v", audits[0])
+	}
+
+	if audits[1].Kind != KindFile || audits[1].ContainerId != "c1" || audits[1].ExitCode != 1 || audits[1].Error != "exit 1" ||
+		!strings.HasSuffix(audits[1].Output, "...[truncated 10 bytes]") {
+		t.Errorf("ListAudits()[1] = %+v", audits[1])
+	}
+}
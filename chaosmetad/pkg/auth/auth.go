@@ -0,0 +1,77 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"os"
+	"strings"
+)
+
+const (
+	// HeaderKey is the HTTP header, and also the gRPC metadata key, carrying the token. eg: "Authorization: Bearer xxx"
+	HeaderKey    = "authorization"
+	BearerPrefix = "Bearer "
+)
+
+// TokenPath is the file of the shared bearer token, empty means authentication is disabled
+var TokenPath string
+
+var token string
+
+// LoadToken read the token from TokenPath, it is shared by the HTTP and gRPC service
+func LoadToken() error {
+	token = ""
+	if TokenPath == "" {
+		return nil
+	}
+
+	fileBytes, err := os.ReadFile(TokenPath)
+	if err != nil {
+		return fmt.Errorf("read token file[%s] error: %s", TokenPath, err.Error())
+	}
+
+	t := strings.TrimSpace(string(fileBytes))
+	if t == "" {
+		return fmt.Errorf("token file[%s] is empty", TokenPath)
+	}
+
+	token = t
+	return nil
+}
+
+func IsEnabled() bool {
+	return token != ""
+}
+
+// Check verify the value of the authorization header, always pass if authentication is disabled
+func Check(authorization string) error {
+	if !IsEnabled() {
+		return nil
+	}
+
+	if !strings.HasPrefix(authorization, BearerPrefix) {
+		return fmt.Errorf("missing bearer token")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(authorization, BearerPrefix)), []byte(token)) != 1 {
+		return fmt.Errorf("invalid token")
+	}
+
+	return nil
+}
@@ -0,0 +1,86 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package auth
+
+import (
+	"os"
+	"path"
+	"testing"
+)
+
+func TestCheck(t *testing.T) {
+	tokenPath := path.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenPath, []byte("secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name          string
+		path          string
+		authorization string
+		wantErr       bool
+	}{
+		{"disabled", "", "", false},
+		{"disabled with token", "", "Bearer xxx", false},
+		{"valid", tokenPath, "Bearer secret", false},
+		{"missing", tokenPath, "", true},
+		{"no bearer prefix", tokenPath, "secret", true},
+		{"invalid", tokenPath, "Bearer secre", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			TokenPath = tt.path
+			if err := LoadToken(); err != nil {
+				t.Fatalf("LoadToken() error = %v", err)
+			}
+			if err := Check(tt.authorization); (err != nil) != tt.wantErr {
+				t.Errorf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+	TokenPath = ""
+	_ = LoadToken()
+}
+
+func TestLoadToken(t *testing.T) {
+	dir := t.TempDir()
+	emptyPath := path.Join(dir, "empty")
+	if err := os.WriteFile(emptyPath, []byte(" \n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{"not exist", path.Join(dir, "none"), true},
+		{"empty", emptyPath, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			TokenPath = tt.path
+			if err := LoadToken(); (err != nil) != tt.wantErr {
+				t.Errorf("LoadToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if IsEnabled() {
+				t.Errorf("IsEnabled() should be false after load error")
+			}
+		})
+	}
+	TokenPath = ""
+}
@@ -0,0 +1,351 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package guardrail
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"net"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	policyFile = "guardrail.json"
+	portBit    = 16
+)
+
+var (
+	// Path is the guardrail policy file, empty means "[run path]/guardrail.json". No policy if file not exist
+	Path   string
+	policy *Policy
+	mutex  sync.Mutex
+)
+
+// Policy lists the resources of node which can not be attacked
+type Policy struct {
+	// Processes process command name, eg: sshd
+	Processes []string `json:"processes,omitempty"`
+	// Pids pid in host's pid namespace
+	Pids []int `json:"pids,omitempty"`
+	// Interfaces network interface, eg: eth0
+	Interfaces []string `json:"interfaces,omitempty"`
+	// CIDRs ip or subnet, eg: 10.0.0.0/8
+	CIDRs []string `json:"cidrs,omitempty"`
+	Ports []int    `json:"ports,omitempty"`
+	// Paths protect the path itself and all files under it
+	Paths []string `json:"paths,omitempty"`
+	// Containers container id or its prefix
+	Containers []string `json:"containers,omitempty"`
+}
+
+// Resources are the node resources affected by an experiment
+type Resources struct {
+	Pids         []int
+	ProcessNames []string
+	Interfaces   []string
+	// IPs ip or subnet list, eg: 192.168.2.5,10.10.0.0/16
+	IPs string
+	// Exclude means the traffic matches IPs and Ports is excluded, all the other traffic is affected
+	Exclude bool
+	// Ports port list of network filter, eg: 8080,12000/8
+	Ports string
+	// Network means the traffic of the interfaces is filtered by IPs, Ports and Exclude, an empty filter matches all.
+	// Otherwise only the listed Ports are affected
+	Network bool
+	// Addresses the addresses of interfaces, which are affected when IPs is empty
+	Addresses  string
+	Paths      []string
+	Containers []string
+}
+
+// ViolationError is returned when the resources of experiment are protected by guardrail
+type ViolationError struct {
+	Violations []string
+}
+
+func (e *ViolationError) Error() string {
+	return fmt.Sprintf("protected by guardrail: %s, if really want to execute, please provide [--guardrail-override] args", strings.Join(e.Violations, "; "))
+}
+
+func GetPolicyPath() string {
+	if Path != "" {
+		return Path
+	}
+
+	return path.Join(utils.GetRunPath(), policyFile)
+}
+
+// GetPathArgs return the args need to be passed to the child chaosmetad process to use the same policy
+func GetPathArgs() string {
+	if Path == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("--guardrail-file %s", utils.ShellQuote(Path))
+}
+
+// LoadPolicy read the policy from file, it is loaded only once in the process
+func LoadPolicy() (*Policy, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if policy != nil {
+		return policy, nil
+	}
+
+	p := &Policy{}
+	fileBytes, err := os.ReadFile(GetPolicyPath())
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("read guardrail file[%s] error: %s", GetPolicyPath(), err.Error())
+		}
+	} else {
+		if err := json.Unmarshal(fileBytes, p); err != nil {
+			return nil, fmt.Errorf("guardrail file[%s] format error: %s", GetPolicyPath(), err.Error())
+		}
+
+		if err := p.Validate(); err != nil {
+			return nil, fmt.Errorf("guardrail file[%s] is invalid: %s", GetPolicyPath(), err.Error())
+		}
+	}
+
+	policy = p
+	return policy, nil
+}
+
+func (p *Policy) Validate() error {
+	for _, cidr := range p.CIDRs {
+		if _, err := parseNet(cidr); err != nil {
+			return fmt.Errorf("cidr[%s] is invalid: %s", cidr, err.Error())
+		}
+	}
+
+	for _, port := range p.Ports {
+		if port <= 0 || port > 65535 {
+			return fmt.Errorf("port[%d] is invalid, should in (0, 65535]", port)
+		}
+	}
+
+	for _, unitPath := range p.Paths {
+		if !filepath.IsAbs(unitPath) {
+			return fmt.Errorf("path[%s] must be an absolute path", unitPath)
+		}
+	}
+
+	return nil
+}
+
+// Check return the violations of resources
+func (p *Policy) Check(r *Resources) []string {
+	var violations []string
+	if r == nil {
+		return nil
+	}
+
+	for _, pid := range r.Pids {
+		for _, protectedPid := range p.Pids {
+			if pid == protectedPid {
+				violations = append(violations, fmt.Sprintf("pid[%d] is protected", pid))
+			}
+		}
+	}
+
+	for _, name := range r.ProcessNames {
+		if utils.StrListContain(p.Processes, name) {
+			violations = append(violations, fmt.Sprintf("process[%s] is protected", name))
+		}
+	}
+
+	for _, netInterface := range r.Interfaces {
+		if utils.StrListContain(p.Interfaces, netInterface) {
+			violations = append(violations, fmt.Sprintf("interface[%s] is protected", netInterface))
+		}
+	}
+
+	violations = append(violations, p.checkIPs(r)...)
+	violations = append(violations, p.checkPorts(r)...)
+
+	for _, unitPath := range r.Paths {
+		for _, protectedPath := range p.Paths {
+			if isSubPath(protectedPath, unitPath) {
+				violations = append(violations, fmt.Sprintf("path[%s] is protected by [%s]", unitPath, protectedPath))
+			} else if isSubPath(unitPath, protectedPath) {
+				violations = append(violations, fmt.Sprintf("path[%s] contains protected path[%s]", unitPath, protectedPath))
+			}
+		}
+	}
+
+	for _, container := range r.Containers {
+		for _, protectedContainer := range p.Containers {
+			if protectedContainer != "" && (strings.HasPrefix(container, protectedContainer) || strings.HasPrefix(protectedContainer, container)) {
+				violations = append(violations, fmt.Sprintf("container[%s] is protected", container))
+			}
+		}
+	}
+
+	return violations
+}
+
+func (p *Policy) checkIPs(r *Resources) []string {
+	var violations []string
+	ipStr, exclude := r.IPs, r.Exclude
+	if ipStr == "" {
+		// no ip filter, the traffic of all addresses is affected
+		ipStr, exclude = r.Addresses, false
+	}
+
+	if ipStr == "" || len(p.CIDRs) == 0 {
+		return nil
+	}
+
+	var nets []*net.IPNet
+	for _, unit := range strings.Split(ipStr, ",") {
+		ipNet, err := parseNet(strings.TrimSpace(unit))
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("ip[%s] is invalid: %s", unit, err.Error()))
+			continue
+		}
+		nets = append(nets, ipNet)
+	}
+
+	for _, cidr := range p.CIDRs {
+		protectedNet, _ := parseNet(cidr)
+		if exclude {
+			var covered bool
+			for _, ipNet := range nets {
+				if containsNet(ipNet, protectedNet) {
+					covered = true
+					break
+				}
+			}
+
+			// only the traffic matches both ip and port is excluded, the other ports of the ip are still affected
+			if !covered || r.Ports != "" {
+				violations = append(violations, fmt.Sprintf("cidr[%s] is protected but not excluded", cidr))
+			}
+			continue
+		}
+
+		for _, ipNet := range nets {
+			if containsNet(ipNet, protectedNet) || containsNet(protectedNet, ipNet) {
+				violations = append(violations, fmt.Sprintf("ip[%s] is protected by cidr[%s]", ipNet.String(), cidr))
+			}
+		}
+	}
+
+	return violations
+}
+
+func (p *Policy) checkPorts(r *Resources) []string {
+	var violations []string
+	if len(p.Ports) == 0 || (r.Ports == "" && !r.Network) {
+		return nil
+	}
+
+	var ranges [][2]int
+	if r.Ports != "" {
+		for _, portRange := range strings.Split(r.Ports, ",") {
+			start, end, err := parsePortRange(portRange)
+			if err != nil {
+				violations = append(violations, fmt.Sprintf("port[%s] is invalid: %s", portRange, err.Error()))
+				continue
+			}
+			ranges = append(ranges, [2]int{start, end})
+		}
+	}
+
+	for _, port := range p.Ports {
+		var covered bool
+		for _, unitRange := range ranges {
+			if unitRange[0] <= port && port <= unitRange[1] {
+				covered = true
+				break
+			}
+		}
+
+		if r.Exclude {
+			// the affected ports are the complement of the listed ports, and only the traffic matches ip filter too is excluded
+			if !covered || r.IPs != "" {
+				violations = append(violations, fmt.Sprintf("port[%d] is protected but not excluded", port))
+			}
+		} else if covered || r.Ports == "" {
+			// no port filter means all ports are affected
+			violations = append(violations, fmt.Sprintf("port[%d] is protected", port))
+		}
+	}
+
+	return violations
+}
+
+func parseNet(ipStr string) (*net.IPNet, error) {
+	if !strings.Contains(ipStr, "/") {
+		ip := net.ParseIP(ipStr)
+		if ip == nil {
+			return nil, fmt.Errorf("not a valid ip")
+		}
+
+		if ip.To4() != nil {
+			return &net.IPNet{IP: ip.To4(), Mask: net.CIDRMask(32, 32)}, nil
+		}
+
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+	}
+
+	_, ipNet, err := net.ParseCIDR(ipStr)
+	return ipNet, err
+}
+
+// containsNet whether sub is a subnet of parent
+func containsNet(parent, sub *net.IPNet) bool {
+	parentOnes, parentBits := parent.Mask.Size()
+	subOnes, subBits := sub.Mask.Size()
+	return parentBits == subBits && parentOnes <= subOnes && parent.Contains(sub.IP)
+}
+
+// parsePortRange the format is the same as the port filter of network fault, eg: 8080, 12000/8
+func parsePortRange(portStr string) (int, int, error) {
+	portArr := strings.Split(strings.TrimSpace(portStr), "/")
+	port, err := strconv.Atoi(portArr[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("%s is not a valid port", portArr[0])
+	}
+
+	mask := portBit
+	if len(portArr) > 1 {
+		if mask, err = strconv.Atoi(portArr[1]); err != nil || mask < 0 || mask > portBit {
+			return 0, 0, fmt.Errorf("port mask[%s] should in [0,%d]", portArr[1], portBit)
+		}
+	}
+
+	size := 1 << (portBit - mask)
+	start := port &^ (size - 1)
+	return start, start + size - 1, nil
+}
+
+// isSubPath whether target is parent itself or under parent
+func isSubPath(parent, target string) bool {
+	parent, target = filepath.Clean(parent), filepath.Clean(target)
+	if parent == target || parent == "/" {
+		return true
+	}
+
+	return strings.HasPrefix(target, parent+"/")
+}
@@ -0,0 +1,97 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package guardrail
+
+import (
+	"testing"
+)
+
+func TestPolicy_Check(t *testing.T) {
+	policy := &Policy{
+		Processes:  []string{"sshd", "kubelet"},
+		Pids:       []int{1},
+		Interfaces: []string{"eth1"},
+		CIDRs:      []string{"10.0.0.0/8", "192.168.1.1"},
+		Ports:      []int{22, 10250},
+		Paths:      []string{"/etc/passwd", "/etc/ssh"},
+		Containers: []string{"abc123"},
+	}
+	if err := policy.Validate(); err != nil {
+		t.Fatalf("Validate() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		resources *Resources
+		wantCount int
+	}{
+		{name: "nil", resources: nil, wantCount: 0},
+		{name: "pid", resources: &Resources{Pids: []int{1, 100}}, wantCount: 1},
+		{name: "process name", resources: &Resources{ProcessNames: []string{"sshd", "nginx"}}, wantCount: 1},
+		{name: "interface", resources: &Resources{Interfaces: []string{"eth1"}}, wantCount: 1},
+		{name: "interface not protected", resources: &Resources{Interfaces: []string{"eth0"}}, wantCount: 0},
+		{name: "ip in cidr", resources: &Resources{IPs: "10.1.2.3"}, wantCount: 1},
+		{name: "subnet contains protected ip", resources: &Resources{IPs: "192.168.0.0/16"}, wantCount: 1},
+		{name: "ip not in cidr", resources: &Resources{IPs: "172.16.0.1,192.168.1.2"}, wantCount: 0},
+		{name: "exclude covers cidr", resources: &Resources{IPs: "10.0.0.0/8,192.168.1.0/24", Exclude: true}, wantCount: 0},
+		{name: "exclude not covers cidr", resources: &Resources{IPs: "10.0.0.0/16", Exclude: true}, wantCount: 2},
+		{name: "port", resources: &Resources{Ports: "22,8080"}, wantCount: 1},
+		{name: "port mask", resources: &Resources{Ports: "10240/8"}, wantCount: 1},
+		{name: "port excluded", resources: &Resources{Ports: "22,10250", Exclude: true}, wantCount: 0},
+		{name: "port partly excluded", resources: &Resources{Ports: "22", Exclude: true}, wantCount: 1},
+		{name: "network without port filter", resources: &Resources{Network: true, IPs: "172.16.0.1"}, wantCount: 2},
+		{name: "network port filter", resources: &Resources{Network: true, IPs: "172.16.0.1", Ports: "8080"}, wantCount: 0},
+		{name: "network exclude all protected ports", resources: &Resources{Network: true, Ports: "22,10250", Exclude: true}, wantCount: 0},
+		{name: "network exclude other ports", resources: &Resources{Network: true, Ports: "8080", Exclude: true}, wantCount: 2},
+		{name: "network exclude without port filter", resources: &Resources{Network: true, IPs: "10.0.0.0/8,192.168.1.1", Exclude: true}, wantCount: 2},
+		{name: "network exclude ip and port", resources: &Resources{Network: true, IPs: "10.0.0.0/8,192.168.1.1", Ports: "22,10250", Exclude: true}, wantCount: 4},
+		{name: "network addresses of interface", resources: &Resources{Network: true, Addresses: "10.1.1.1,172.16.0.1", Ports: "8080"}, wantCount: 1},
+		{name: "network addresses with exclude ports", resources: &Resources{Network: true, Addresses: "172.16.0.1", Ports: "22,10250", Exclude: true}, wantCount: 0},
+		{name: "file", resources: &Resources{Paths: []string{"/etc/passwd"}}, wantCount: 1},
+		{name: "file under dir", resources: &Resources{Paths: []string{"/etc/ssh/sshd_config"}}, wantCount: 1},
+		{name: "dir contains file", resources: &Resources{Paths: []string{"/etc"}}, wantCount: 2},
+		{name: "similar prefix", resources: &Resources{Paths: []string{"/etc/passwd-", "/etc/sshd"}}, wantCount: 0},
+		{name: "container prefix", resources: &Resources{Containers: []string{"abc123def456"}}, wantCount: 1},
+		{name: "container not protected", resources: &Resources{Containers: []string{"def456"}}, wantCount: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := policy.Check(tt.resources); len(got) != tt.wantCount {
+				t.Errorf("Check() = %v, want %d violations", got, tt.wantCount)
+			}
+		})
+	}
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  *Policy
+		wantErr bool
+	}{
+		{name: "empty", policy: &Policy{}, wantErr: false},
+		{name: "invalid cidr", policy: &Policy{CIDRs: []string{"10.0.0.0/33"}}, wantErr: true},
+		{name: "invalid port", policy: &Policy{Ports: []int{0}}, wantErr: true},
+		{name: "relative path", policy: &Policy{Paths: []string{"etc/passwd"}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package guardrail
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/net"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+	"strings"
+)
+
+// ProcessResources pids are in container's pid namespace if cr is not empty, and only host's pids are checked by "pids" policy
+func ProcessResources(ctx context.Context, cr, cId string, pids []int) (*Resources, error) {
+	r := &Resources{}
+	for _, pid := range pids {
+		name, err := process.GetProcessName(ctx, cr, cId, pid)
+		if err != nil {
+			return nil, err
+		}
+
+		if cr == "" {
+			r.Pids = append(r.Pids, pid)
+		}
+		r.ProcessNames = append(r.ProcessNames, name)
+	}
+
+	return r, nil
+}
+
+// NetworkResources if no ip filter provided, the addresses of interface are affected. Empty port filter means all ports
+func NetworkResources(ctx context.Context, cr, cId, netInterface, mode, srcIp, dstIp, srcPort, dstPort string) (*Resources, error) {
+	r := &Resources{
+		Interfaces: []string{netInterface},
+		Exclude:    mode == net.ModeExclude,
+		Network:    true,
+	}
+
+	var ipList, portList []string
+	for _, unit := range []string{srcIp, dstIp} {
+		if unit != "" {
+			ipList = append(ipList, unit)
+		}
+	}
+
+	for _, unit := range []string{srcPort, dstPort} {
+		if unit != "" {
+			portList = append(portList, unit)
+		}
+	}
+
+	r.IPs, r.Ports = strings.Join(ipList, ","), strings.Join(portList, ",")
+	if r.IPs == "" {
+		addrList, err := net.GetInterfaceIPs(ctx, cr, cId, netInterface)
+		if err != nil {
+			return nil, fmt.Errorf("get addresses of interface error: %s", err.Error())
+		}
+
+		r.Addresses = strings.Join(addrList, ",")
+	}
+
+	return r, nil
+}
@@ -0,0 +1,112 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"os"
+	"time"
+)
+
+// ExportExperiments write all experiments with their events and audits to file as json, print to stdout if file is empty
+func ExportExperiments(ctx context.Context, file string) {
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		errutil.SolveErr(ctx, errutil.DBErr, fmt.Sprintf("connect db error: %s", err.Error()))
+	}
+
+	exps, err := db.ListAll()
+	if err != nil {
+		errutil.SolveErr(ctx, errutil.DBErr, fmt.Sprintf("list experiments error: %s", err.Error()))
+	}
+
+	events, err := db.ListEvents(0, "", "", "", 0)
+	if err != nil {
+		errutil.SolveErr(ctx, errutil.DBErr, fmt.Sprintf("list events error: %s", err.Error()))
+	}
+
+	audits, err := db.ListAllAudits()
+	if err != nil {
+		errutil.SolveErr(ctx, errutil.DBErr, fmt.Sprintf("list audits error: %s", err.Error()))
+	}
+
+	reBytes, err := json.MarshalIndent(&storage.ExperimentHistory{
+		SchemaVersion: storage.SchemaVersion(),
+		ExportTime:    time.Now().Format(utils.TimeFormat),
+		Experiments:   exps,
+		Events:        events,
+		Audits:        audits,
+	}, "", "  ")
+	if err != nil {
+		errutil.SolveErr(ctx, errutil.InternalErr, fmt.Sprintf("experiments change to json error: %s", err.Error()))
+	}
+
+	if file == "" {
+		fmt.Println(string(reBytes))
+		return
+	}
+
+	if err := os.WriteFile(file, reBytes, 0644); err != nil {
+		errutil.SolveErr(ctx, errutil.InternalErr, fmt.Sprintf("write file[%s] error: %s", file, err.Error()))
+	}
+
+	log.GetLogger(ctx).Infof("export %d experiments to %s", len(exps), file)
+}
+
+// ImportExperiments load experiments with their events and audits from the json file exported by ExportExperiments
+func ImportExperiments(ctx context.Context, file string, overwrite bool) {
+	if file == "" {
+		errutil.SolveErr(ctx, errutil.BadArgsErr, "\"file\" must provide")
+	}
+
+	fileBytes, err := os.ReadFile(file)
+	if err != nil {
+		errutil.SolveErr(ctx, errutil.BadArgsErr, fmt.Sprintf("read file[%s] error: %s", file, err.Error()))
+	}
+
+	var data storage.ExperimentHistory
+	if err := json.Unmarshal(fileBytes, &data); err != nil {
+		errutil.SolveErr(ctx, errutil.BadArgsErr, fmt.Sprintf("file[%s] format error: %s", file, err.Error()))
+	}
+
+	if data.SchemaVersion > storage.SchemaVersion() {
+		errutil.SolveErr(ctx, errutil.BadArgsErr, fmt.Sprintf("schema version of file[%d] is newer than the binary[%d]", data.SchemaVersion, storage.SchemaVersion()))
+	}
+
+	for _, exp := range data.Experiments {
+		if exp == nil || exp.Uid == "" {
+			errutil.SolveErr(ctx, errutil.BadArgsErr, "experiment without uid exists in file")
+		}
+	}
+
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		errutil.SolveErr(ctx, errutil.DBErr, fmt.Sprintf("connect db error: %s", err.Error()))
+	}
+
+	imported, skipped, err := db.Import(&data, overwrite)
+	if err != nil {
+		errutil.SolveErr(ctx, errutil.DBErr, fmt.Sprintf("import experiments error: %s", err.Error()))
+	}
+
+	log.GetLogger(ctx).Infof("import %d experiments, skip %d existed experiments", imported, skipped)
+}
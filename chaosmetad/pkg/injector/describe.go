@@ -0,0 +1,113 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package injector
+
+import (
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type FaultDescriptor struct {
+	Target      string
+	Fault       string
+	Description string
+	Args        []ArgDescriptor
+}
+
+type ArgDescriptor struct {
+	// Name is the key in the json args of the experiment
+	Name        string
+	Type        string
+	Default     string
+	Description string
+}
+
+// Describe list the registered faults with the args declared by their command options, empty target or fault means all
+func Describe(target, fault string) []FaultDescriptor {
+	re := make([]FaultDescriptor, 0)
+	for k, f := range constructorScheme {
+		kArr := strings.SplitN(k, utils.BuilderSplit, 2)
+		if len(kArr) != 2 {
+			continue
+		}
+
+		if (target != "" && kArr[0] != target) || (fault != "" && kArr[1] != fault) {
+			continue
+		}
+
+		i := f()
+		cmd := &cobra.Command{Short: fmt.Sprintf("create %s experiment for %s", kArr[1], kArr[0])}
+		i.SetOption(cmd)
+		d := FaultDescriptor{
+			Target:      kArr[0],
+			Fault:       kArr[1],
+			Description: cmd.Short,
+			Args:        make([]ArgDescriptor, 0),
+		}
+
+		names := getArgsJsonNames(i.GetArgs())
+		cmd.Flags().VisitAll(func(flag *pflag.Flag) {
+			name, ok := names[reflect.ValueOf(flag.Value).Pointer()]
+			if !ok {
+				name = strings.ReplaceAll(flag.Name, "-", "_")
+			}
+
+			d.Args = append(d.Args, ArgDescriptor{
+				Name:        name,
+				Type:        flag.Value.Type(),
+				Default:     flag.DefValue,
+				Description: flag.Usage,
+			})
+		})
+
+		re = append(re, d)
+	}
+
+	sort.Slice(re, func(a, b int) bool {
+		if re[a].Target != re[b].Target {
+			return re[a].Target < re[b].Target
+		}
+		return re[a].Fault < re[b].Fault
+	})
+
+	return re
+}
+
+// getArgsJsonNames map the address of each field of args to its json name, so that a flag can be matched to its json key
+func getArgsJsonNames(args interface{}) map[uintptr]string {
+	re := make(map[uintptr]string)
+	v := reflect.ValueOf(args)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return re
+	}
+
+	v = v.Elem()
+	for i := 0; i < v.NumField(); i++ {
+		name := strings.Split(v.Type().Field(i).Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" || !v.Field(i).CanAddr() {
+			continue
+		}
+		re[v.Field(i).Addr().Pointer()] = name
+	}
+
+	return re
+}
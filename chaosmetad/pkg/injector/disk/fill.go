@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/guardrail"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
@@ -91,6 +92,10 @@ func (i *FillInjector) Validator(ctx context.Context) error {
 		return fmt.Errorf("\"dir\" must provide absolute path")
 	}
 
+	if err := i.CheckGuardrail(ctx, &guardrail.Resources{Paths: []string{i.Args.Dir}}); err != nil {
+		return err
+	}
+
 	return i.getCmdExecutor(utils.MethodValidator, fmt.Sprintf("%d '%s' %s", i.Args.Percent, i.Args.Bytes, i.Args.Dir)).ExecTool(ctx)
 }
 
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/guardrail"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
@@ -104,6 +105,10 @@ func (i *BurnInjector) Validator(ctx context.Context) error {
 		return fmt.Errorf("\"dir\" is empty")
 	}
 
+	if err := i.CheckGuardrail(ctx, &guardrail.Resources{Paths: []string{i.Args.Dir}}); err != nil {
+		return err
+	}
+
 	if i.Args.Mode != ModeRead && i.Args.Mode != ModeWrite {
 		return fmt.Errorf("\"mode\" not support %s, only support: %s、%s", i.Args.Mode, ModeRead, ModeWrite)
 	}
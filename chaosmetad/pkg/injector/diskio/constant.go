@@ -37,5 +37,17 @@ const (
 
 	TmpCgroup = "/user.slice"
 
+	FaultDiskIODelay = "delay"
+	// DelaySegments the device is split into segments with different latency to spread the jitter
+	DelaySegments = 64
+	DelayLevels   = 8
+	// MinSegmentSectors 1MB
+	MinSegmentSectors = 2048
+
+	FaultDiskIOError     = "error"
+	DefaultErrorInterval = "10s"
+	// ErrorReadsKernel the kernel version which supports the "error_reads" feature of dm-flakey
+	ErrorReadsKernel = "6.5"
+
 	DiskIOExec = "chaosmeta_diskio"
 )
@@ -0,0 +1,178 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package diskio
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/disk"
+	"strconv"
+)
+
+func init() {
+	injector.Register(TargetDiskIO, FaultDiskIODelay, func() injector.IInjector { return &DelayInjector{} })
+}
+
+// DelayInjector reloads the dm-linear table of the device with dm-delay, which adds a fixed latency to every IO. dm-delay
+// has no per-IO jitter, and the table of a plain block device(eg: /dev/sda1) can not be reloaded, so neither is supported
+type DelayInjector struct {
+	injector.BaseInjector
+	Args    DelayArgs
+	Runtime DMRuntime
+}
+
+type DelayArgs struct {
+	Dir     string `json:"dir"`
+	Mode    string `json:"mode,omitempty"`
+	Latency string `json:"latency"`
+	// SegmentJitter the latency is fixed for each segment of the device, it varies by the area of IO, not by each IO
+	SegmentJitter string `json:"segment_jitter,omitempty"`
+}
+
+func (i *DelayInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *DelayInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *DelayInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Mode == "" {
+		i.Args.Mode = ModeAll
+	}
+}
+
+func (i *DelayInjector) SetOption(cmd *cobra.Command) {
+	// i.BaseInjector.SetOption(cmd)
+
+	cmd.Flags().StringVarP(&i.Args.Dir, "dir", "d", "", "target directory, it must be on a device-mapper device with dm-linear table(eg: LVM logical volume), plain block devices are not supported, the whole device will be delayed")
+	cmd.Flags().StringVarP(&i.Args.Mode, "mode", "m", "", fmt.Sprintf("delayed disk IO mode, support: %s、%s、%s（default %s）", ModeRead, ModeWrite, ModeAll, ModeAll))
+	cmd.Flags().StringVarP(&i.Args.Latency, "latency", "l", "", "fixed delay added to each IO, support unit: s、ms（default ms）")
+	cmd.Flags().StringVar(&i.Args.SegmentJitter, "segment-jitter", "", fmt.Sprintf("the device is split into %d segments, the latency of each segment is fixed in latency±segment-jitter, so the latency varies by the area of IO rather than by each IO(per-IO jitter is not supported), support unit: s、ms（default ms）", DelaySegments))
+}
+
+func (i *DelayInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.Mode != ModeRead && i.Args.Mode != ModeWrite && i.Args.Mode != ModeAll {
+		return fmt.Errorf("\"mode\" not support %s, only support: %s、%s、%s", i.Args.Mode, ModeRead, ModeWrite, ModeAll)
+	}
+
+	latency, err := getMilliseconds(i.Args.Latency)
+	if err != nil {
+		return fmt.Errorf("\"latency\"[%s] is invalid: %s", i.Args.Latency, err.Error())
+	}
+
+	if latency <= 0 {
+		return fmt.Errorf("\"latency\"[%s] must larger than 0", i.Args.Latency)
+	}
+
+	if i.Args.SegmentJitter != "" {
+		if _, err := getMilliseconds(i.Args.SegmentJitter); err != nil {
+			return fmt.Errorf("\"segment-jitter\"[%s] is invalid: %s", i.Args.SegmentJitter, err.Error())
+		}
+	}
+
+	return validateDMDir(ctx, &i.BaseInjector, i.Args.Dir, false)
+}
+
+func (i *DelayInjector) Inject(ctx context.Context) error {
+	latency, _ := getMilliseconds(i.Args.Latency)
+	var jitter int64
+	if i.Args.SegmentJitter != "" {
+		jitter, _ = getMilliseconds(i.Args.SegmentJitter)
+	}
+
+	return injectDMTable(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Dir, &i.Runtime, func(targets []disk.DMTarget) []disk.DMTarget {
+		return buildDelayTable(targets, i.Args.Mode, latency, jitter)
+	})
+}
+
+func (i *DelayInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return recoverDMTable(ctx, &i.Runtime)
+}
+
+// buildDelayTable convert the linear targets to delay targets. With jitter, the device is split into segments, and the
+// latency of each segment is picked from evenly spaced levels in [latency-jitter, latency+jitter]
+func buildDelayTable(targets []disk.DMTarget, mode string, latency, jitter int64) []disk.DMTarget {
+	var total uint64
+	for _, t := range targets {
+		total += t.Length
+	}
+
+	segment := total / DelaySegments
+	if segment < MinSegmentSectors {
+		segment = MinSegmentSectors
+	}
+
+	var (
+		re  []disk.DMTarget
+		idx int64
+	)
+	for _, t := range targets {
+		dev := t.Args[0]
+		offset, _ := strconv.ParseUint(t.Args[1], 10, 64)
+		for done := uint64(0); done < t.Length; {
+			length := t.Length - done
+			if jitter > 0 && length > segment {
+				length = segment
+			}
+
+			delay := latency
+			if jitter > 0 {
+				delay = latency - jitter + 2*jitter*((idx*5)%DelayLevels)/(DelayLevels-1)
+				if delay < 0 {
+					delay = 0
+				}
+			}
+
+			re = append(re, disk.DMTarget{
+				Start:  t.Start + done,
+				Length: length,
+				Type:   disk.DMTargetDelay,
+				Args:   getDelayArgs(mode, dev, strconv.FormatUint(offset+done, 10), delay),
+			})
+			done += length
+			idx++
+		}
+	}
+
+	return re
+}
+
+// getDelayArgs dm-delay: <device> <offset> <delay> [<write_device> <write_offset> <write_delay>]
+func getDelayArgs(mode, dev, offset string, delay int64) []string {
+	delayStr := strconv.FormatInt(delay, 10)
+	switch mode {
+	case ModeRead:
+		return []string{dev, offset, delayStr, dev, offset, "0"}
+	case ModeWrite:
+		return []string{dev, offset, "0", dev, offset, delayStr}
+	default:
+		return []string{dev, offset, delayStr}
+	}
+}
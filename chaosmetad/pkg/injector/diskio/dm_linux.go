@@ -0,0 +1,195 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package diskio
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/crclient"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/guardrail"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/disk"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// The delay and error faults replace the live table of the device-mapper device backing the directory, such as a LVM
+// logical volume, with a "delay" or "flakey" table, and load the original table back on recovery. All the I/O of the
+// device is affected, not only the directory.
+
+type DMRuntime struct {
+	Device        string `json:"device,omitempty"`
+	MountPoint    string `json:"mount_point,omitempty"`
+	OriginalTable string `json:"original_table,omitempty"`
+}
+
+func getMountInfoPath(ctx context.Context, cr, cId string) (string, error) {
+	if cr == "" {
+		return "/proc/self/mountinfo", nil
+	}
+
+	client, err := crclient.GetClient(ctx, cr)
+	if err != nil {
+		return "", fmt.Errorf("get %s client error: %s", cr, err.Error())
+	}
+
+	pid, err := client.GetPidById(ctx, cId)
+	if err != nil {
+		return "", fmt.Errorf("get pid of container[%s]'s init process error: %s", cId, err.Error())
+	}
+
+	return fmt.Sprintf("/proc/%d/mountinfo", pid), nil
+}
+
+// getMountDevice return the mount point and the device number of dir, dir is in the mount namespace of container
+func getMountDevice(ctx context.Context, cr, cId, dir string) (string, string, error) {
+	mountInfoPath, err := getMountInfoPath(ctx, cr, cId)
+	if err != nil {
+		return "", "", err
+	}
+
+	return disk.GetMountDevice(mountInfoPath, dir)
+}
+
+// getDMDevice return the device-mapper name and the mount point of dir, dir is in the mount namespace of container
+func getDMDevice(ctx context.Context, cr, cId, dir string) (string, string, string, error) {
+	mountPoint, devNum, err := getMountDevice(ctx, cr, cId, dir)
+	if err != nil {
+		return "", "", "", err
+	}
+
+	name, err := disk.GetDMName(devNum)
+	if err != nil {
+		return "", "", "", fmt.Errorf("%s is mounted on %s: %s", mountPoint, devNum, err.Error())
+	}
+
+	return name, mountPoint, devNum, nil
+}
+
+// getLinearTable only the device with linear table is supported, it also avoids injecting a device being injected
+func getLinearTable(ctx context.Context, name string) ([]disk.DMTarget, error) {
+	targets, err := disk.GetDMTable(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range targets {
+		if t.Type != disk.DMTargetLinear || len(t.Args) != 2 {
+			return nil, fmt.Errorf("table of %s has \"%s\" target, only the device with dm-linear table(eg: LVM logical volume) is supported, or other experiment is running on it", name, t.Type)
+		}
+	}
+
+	return targets, nil
+}
+
+// validateDMDir if protectSystem, the device of host's root or chaosmetad's db is refused, otherwise the recovery may fail
+func validateDMDir(ctx context.Context, i *injector.BaseInjector, dir string, protectSystem bool) error {
+	if dir == "" {
+		return fmt.Errorf("\"dir\" is empty")
+	}
+
+	if !filepath.IsAbs(dir) {
+		return fmt.Errorf("\"dir\"[%s] must be an absolute path", dir)
+	}
+
+	if !cmdexec.SupportCmd("dmsetup") {
+		return fmt.Errorf("not support command \"dmsetup\"")
+	}
+
+	name, mountPoint, devNum, err := getDMDevice(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, dir)
+	if err != nil {
+		return fmt.Errorf("\"dir\"[%s] is invalid: %s, only the directory on a device-mapper device with dm-linear table(eg: LVM logical volume) is supported", dir, err.Error())
+	}
+
+	if _, err := getLinearTable(ctx, name); err != nil {
+		return err
+	}
+
+	if protectSystem {
+		if err := checkSystemDevice(name, mountPoint, devNum); err != nil {
+			return err
+		}
+	}
+
+	return i.CheckGuardrail(ctx, &guardrail.Resources{Paths: []string{mountPoint}})
+}
+
+// checkSystemDevice refuse the device of host's root or chaosmetad's db, otherwise the recovery may fail
+func checkSystemDevice(name, mountPoint, devNum string) error {
+	for mountInfoPath, path := range map[string]string{"/proc/1/mountinfo": "/", "/proc/self/mountinfo": filepath.Dir(storage.GetDBPath())} {
+		if _, systemDevNum, err := disk.GetMountDevice(mountInfoPath, path); err == nil && systemDevNum == devNum {
+			return fmt.Errorf("device[%s] of %s holds the system path[%s], which is needed by recovery", name, mountPoint, path)
+		}
+	}
+
+	return nil
+}
+
+// injectDMTable save the original table to runtime and load the table built from it
+func injectDMTable(ctx context.Context, cr, cId, dir string, r *DMRuntime, build func([]disk.DMTarget) []disk.DMTarget) error {
+	name, mountPoint, _, err := getDMDevice(ctx, cr, cId, dir)
+	if err != nil {
+		return err
+	}
+
+	targets, err := getLinearTable(ctx, name)
+	if err != nil {
+		return err
+	}
+
+	r.Device, r.MountPoint, r.OriginalTable = name, mountPoint, disk.FormatDMTable(targets)
+	return disk.ReloadDMTable(ctx, name, build(targets))
+}
+
+func recoverDMTable(ctx context.Context, r *DMRuntime) error {
+	if r.Device == "" || r.OriginalTable == "" {
+		return nil
+	}
+
+	if _, err := os.Stat(filepath.Join("/dev/mapper", r.Device)); os.IsNotExist(err) {
+		log.GetLogger(ctx).Warnf("device[%s] is not exist, no need to recover", r.Device)
+		return nil
+	}
+
+	targets, err := disk.ParseDMTable(r.OriginalTable)
+	if err != nil {
+		return fmt.Errorf("original table of %s is invalid: %s", r.Device, err.Error())
+	}
+
+	return disk.ReloadDMTable(ctx, r.Device, targets)
+}
+
+// getMilliseconds parse the latency for device-mapper, support unit: "s、ms"(default ms)
+func getMilliseconds(value string) (int64, error) {
+	unit, multiple := "ms", int64(1)
+	if strings.HasSuffix(value, "ms") {
+		value = strings.TrimSuffix(value, "ms")
+	} else if strings.HasSuffix(value, "s") {
+		unit, multiple, value = "s", 1000, strings.TrimSuffix(value, "s")
+	}
+
+	re, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || re < 0 {
+		return -1, fmt.Errorf("\"%s\" is not a non-negative integer with unit %s", value, unit)
+	}
+
+	return re * multiple, nil
+}
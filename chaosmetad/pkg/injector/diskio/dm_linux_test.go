@@ -0,0 +1,389 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package diskio
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/disk"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func dmTarget(start, length uint64, targetType string, args ...string) disk.DMTarget {
+	return disk.DMTarget{Start: start, Length: length, Type: targetType, Args: args}
+}
+
+func TestBuildDelayTable(t *testing.T) {
+	linear := []disk.DMTarget{dmTarget(0, 4096, disk.DMTargetLinear, []string{"7:0", "2048"}...)}
+	tests := []struct {
+		name    string
+		targets []disk.DMTarget
+		mode    string
+		latency int64
+		jitter  int64
+		want    []disk.DMTarget
+	}{
+		{"all", linear, ModeAll, 100, 0, []disk.DMTarget{dmTarget(0, 4096, disk.DMTargetDelay, []string{"7:0", "2048", "100"}...)}},
+		{"read", linear, ModeRead, 100, 0, []disk.DMTarget{dmTarget(0, 4096, disk.DMTargetDelay, []string{"7:0", "2048", "100", "7:0", "2048", "0"}...)}},
+		{"write", linear, ModeWrite, 100, 0, []disk.DMTarget{dmTarget(0, 4096, disk.DMTargetDelay, []string{"7:0", "2048", "0", "7:0", "2048", "100"}...)}},
+		{"jitter", linear, ModeAll, 10, 14, []disk.DMTarget{
+			dmTarget(0, 2048, disk.DMTargetDelay, []string{"7:0", "2048", "0"}...),
+			dmTarget(2048, 2048, disk.DMTargetDelay, []string{"7:0", "4096", "16"}...),
+		}},
+		{"jitter over targets", []disk.DMTarget{
+			dmTarget(0, 3000, disk.DMTargetLinear, []string{"7:0", "0"}...),
+			dmTarget(3000, 1000, disk.DMTargetLinear, []string{"7:1", "0"}...),
+		}, ModeAll, 100, 70, []disk.DMTarget{
+			dmTarget(0, 2048, disk.DMTargetDelay, []string{"7:0", "0", "30"}...),
+			dmTarget(2048, 952, disk.DMTargetDelay, []string{"7:0", "2048", "130"}...),
+			dmTarget(3000, 1000, disk.DMTargetDelay, []string{"7:1", "0", "70"}...),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildDelayTable(tt.targets, tt.mode, tt.latency, tt.jitter); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("buildDelayTable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBuildErrorTable(t *testing.T) {
+	linear := []disk.DMTarget{dmTarget(0, 4096, disk.DMTargetLinear, []string{"7:0", "2048"}...)}
+	tests := []struct {
+		name     string
+		mode     string
+		percent  int
+		interval int64
+		want     []string
+	}{
+		{"all", ModeAll, 100, 10, []string{"7:0", "2048", "0", "10"}},
+		{"read", ModeRead, 30, 10, []string{"7:0", "2048", "7", "3", "1", "error_reads"}},
+		{"write", ModeWrite, 50, 60, []string{"7:0", "2048", "30", "30", "1", "error_writes"}},
+		{"at least one second", ModeAll, 1, 10, []string{"7:0", "2048", "9", "1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			want := []disk.DMTarget{dmTarget(0, 4096, disk.DMTargetFlakey, tt.want...)}
+			if got := buildErrorTable(linear, tt.mode, tt.percent, tt.interval); !reflect.DeepEqual(got, want) {
+				t.Errorf("buildErrorTable() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestIsKernelAtLeast(t *testing.T) {
+	tests := []struct {
+		release string
+		want    bool
+	}{
+		{"6.5.0-14-generic", true},
+		{"6.8.12", true},
+		{"7.0", true},
+		{"6.4.16-arch1", false},
+		{"5.15.0-91-generic", false},
+		{"4.19.91-27.al7.x86_64", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.release, func(t *testing.T) {
+			if got := isKernelAtLeast(tt.release, ErrorReadsKernel); got != tt.want {
+				t.Errorf("isKernelAtLeast() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// The harness creates a linear device-mapper device on a loop device, which is the same as a LVM logical volume,
+// formats and mounts it on a temp directory. It must be run as root: go test ./pkg/injector/diskio/
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "chaosmetad-diskio-test")
+	if err != nil {
+		fmt.Printf("create temp dir error: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	storage.DBPath = filepath.Join(dir, "chaosmetad.dat")
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+type testDM struct {
+	name  string
+	dir   string
+	table string
+}
+
+func runCmd(cmd string) (string, error) {
+	out, err := exec.Command("/bin/bash", "-c", cmd).CombinedOutput()
+	if err != nil {
+		return string(out), fmt.Errorf("run [%s] error: %s, output: %s", cmd, err.Error(), string(out))
+	}
+
+	return string(out), nil
+}
+
+func requireDM(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("device-mapper harness must run as root")
+	}
+
+	for _, c := range []string{"losetup", "dmsetup", "mkfs.ext4", "dd"} {
+		if !cmdexec.SupportCmd(c) {
+			t.Skipf("command \"%s\" is not found", c)
+		}
+	}
+
+	if _, err := os.Stat("/dev/mapper/control"); err != nil {
+		t.Skip("device-mapper is not supported by kernel")
+	}
+
+	if out, err := runCmd("dmsetup targets"); err != nil || !strings.Contains(out, disk.DMTargetDelay) || !strings.Contains(out, disk.DMTargetFlakey) {
+		t.Skipf("device-mapper target \"delay\" or \"flakey\" is not supported: %s", out)
+	}
+}
+
+// newTestDM creates a 64MB device-mapper device on a loop device and mounts it
+func newTestDM(t *testing.T) *testDM {
+	requireDM(t)
+	base := t.TempDir()
+	d := &testDM{name: fmt.Sprintf("cmt%d-%s", os.Getpid(), strings.ToLower(t.Name())), dir: filepath.Join(base, "mnt")}
+	d.name = strings.NewReplacer("/", "-", "_", "-").Replace(d.name)
+	img := filepath.Join(base, "disk.img")
+
+	if _, err := runCmd(fmt.Sprintf("truncate -s 64M %s && mkdir -p %s", img, d.dir)); err != nil {
+		t.Fatal(err)
+	}
+
+	loop, err := runCmd(fmt.Sprintf("losetup -f --show %s", img))
+	if err != nil {
+		t.Skipf("loop device is not available: %v", err)
+	}
+	loop = strings.TrimSpace(loop)
+	t.Cleanup(func() {
+		_, _ = runCmd(fmt.Sprintf("umount %s; dmsetup remove %s; losetup -d %s", d.dir, d.name, loop))
+	})
+
+	d.table = fmt.Sprintf("0 %d linear %s 0", 64*1024*2, loop)
+	for _, cmd := range []string{
+		fmt.Sprintf("dmsetup create %s --table '%s'", d.name, d.table),
+		fmt.Sprintf("mkfs.ext4 -q /dev/mapper/%s", d.name),
+		// do not remount read-only when the error fault fails the journal
+		fmt.Sprintf("mount -o errors=continue /dev/mapper/%s %s", d.name, d.dir),
+	} {
+		if _, err := runCmd(cmd); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// dmsetup shows the device number of the loop device instead of its path
+	table, err := runCmd(fmt.Sprintf("dmsetup table %s", d.name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.table = strings.TrimSpace(table)
+
+	return d
+}
+
+// readDirect reads a block of the device bypassing the page cache
+func (d *testDM) readDirect() (time.Duration, error) {
+	start := time.Now()
+	_, err := runCmd(fmt.Sprintf("dd if=/dev/mapper/%s of=/dev/null bs=4k count=1 skip=%d iflag=direct", d.name, time.Now().UnixNano()%4096))
+	return time.Since(start), err
+}
+
+func (d *testDM) inject(t *testing.T, fault, args string) string {
+	i, err := injector.NewInjector(TargetDiskIO, fault)
+	if err != nil {
+		t.Fatalf("NewInjector() error = %v", err)
+	}
+
+	if err := i.LoadInjector(&storage.Experiment{Target: TargetDiskIO, Fault: fault, Args: args, Runtime: "{}"}, i.GetArgs(), i.GetRuntime()); err != nil {
+		t.Fatalf("LoadInjector() error = %v", err)
+	}
+
+	if code, msg := injector.ProcessInject(context.Background(), i); code != errutil.NoErr {
+		t.Fatalf("ProcessInject() code: %d, msg: %s", code, msg)
+	}
+
+	return i.GetInfo().Uid
+}
+
+// recover recovers the experiment and asserts that the original table is loaded back
+func (d *testDM) recover(t *testing.T, uid string) {
+	if code, msg := injector.ProcessRecover(context.Background(), uid); code != errutil.NoErr {
+		t.Fatalf("ProcessRecover() code: %d, msg: %s", code, msg)
+	}
+
+	if table, err := runCmd(fmt.Sprintf("dmsetup table %s", d.name)); err != nil || strings.TrimSpace(table) != d.table {
+		t.Errorf("table after recover = %s, %v, want %s", table, err, d.table)
+	}
+}
+
+func TestDelayInjector(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     string
+		min, max time.Duration
+	}{
+		{"latency", `{"dir":"%s","mode":"read","latency":"300ms"}`, 300 * time.Millisecond, 2 * time.Second},
+		{"jitter", `{"dir":"%s","latency":"400ms","segment_jitter":"100ms"}`, 300 * time.Millisecond, 2 * time.Second},
+		{"write only", `{"dir":"%s","mode":"write","latency":"1s"}`, 0, 500 * time.Millisecond},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := newTestDM(t)
+			uid := d.inject(t, FaultDiskIODelay, fmt.Sprintf(tt.args, d.dir))
+			cost, err := d.readDirect()
+			d.recover(t, uid)
+
+			if err != nil || cost < tt.min || cost > tt.max {
+				t.Errorf("read during injection: cost = %s, error = %v, want in [%s, %s]", cost, err, tt.min, tt.max)
+			}
+
+			if cost, err := d.readDirect(); err != nil || cost > tt.min/2+100*time.Millisecond {
+				t.Errorf("read after recover: cost = %s, error = %v", cost, err)
+			}
+		})
+	}
+}
+
+func TestErrorInjector(t *testing.T) {
+	d := newTestDM(t)
+	uid := d.inject(t, FaultDiskIOError, fmt.Sprintf(`{"dir":"%s","interval":"60s"}`, d.dir))
+	_, during := d.readDirect()
+	d.recover(t, uid)
+
+	if during == nil {
+		t.Errorf("read during injection succeeded, want IO error")
+	}
+
+	if _, err := d.readDirect(); err != nil {
+		t.Errorf("read after recover error = %v", err)
+	}
+}
+
+func requireFailRequest(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("fail_make_request harness must run as root")
+	}
+
+	for _, c := range []string{"losetup", "mkfs.ext4", "dd"} {
+		if !cmdexec.SupportCmd(c) {
+			t.Skipf("command \"%s\" is not found", c)
+		}
+	}
+
+	config, err := disk.GetFailRequestConfig()
+	if err != nil {
+		t.Skipf("fail_make_request is not supported: %v", err)
+	}
+
+	if config.Probability != "0" {
+		t.Skipf("fail_make_request is in use, probability: %s", config.Probability)
+	}
+}
+
+// TestErrorInjector_ErrorPercent fails each request of a plain loop device, no device-mapper is needed
+func TestErrorInjector_ErrorPercent(t *testing.T) {
+	requireFailRequest(t)
+	base := t.TempDir()
+	img, dir := filepath.Join(base, "disk.img"), filepath.Join(base, "mnt")
+	if _, err := runCmd(fmt.Sprintf("truncate -s 64M %s && mkdir -p %s && mkfs.ext4 -q %s", img, dir, img)); err != nil {
+		t.Fatal(err)
+	}
+
+	loop, err := runCmd(fmt.Sprintf("losetup -f --show %s", img))
+	if err != nil {
+		t.Skipf("loop device is not available: %v", err)
+	}
+	loop = strings.TrimSpace(loop)
+	t.Cleanup(func() {
+		_, _ = runCmd(fmt.Sprintf("umount %s; losetup -d %s", dir, loop))
+	})
+
+	if _, err := runCmd(fmt.Sprintf("mount -o errors=continue %s %s", loop, dir)); err != nil {
+		t.Fatal(err)
+	}
+
+	readDirect := func() error {
+		_, err := runCmd(fmt.Sprintf("dd if=%s of=/dev/null bs=4k count=1 skip=%d iflag=direct", loop, time.Now().UnixNano()%4096))
+		return err
+	}
+
+	i, err := injector.NewInjector(TargetDiskIO, FaultDiskIOError)
+	if err != nil {
+		t.Fatalf("NewInjector() error = %v", err)
+	}
+
+	if err := i.LoadInjector(&storage.Experiment{Target: TargetDiskIO, Fault: FaultDiskIOError, Args: fmt.Sprintf(`{"dir":"%s","error_percent":100}`, dir), Runtime: "{}"}, i.GetArgs(), i.GetRuntime()); err != nil {
+		t.Fatalf("LoadInjector() error = %v", err)
+	}
+
+	if code, msg := injector.ProcessInject(context.Background(), i); code != errutil.NoErr {
+		t.Fatalf("ProcessInject() code: %d, msg: %s", code, msg)
+	}
+
+	during := readDirect()
+	if code, msg := injector.ProcessRecover(context.Background(), i.GetInfo().Uid); code != errutil.NoErr {
+		t.Fatalf("ProcessRecover() code: %d, msg: %s", code, msg)
+	}
+
+	if during == nil {
+		t.Errorf("read during injection succeeded, want IO error")
+	}
+
+	if err := readDirect(); err != nil {
+		t.Errorf("read after recover error = %v", err)
+	}
+
+	if config, err := disk.GetFailRequestConfig(); err != nil || config.Probability != "0" {
+		t.Errorf("fail_make_request after recover = %v, %v, want probability 0", config, err)
+	}
+}
+
+func TestErrorInjector_Validator(t *testing.T) {
+	tests := []struct {
+		name string
+		args ErrorArgs
+	}{
+		{name: "error percent out of range", args: ErrorArgs{Dir: "/tmp", Mode: ModeAll, ErrorPercent: 101}},
+		{name: "error percent with read", args: ErrorArgs{Dir: "/tmp", Mode: ModeRead, ErrorPercent: 10}},
+		{name: "error percent with interval", args: ErrorArgs{Dir: "/tmp", Mode: ModeAll, ErrorPercent: 10, Interval: "10s"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := &ErrorInjector{Args: tt.args}
+			i.Info.Uid = "diskio-validator"
+			if err := i.Validator(context.Background()); err == nil || !strings.Contains(err.Error(), "error-percent") {
+				t.Errorf("Validator() error = %v, want error of \"error-percent\"", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package diskio
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/guardrail"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/disk"
+	"golang.org/x/sys/unix"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// The error fault uses dm-flakey by default, which fails the IO for "down" seconds after being available for "up"
+// seconds, so "down-percent" is the proportion of time in each interval rather than the probability of each IO.
+// With "error-percent", each request fails independently by fail_make_request of kernel instead, which works on any
+// block device but can not select read or write
+
+func init() {
+	injector.Register(TargetDiskIO, FaultDiskIOError, func() injector.IInjector { return &ErrorInjector{} })
+}
+
+type ErrorInjector struct {
+	injector.BaseInjector
+	Args    ErrorArgs
+	Runtime ErrorRuntime
+}
+
+type ErrorArgs struct {
+	Dir          string `json:"dir"`
+	Mode         string `json:"mode,omitempty"`
+	DownPercent  int    `json:"down_percent,omitempty"`
+	Interval     string `json:"interval,omitempty"`
+	ErrorPercent int    `json:"error_percent,omitempty"`
+}
+
+type ErrorRuntime struct {
+	DMRuntime
+	// DevNum and OriginalFailRequest are used by "error-percent", the original config is restored on recovery
+	DevNum              string                  `json:"dev_num,omitempty"`
+	OriginalFailRequest *disk.FailRequestConfig `json:"original_fail_request,omitempty"`
+}
+
+func (i *ErrorInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *ErrorInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *ErrorInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Mode == "" {
+		i.Args.Mode = ModeAll
+	}
+
+	if i.Args.ErrorPercent != 0 {
+		return
+	}
+
+	if i.Args.DownPercent == 0 {
+		i.Args.DownPercent = 100
+	}
+
+	if i.Args.Interval == "" {
+		i.Args.Interval = DefaultErrorInterval
+	}
+}
+
+func (i *ErrorInjector) SetOption(cmd *cobra.Command) {
+	// i.BaseInjector.SetOption(cmd)
+
+	cmd.Flags().StringVarP(&i.Args.Dir, "dir", "d", "", "target directory, the whole device of it will return IO error. It must be on a device-mapper device with dm-linear table(eg: LVM logical volume), unless \"error-percent\" is provided")
+	cmd.Flags().StringVarP(&i.Args.Mode, "mode", "m", "", fmt.Sprintf("failed disk IO mode, support: %s、%s、%s, \"%s\" requires linux %s+（default %s）", ModeRead, ModeWrite, ModeAll, ModeRead, ErrorReadsKernel, ModeAll))
+	cmd.Flags().IntVarP(&i.Args.DownPercent, "down-percent", "p", 0, "percentage of time in each interval that all the IO fails, it is not the probability of each IO, range: 1-100（default 100）")
+	cmd.Flags().StringVarP(&i.Args.Interval, "interval", "i", "", fmt.Sprintf("the cycle of available and failure window, support unit: s、m、h、d（default %s）", DefaultErrorInterval))
+	cmd.Flags().IntVarP(&i.Args.ErrorPercent, "error-percent", "e", 0, fmt.Sprintf("percentage of IO requests which fail, each request fails independently. It needs CONFIG_FAIL_MAKE_REQUEST of kernel and debugfs mounted on %s, supports any block device, only supports mode \"%s\" and can not be used with \"down-percent\" and \"interval\", range: 1-100", filepath.Dir(disk.FailRequestDir), ModeAll))
+}
+
+func (i *ErrorInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.Mode != ModeRead && i.Args.Mode != ModeWrite && i.Args.Mode != ModeAll {
+		return fmt.Errorf("\"mode\" not support %s, only support: %s、%s、%s", i.Args.Mode, ModeRead, ModeWrite, ModeAll)
+	}
+
+	if i.Args.ErrorPercent != 0 {
+		return i.validateFailRequest(ctx)
+	}
+
+	if i.Args.DownPercent < 1 || i.Args.DownPercent > 100 {
+		return fmt.Errorf("\"down-percent\"[%d] must be in range: 1-100", i.Args.DownPercent)
+	}
+
+	if i.Args.Mode == ModeRead {
+		if err := checkErrorReads(); err != nil {
+			return err
+		}
+	}
+
+	interval, err := utils.GetTimeSecond(i.Args.Interval)
+	if err != nil {
+		return fmt.Errorf("\"interval\"[%s] is invalid: %s", i.Args.Interval, err.Error())
+	}
+
+	if interval <= 0 {
+		return fmt.Errorf("\"interval\"[%s] must larger than 0", i.Args.Interval)
+	}
+
+	return validateDMDir(ctx, &i.BaseInjector, i.Args.Dir, true)
+}
+
+func (i *ErrorInjector) validateFailRequest(ctx context.Context) error {
+	if i.Args.ErrorPercent < 1 || i.Args.ErrorPercent > 100 {
+		return fmt.Errorf("\"error-percent\"[%d] must be in range: 1-100", i.Args.ErrorPercent)
+	}
+
+	if i.Args.Mode != ModeAll {
+		return fmt.Errorf("\"error-percent\" can not select read or write, only support mode: %s", ModeAll)
+	}
+
+	if i.Args.DownPercent != 0 || i.Args.Interval != "" {
+		return fmt.Errorf("\"error-percent\" can not be used with \"down-percent\" and \"interval\"")
+	}
+
+	if i.Args.Dir == "" {
+		return fmt.Errorf("\"dir\" is empty")
+	}
+
+	if !filepath.IsAbs(i.Args.Dir) {
+		return fmt.Errorf("\"dir\"[%s] must be an absolute path", i.Args.Dir)
+	}
+
+	config, err := disk.GetFailRequestConfig()
+	if err != nil {
+		return fmt.Errorf("\"error-percent\" needs CONFIG_FAIL_MAKE_REQUEST of kernel and debugfs mounted on %s: %s", filepath.Dir(disk.FailRequestDir), err.Error())
+	}
+
+	// the config is shared by all the devices, so only one experiment can use it
+	if config.Probability != "0" {
+		return fmt.Errorf("fail_make_request is in use by other experiment or the system, probability: %s", config.Probability)
+	}
+
+	mountPoint, devNum, err := getMountDevice(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Dir)
+	if err != nil {
+		return fmt.Errorf("\"dir\"[%s] is invalid: %s", i.Args.Dir, err.Error())
+	}
+
+	if err := disk.CheckMakeItFail(devNum); err != nil {
+		return err
+	}
+
+	if err := checkSystemDevice(devNum, mountPoint, devNum); err != nil {
+		return err
+	}
+
+	return i.CheckGuardrail(ctx, &guardrail.Resources{Paths: []string{mountPoint}})
+}
+
+func (i *ErrorInjector) Inject(ctx context.Context) error {
+	if i.Args.ErrorPercent != 0 {
+		return i.injectFailRequest(ctx)
+	}
+
+	interval, _ := utils.GetTimeSecond(i.Args.Interval)
+	return injectDMTable(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Dir, &i.Runtime.DMRuntime, func(targets []disk.DMTarget) []disk.DMTarget {
+		return buildErrorTable(targets, i.Args.Mode, i.Args.DownPercent, interval)
+	})
+}
+
+// injectFailRequest save the original config of fail_make_request to runtime, then fail the requests of the device
+func (i *ErrorInjector) injectFailRequest(ctx context.Context) error {
+	mountPoint, devNum, err := getMountDevice(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Dir)
+	if err != nil {
+		return err
+	}
+
+	original, err := disk.GetFailRequestConfig()
+	if err != nil {
+		return err
+	}
+
+	i.Runtime.MountPoint, i.Runtime.DevNum, i.Runtime.OriginalFailRequest = mountPoint, devNum, original
+	// "times" -1 means no limit, "verbose" 0 avoids logging every failed request to dmesg
+	if err := disk.SetFailRequestConfig(&disk.FailRequestConfig{
+		Probability: strconv.Itoa(i.Args.ErrorPercent),
+		Interval:    "1",
+		Times:       "-1",
+		Verbose:     "0",
+	}); err != nil {
+		_ = disk.SetFailRequestConfig(original)
+		return err
+	}
+
+	if err := disk.SetMakeItFail(devNum, true); err != nil {
+		_ = disk.SetFailRequestConfig(original)
+		return err
+	}
+
+	return nil
+}
+
+func (i *ErrorInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	if i.Runtime.DevNum != "" {
+		return recoverFailRequest(ctx, &i.Runtime)
+	}
+
+	return recoverDMTable(ctx, &i.Runtime.DMRuntime)
+}
+
+func recoverFailRequest(ctx context.Context, r *ErrorRuntime) error {
+	if err := disk.CheckMakeItFail(r.DevNum); err != nil {
+		log.GetLogger(ctx).Warnf("device[%s] is not exist, no need to recover it: %s", r.DevNum, err.Error())
+	} else if err := disk.SetMakeItFail(r.DevNum, false); err != nil {
+		return err
+	}
+
+	if r.OriginalFailRequest == nil {
+		return nil
+	}
+
+	return disk.SetFailRequestConfig(r.OriginalFailRequest)
+}
+
+// checkErrorReads the "error_reads" feature of dm-flakey used by mode read is added in linux 6.5
+func checkErrorReads() error {
+	var uts unix.Utsname
+	if err := unix.Uname(&uts); err != nil {
+		return fmt.Errorf("get kernel version error: %s", err.Error())
+	}
+
+	release := unix.ByteSliceToString(uts.Release[:])
+	if !isKernelAtLeast(release, ErrorReadsKernel) {
+		return fmt.Errorf("\"mode\"[%s] needs the \"error_reads\" feature of dm-flakey, which requires linux %s+, current kernel: %s", ModeRead, ErrorReadsKernel, release)
+	}
+
+	return nil
+}
+
+// isKernelAtLeast compare the major and minor version of kernel release, eg: "6.5.0-14-generic"
+func isKernelAtLeast(release, version string) bool {
+	parse := func(v string) (int, int) {
+		fields := strings.FieldsFunc(v, func(r rune) bool { return r < '0' || r > '9' })
+		var nums [2]int
+		for j := 0; j < len(fields) && j < 2; j++ {
+			nums[j], _ = strconv.Atoi(fields[j])
+		}
+		return nums[0], nums[1]
+	}
+
+	major, minor := parse(release)
+	wantMajor, wantMinor := parse(version)
+	return major > wantMajor || (major == wantMajor && minor >= wantMinor)
+}
+
+// buildErrorTable dm-flakey: <device> <offset> <up interval> <down interval> [<num_features> [<feature>]]
+func buildErrorTable(targets []disk.DMTarget, mode string, percent int, interval int64) []disk.DMTarget {
+	down := interval * int64(percent) / 100
+	if down < 1 {
+		down = 1
+	}
+	up := interval - down
+
+	var features []string
+	switch mode {
+	case ModeRead:
+		// without features dm-flakey fails both read and write
+		features = []string{"1", "error_reads"}
+	case ModeWrite:
+		features = []string{"1", "error_writes"}
+	}
+
+	re := make([]disk.DMTarget, len(targets))
+	for j, t := range targets {
+		args := []string{t.Args[0], t.Args[1], strconv.FormatInt(up, 10), strconv.FormatInt(down, 10)}
+		re[j] = disk.DMTarget{
+			Start:  t.Start,
+			Length: t.Length,
+			Type:   disk.DMTargetFlakey,
+			Args:   append(args, features...),
+		}
+	}
+
+	return re
+}
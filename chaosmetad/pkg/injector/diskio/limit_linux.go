@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/guardrail"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
@@ -87,6 +88,16 @@ func (i *LimitInjector) Validator(ctx context.Context) error {
 		return fmt.Errorf("\"pid-list\" or \"key\" is invalid: %s", err.Error())
 	}
 
+	// pid list is in host's pid namespace
+	r, err := guardrail.ProcessResources(ctx, "", "", pidList)
+	if err != nil {
+		return fmt.Errorf("get resources of target process error: %s", err.Error())
+	}
+
+	if err := i.CheckGuardrail(ctx, r); err != nil {
+		return err
+	}
+
 	if err := cgroup.CheckPidListBlkioCgroup(ctx, pidList); err != nil {
 		return fmt.Errorf("check cgroup of %v error: %s", pidList, err.Error())
 	}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/guardrail"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
@@ -84,6 +85,10 @@ func (i *RecordInjector) Validator(ctx context.Context) error {
 		return fmt.Errorf("args \"mode\" only support: %s, %s", ModeAdd, ModeDelete)
 	}
 
+	if err := i.CheckGuardrail(ctx, &guardrail.Resources{Paths: []string{ConfRecord}}); err != nil {
+		return err
+	}
+
 	return nil
 }
 
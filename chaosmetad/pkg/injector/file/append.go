@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/guardrail"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/filesys"
@@ -109,6 +110,10 @@ func (i *AppendInjector) Validator(ctx context.Context) error {
 		return fmt.Errorf("\"content is not a valid base64 format\"")
 	}
 
+	if err := i.CheckGuardrail(ctx, &guardrail.Resources{Paths: []string{i.Args.Path}}); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/guardrail"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/filesys"
 	"path/filepath"
@@ -76,6 +77,10 @@ func (i *DeleteInjector) Validator(ctx context.Context) error {
 		return fmt.Errorf("file[%s] is not exist", i.Args.Path)
 	}
 
+	if err := i.CheckGuardrail(ctx, &guardrail.Resources{Paths: []string{i.Args.Path}}); err != nil {
+		return err
+	}
+
 	return nil
 }
 
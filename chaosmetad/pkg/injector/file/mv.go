@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/guardrail"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/filesys"
 )
@@ -94,6 +95,10 @@ func (i *MvInjector) Validator(ctx context.Context) error {
 		return fmt.Errorf("dst path[%s] is exist", i.Args.Dst)
 	}
 
+	if err := i.CheckGuardrail(ctx, &guardrail.Resources{Paths: []string{i.Args.Src, i.Args.Dst}}); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -19,9 +19,12 @@ package injector
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/audit"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/crclient"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/guardrail"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
@@ -30,6 +33,7 @@ import (
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/user"
 	"runtime/debug"
 	"strings"
+	"time"
 )
 
 type IInjector interface {
@@ -40,6 +44,7 @@ type IInjector interface {
 
 	GetArgs() interface{}
 	GetRuntime() interface{}
+	GetInfo() *BaseInfo
 
 	SetOption(cmd *cobra.Command)
 	SetDefault()
@@ -48,6 +53,11 @@ type IInjector interface {
 	Recover(ctx context.Context) error
 }
 
+// IQuerier is implemented by the injectors which can report the live status of the injected fault
+type IQuerier interface {
+	Query(ctx context.Context) (string, error)
+}
+
 /*=======================================Base Injector===================================================*/
 
 type BaseInjector struct {
@@ -69,6 +79,11 @@ type BaseInfo struct {
 	ContainerId      string `json:"container_id"`
 	ContainerRuntime string `json:"container_runtime"`
 	//ContainerNs      []string `json:"container_ns"`
+	// guardrail information
+	GuardrailOverride  bool   `json:"guardrail_override"`
+	GuardrailViolation string `json:"guardrail_violation"`
+	// intermittent information
+	Intermittent IntermittentInfo `json:"intermittent"`
 }
 
 func (i *BaseInjector) GetArgs() interface{} {
@@ -81,6 +96,10 @@ func (i *BaseInjector) GetRuntime() interface{} {
 	return empty
 }
 
+func (i *BaseInjector) GetInfo() *BaseInfo {
+	return &i.Info
+}
+
 func (i *BaseInjector) SetCommonArgs(info *BaseInfo) {
 	if info == nil {
 		return
@@ -113,6 +132,14 @@ func (i *BaseInjector) SetCommonArgs(info *BaseInfo) {
 	if info.ContainerId != "" {
 		i.Info.ContainerId = info.ContainerId
 	}
+
+	if info.GuardrailOverride {
+		i.Info.GuardrailOverride = info.GuardrailOverride
+	}
+
+	if info.Intermittent != (IntermittentInfo{}) {
+		i.Info.Intermittent = info.Intermittent
+	}
 }
 
 func (i *BaseInjector) SetOption(cmd *cobra.Command) {
@@ -165,6 +192,14 @@ func (i *BaseInjector) Validator(ctx context.Context) error {
 		if _, err := client.GetPidById(ctx, i.Info.ContainerId); err != nil {
 			return fmt.Errorf("check container error: %s", err.Error())
 		}
+
+		if err := i.CheckGuardrail(ctx, &guardrail.Resources{Containers: []string{i.Info.ContainerId}}); err != nil {
+			return err
+		}
+	}
+
+	if err := i.Info.Intermittent.Validate(); err != nil {
+		return fmt.Errorf("intermittent args error: %s", err.Error())
 	}
 
 	if err := utils.IsValidUid(i.Info.Uid); err != nil {
@@ -182,6 +217,32 @@ func (i *BaseInjector) Validator(ctx context.Context) error {
 	return nil
 }
 
+// CheckGuardrail should be called in Validator with the resources affected by the experiment, and the error should be returned directly.
+// If override is allowed, the violations will be recorded in the experiment instead of returning error
+func (i *BaseInjector) CheckGuardrail(ctx context.Context, r *guardrail.Resources) error {
+	policy, err := guardrail.LoadPolicy()
+	if err != nil {
+		return fmt.Errorf("load guardrail policy error: %s", err.Error())
+	}
+
+	violations := policy.Check(r)
+	if len(violations) == 0 {
+		return nil
+	}
+
+	if !i.Info.GuardrailOverride {
+		return &guardrail.ViolationError{Violations: violations}
+	}
+
+	log.GetLogger(ctx).Warnf("guardrail is overridden: %s", strings.Join(violations, "; "))
+	if i.Info.GuardrailViolation != "" {
+		violations = append([]string{i.Info.GuardrailViolation}, violations...)
+	}
+	i.Info.GuardrailViolation = strings.Join(violations, "; ")
+
+	return nil
+}
+
 func (i *BaseInjector) DelayRecover(ctx context.Context, timeout int64) error {
 	return cmdexec.StartSleepRecover(ctx, timeout, i.Info.Uid)
 }
@@ -204,6 +265,15 @@ func (i *BaseInjector) LoadInjector(exp *storage.Experiment, argsVar, rVar inter
 	i.Info.Timeout = exp.Timeout
 	i.Info.ContainerRuntime = exp.ContainerRuntime
 	i.Info.ContainerId = exp.ContainerId
+	i.Info.GuardrailOverride = exp.GuardrailOverride
+	i.Info.GuardrailViolation = exp.GuardrailViolation
+
+	i.Info.Intermittent = IntermittentInfo{}
+	if exp.Intermittent != "" {
+		if err := json.Unmarshal([]byte(exp.Intermittent), &i.Info.Intermittent); err != nil {
+			return fmt.Errorf("load intermittent from experiment error: %s", err.Error())
+		}
+	}
 
 	return nil
 }
@@ -230,6 +300,11 @@ func (i *BaseInjector) OptionToExp(args, r interface{}) (*storage.Experiment, er
 		Runtime:          string(runtimeByte),
 		ContainerRuntime: i.Info.ContainerRuntime,
 		ContainerId:      i.Info.ContainerId,
+		// guardrail
+		GuardrailOverride:  i.Info.GuardrailOverride,
+		GuardrailViolation: i.Info.GuardrailViolation,
+		// intermittent
+		Intermittent: i.Info.Intermittent.String(),
 	}
 
 	return exp, nil
@@ -249,6 +324,11 @@ func ProcessInject(ctx context.Context, i IInjector) (code int, msg string) {
 	i.SetDefault()
 
 	if err := i.Validator(ctx); err != nil {
+		var violationErr *guardrail.ViolationError
+		if errors.As(err, &violationErr) {
+			return errutil.GuardrailErr, err.Error()
+		}
+
 		return errutil.BadArgsErr, fmt.Sprintf("args error: %s", err.Error())
 	}
 
@@ -268,8 +348,9 @@ func ProcessInject(ctx context.Context, i IInjector) (code int, msg string) {
 
 	logger.Infof("uid: %s", exp.Uid)
 	logger.Infof("args: %s", exp.Args)
+	ctx = audit.WithExperiment(ctx, exp.Uid, utils.MethodInject)
 
-	if err := i.Inject(ctx); err != nil {
+	if err := injectCycle(ctx, i); err != nil {
 		errMsg := fmt.Sprintf("inject error: %s", err.Error())
 		if err := db.UpdateStatusAndErr(exp.Uid, utils.StatusError, errMsg); err != nil {
 			logger.Warnf("update status[%s] for experiment[%s] error: %s", utils.StatusError, exp.Uid, errMsg)
@@ -297,6 +378,12 @@ func ProcessInject(ctx context.Context, i IInjector) (code int, msg string) {
 		}
 	}
 
+	if i.GetInfo().Intermittent.IsEnabled() {
+		if err := cmdexec.StartIntermittent(ctx, exp.Uid, guardrail.GetPathArgs()); err != nil {
+			logger.Warnf("inject success but start intermittent process error: %s, the fault will not cycle", err.Error())
+		}
+	}
+
 	return errutil.NoErr, "success"
 }
 
@@ -321,6 +408,7 @@ func ProcessRecover(ctx context.Context, uid string) (code int, msg string) {
 	if err != nil {
 		return errutil.DBErr, fmt.Sprintf("query experiment by uid[%s] error: %s", uid, err.Error())
 	}
+	ctx = audit.WithExperiment(ctx, uid, utils.MethodRecover)
 
 	i, err := NewInjector(exp.Target, exp.Fault)
 	if err != nil {
@@ -331,10 +419,41 @@ func ProcessRecover(ctx context.Context, uid string) (code int, msg string) {
 		return errutil.InternalErr, fmt.Sprintf("load experiment to injector error: %s", err.Error())
 	}
 
-	if err := i.Recover(ctx); err != nil {
+	if i.GetInfo().Intermittent.IsEnabled() {
+		killed, err := stopIntermittent(ctx, uid, i.GetInfo().Intermittent.Runtime.Pid)
+		if err != nil {
+			return errutil.RecoverErr, fmt.Sprintf("stop intermittent process error: %s", err.Error())
+		}
+
+		// reload the cycle state saved by the stopped process
+		if exp, err = db.GetByUid(uid); err != nil {
+			return errutil.DBErr, fmt.Sprintf("query experiment by uid[%s] error: %s", uid, err.Error())
+		}
+
+		if err := i.LoadInjector(exp, i.GetArgs(), i.GetRuntime()); err != nil {
+			return errutil.InternalErr, fmt.Sprintf("load experiment to injector error: %s", err.Error())
+		}
+
+		// the killed process may be injecting, so the fault is recovered whatever the state saved
+		if killed {
+			logger.Warn("intermittent process is killed, the fault may be injected, recover it")
+			i.GetInfo().Intermittent.Runtime.Injected = true
+		}
+	}
+
+	if i.GetInfo().Intermittent.IsEnabled() && !i.GetInfo().Intermittent.Runtime.Injected {
+		logger.Info("fault is not injected in the current cycle, no need to recover")
+	} else if err := i.Recover(ctx); err != nil {
 		return errutil.RecoverErr, fmt.Sprintf("recover error: %s", err.Error())
 	}
 
+	if info := &i.GetInfo().Intermittent; info.IsEnabled() && info.Runtime.Injected {
+		info.Runtime.Injected, info.Runtime.LastChange = false, time.Now().Format(utils.TimeFormat)
+		if err := saveIntermittent(i); err != nil {
+			logger.Warnf("save intermittent state error: %s", err.Error())
+		}
+	}
+
 	logger.Info("recover success")
 
 	if err := db.UpdateStatus(uid, utils.StatusDestroyed); err != nil {
@@ -344,6 +463,29 @@ func ProcessRecover(ctx context.Context, uid string) (code int, msg string) {
 	return errutil.NoErr, "success"
 }
 
+// QueryDetail return the live status of the experiment reported by its injector, empty if not supported
+func QueryDetail(ctx context.Context, exp *storage.Experiment) (string, error) {
+	if exp.Status != utils.StatusSuccess {
+		return "", nil
+	}
+
+	i, err := NewInjector(exp.Target, exp.Fault)
+	if err != nil {
+		return "", fmt.Errorf("find injector by target[%s] and fault[%s] error: %s", exp.Target, exp.Fault, err.Error())
+	}
+
+	q, ok := i.(IQuerier)
+	if !ok {
+		return "", nil
+	}
+
+	if err := i.LoadInjector(exp, i.GetArgs(), i.GetRuntime()); err != nil {
+		return "", fmt.Errorf("load experiment to injector error: %s", err.Error())
+	}
+
+	return q.Query(ctx)
+}
+
 /*=======================================Command Constructor===================================================*/
 
 func NewCmdByTarget(target string, args *BaseInfo) *cobra.Command {
@@ -0,0 +1,339 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package injector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/audit"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"math/rand"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const intermittentStopInterval = 200 * time.Millisecond
+
+// intermittentStopTimeout the background process is killed if it does not exit in time after SIGTERM
+var intermittentStopTimeout = 10 * time.Second
+
+func init() {
+	rand.Seed(time.Now().UnixNano())
+}
+
+// IntermittentInfo makes the experiment cycle between inject and recover under the same uid
+type IntermittentInfo struct {
+	On          string              `json:"on,omitempty"`
+	Off         string              `json:"off,omitempty"`
+	Jitter      string              `json:"jitter,omitempty"`
+	Probability float64             `json:"probability,omitempty"`
+	Runtime     IntermittentRuntime `json:"runtime"`
+}
+
+type IntermittentRuntime struct {
+	// Pid is the background process which cycles the experiment
+	Pid int `json:"pid,omitempty"`
+	// Injected means the fault is injected in the current cycle
+	Injected   bool   `json:"injected"`
+	Cycles     int    `json:"cycles"`
+	Skipped    int    `json:"skipped"`
+	LastChange string `json:"last_change,omitempty"`
+	LastError  string `json:"last_error,omitempty"`
+}
+
+func (i *IntermittentInfo) IsEnabled() bool {
+	return i.On != "" || i.Off != ""
+}
+
+func (i *IntermittentInfo) Validate() error {
+	if !i.IsEnabled() {
+		if i.Jitter != "" || i.Probability != 0 {
+			return fmt.Errorf("\"on\" and \"off\" must be provided for intermittent experiment")
+		}
+		return nil
+	}
+
+	for name, value := range map[string]string{"on": i.On, "off": i.Off} {
+		second, err := utils.GetTimeSecond(value)
+		if err != nil || second <= 0 {
+			return fmt.Errorf("\"%s\" is not valid, must be a positive duration: %s", name, value)
+		}
+	}
+
+	if i.Jitter != "" {
+		if second, err := utils.GetTimeSecond(i.Jitter); err != nil || second < 0 {
+			return fmt.Errorf("\"on-off-jitter\" is not valid: %s", i.Jitter)
+		}
+	}
+
+	if i.Probability < 0 || i.Probability > 1 {
+		return fmt.Errorf("\"probability\" must be in (0, 1]")
+	}
+
+	return nil
+}
+
+func (i *IntermittentInfo) String() string {
+	if *i == (IntermittentInfo{}) {
+		return ""
+	}
+
+	reBytes, _ := json.Marshal(i)
+	return string(reBytes)
+}
+
+// nextDuration return the duration of the next phase, the jitter is added randomly and the result is at least 1s
+func (i *IntermittentInfo) nextDuration(injected bool) time.Duration {
+	base, _ := utils.GetTimeSecond(i.Off)
+	if injected {
+		base, _ = utils.GetTimeSecond(i.On)
+	}
+
+	if jitter, _ := utils.GetTimeSecond(i.Jitter); jitter > 0 {
+		base += rand.Int63n(2*jitter+1) - jitter
+	}
+
+	if base < 1 {
+		base = 1
+	}
+
+	return time.Duration(base) * time.Second
+}
+
+// hit decides if the fault is injected in this cycle, probability 0 means always
+func (i *IntermittentInfo) hit() bool {
+	return i.Probability == 0 || rand.Float64() < i.Probability
+}
+
+// injectCycle injects the experiment. For intermittent experiment, it starts a new cycle and may skip it by probability
+func injectCycle(ctx context.Context, i IInjector) error {
+	info := &i.GetInfo().Intermittent
+	if !info.IsEnabled() {
+		return i.Inject(ctx)
+	}
+
+	info.Runtime.Cycles++
+	info.Runtime.LastChange = time.Now().Format(utils.TimeFormat)
+	if !info.hit() {
+		info.Runtime.Skipped++
+		return nil
+	}
+
+	if err := i.Inject(ctx); err != nil {
+		return err
+	}
+
+	info.Runtime.Injected = true
+	return nil
+}
+
+func saveIntermittent(i IInjector) error {
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		return fmt.Errorf("connect db error: %s", err.Error())
+	}
+
+	exp, err := i.OptionToExp(i.GetArgs(), i.GetRuntime())
+	if err != nil {
+		return fmt.Errorf("create experiment error: %s", err.Error())
+	}
+
+	return db.UpdateRuntime(exp.Uid, exp.Runtime, exp.Intermittent)
+}
+
+// RunIntermittent cycles the experiment between inject and recover until it is not running or SIGTERM received.
+// It never recovers the experiment finally, the final recovery is done by ProcessRecover after this process exits
+func RunIntermittent(ctx context.Context, uid string) error {
+	logger := log.GetLogger(ctx)
+	sigCtx, cancel := signal.NotifyContext(ctx, syscall.SIGTERM, syscall.SIGINT)
+	defer cancel()
+
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		return fmt.Errorf("connect db error: %s", err.Error())
+	}
+
+	exp, err := db.GetByUid(uid)
+	if err != nil {
+		return fmt.Errorf("query experiment by uid[%s] error: %s", uid, err.Error())
+	}
+
+	i, err := NewInjector(exp.Target, exp.Fault)
+	if err != nil {
+		return fmt.Errorf("find injector by target[%s] and fault[%s] error: %s", exp.Target, exp.Fault, err.Error())
+	}
+
+	if err := i.LoadInjector(exp, i.GetArgs(), i.GetRuntime()); err != nil {
+		return fmt.Errorf("load experiment to injector error: %s", err.Error())
+	}
+
+	info := &i.GetInfo().Intermittent
+	if !info.IsEnabled() {
+		return fmt.Errorf("experiment[%s] is not intermittent", uid)
+	}
+
+	info.Runtime.Pid = os.Getpid()
+	if err := saveIntermittent(i); err != nil {
+		return fmt.Errorf("save pid error: %s", err.Error())
+	}
+
+	// a recover started before the pid is saved can not find this process by pid, so the status is checked again
+	if !isExperimentRunning(uid) {
+		logger.Infof("intermittent experiment[%s] is not running, exit", uid)
+		return nil
+	}
+
+	timer := time.NewTimer(info.nextDuration(info.Runtime.Injected))
+	defer timer.Stop()
+	for {
+		select {
+		case <-sigCtx.Done():
+			logger.Infof("intermittent experiment[%s] stopped", uid)
+			return nil
+		case <-timer.C:
+		}
+
+		if !isExperimentRunning(uid) {
+			logger.Infof("intermittent experiment[%s] is not running, exit", uid)
+			return nil
+		}
+
+		if info.Runtime.Injected {
+			if err := i.Recover(audit.WithExperiment(ctx, uid, utils.MethodRecover)); err != nil {
+				info.Runtime.LastError = fmt.Sprintf("recover error: %s", err.Error())
+				logger.Warnf("intermittent experiment[%s] %s", uid, info.Runtime.LastError)
+			} else {
+				info.Runtime.Injected = false
+				info.Runtime.LastChange = time.Now().Format(utils.TimeFormat)
+			}
+		} else if sigCtx.Err() != nil || !isExperimentRunning(uid) {
+			// the recover may start while the fault is recovered above, never inject after it
+			logger.Infof("intermittent experiment[%s] is stopping, exit", uid)
+			return nil
+		} else if err := injectCycle(audit.WithExperiment(ctx, uid, utils.MethodInject), i); err != nil {
+			info.Runtime.LastError = fmt.Sprintf("inject error: %s", err.Error())
+			logger.Warnf("intermittent experiment[%s] %s", uid, info.Runtime.LastError)
+		}
+
+		if err := saveIntermittent(i); err != nil {
+			logger.Warnf("save intermittent experiment[%s] error: %s", uid, err.Error())
+		}
+
+		timer.Reset(info.nextDuration(info.Runtime.Injected))
+	}
+}
+
+func isExperimentRunning(uid string) bool {
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		return false
+	}
+
+	exp, err := db.GetByUid(uid)
+	return err == nil && exp.Status == utils.StatusSuccess
+}
+
+// stopIntermittent stops the background process of the experiment and waits for its exit, it is killed if it does not
+// exit in time. If the pid is not saved yet, the process is found by its cmdline. killed is true if any process is
+// killed, then the fault may be injected while the state saved says not
+func stopIntermittent(ctx context.Context, uid string, pid int) (killed bool, err error) {
+	if pid <= 0 {
+		for _, p := range findIntermittentProcess(uid) {
+			pKilled, err := stopIntermittent(ctx, uid, p)
+			if err != nil {
+				return killed, err
+			}
+			killed = killed || pKilled
+		}
+		return killed, nil
+	}
+
+	if !isIntermittentProcess(uid, pid) {
+		return false, nil
+	}
+
+	log.GetLogger(ctx).Debugf("stop intermittent process[%d]", pid)
+	if err := syscall.Kill(pid, syscall.SIGTERM); err != nil {
+		return false, fmt.Errorf("send SIGTERM to intermittent process[%d] error: %s", pid, err.Error())
+	}
+
+	deadline := time.Now().Add(intermittentStopTimeout)
+	for time.Now().Before(deadline) {
+		if !isIntermittentProcess(uid, pid) {
+			return false, nil
+		}
+		time.Sleep(intermittentStopInterval)
+	}
+
+	log.GetLogger(ctx).Warnf("intermittent process[%d] does not exit in %s, kill it", pid, intermittentStopTimeout)
+	if err := syscall.Kill(pid, syscall.SIGKILL); err != nil && isIntermittentProcess(uid, pid) {
+		return false, fmt.Errorf("kill intermittent process[%d] error: %s", pid, err.Error())
+	}
+
+	// SIGKILL is delivered asynchronously, the fault can only be recovered after the process is gone
+	for deadline = time.Now().Add(intermittentStopTimeout); time.Now().Before(deadline); time.Sleep(intermittentStopInterval) {
+		if !isIntermittentProcess(uid, pid) {
+			return true, nil
+		}
+	}
+
+	return true, fmt.Errorf("intermittent process[%d] still exists after killed", pid)
+}
+
+// isIntermittentProcess checks the cmdline to avoid signaling a reused pid
+func isIntermittentProcess(uid string, pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+
+	cmdline, err := os.ReadFile("/proc/" + strconv.Itoa(pid) + "/cmdline")
+	if err != nil {
+		return false
+	}
+
+	// the process may still be the bash which starts it, so the cmdline is split by space too
+	args := strings.Fields(strings.ReplaceAll(string(cmdline), "\x00", " "))
+	return utils.StrListContain(args, "intermittent") && utils.StrListContain(args, uid)
+}
+
+// findIntermittentProcess return the background processes of the experiment by scanning /proc
+func findIntermittentProcess(uid string) []int {
+	entries, err := os.ReadDir("/proc")
+	if err != nil {
+		return nil
+	}
+
+	var pids []int
+	for _, entry := range entries {
+		pid, err := strconv.Atoi(entry.Name())
+		if err != nil || pid == os.Getpid() {
+			continue
+		}
+
+		if isIntermittentProcess(uid, pid) {
+			pids = append(pids, pid)
+		}
+	}
+
+	return pids
+}
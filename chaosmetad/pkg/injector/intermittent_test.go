@@ -0,0 +1,112 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package injector
+
+import (
+	"context"
+	"os/exec"
+	"testing"
+	"time"
+)
+
+func TestIntermittentInfo_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		info    IntermittentInfo
+		wantErr bool
+	}{
+		{name: "disabled", info: IntermittentInfo{}},
+		{name: "normal", info: IntermittentInfo{On: "5s", Off: "25s", Jitter: "2s", Probability: 0.5}},
+		{name: "minute unit", info: IntermittentInfo{On: "1m", Off: "1h"}},
+		{name: "only on", info: IntermittentInfo{On: "5s"}, wantErr: true},
+		{name: "jitter without on off", info: IntermittentInfo{Jitter: "2s"}, wantErr: true},
+		{name: "invalid off", info: IntermittentInfo{On: "5s", Off: "0s"}, wantErr: true},
+		{name: "invalid jitter", info: IntermittentInfo{On: "5s", Off: "5s", Jitter: "2x"}, wantErr: true},
+		{name: "invalid probability", info: IntermittentInfo{On: "5s", Off: "5s", Probability: 1.5}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.info.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestIntermittentInfo_nextDuration(t *testing.T) {
+	info := &IntermittentInfo{On: "5s", Off: "2s", Jitter: "3s"}
+	for n := 0; n < 100; n++ {
+		if d := info.nextDuration(true); d < 2*time.Second || d > 8*time.Second {
+			t.Fatalf("nextDuration(true) = %s, want in [2s, 8s]", d)
+		}
+
+		if d := info.nextDuration(false); d < time.Second || d > 5*time.Second {
+			t.Fatalf("nextDuration(false) = %s, want in [1s, 5s]", d)
+		}
+	}
+}
+
+func TestStopIntermittent_PidNotSaved(t *testing.T) {
+	uid := "intermittent-test-uid"
+	// the cycler started by bash which has not saved its pid yet
+	c := exec.Command("/bin/bash", "-c", "sleep 30; : intermittent "+uid)
+	if err := c.Start(); err != nil {
+		t.Fatalf("start process error: %v", err)
+	}
+	go c.Wait()
+
+	if pids := findIntermittentProcess(uid); len(pids) != 1 || pids[0] != c.Process.Pid {
+		t.Fatalf("findIntermittentProcess() = %v, want [%d]", pids, c.Process.Pid)
+	}
+
+	if killed, err := stopIntermittent(context.Background(), uid, 0); err != nil || killed {
+		t.Fatalf("stopIntermittent() = %v, %v, want false, nil", killed, err)
+	}
+
+	if isIntermittentProcess(uid, c.Process.Pid) {
+		t.Fatalf("intermittent process[%d] is not stopped", c.Process.Pid)
+	}
+}
+
+func TestStopIntermittent_Kill(t *testing.T) {
+	defer func(timeout time.Duration) {
+		intermittentStopTimeout = timeout
+	}(intermittentStopTimeout)
+	intermittentStopTimeout = time.Second
+
+	uid := "intermittent-kill-test-uid"
+	// the cycler stuck in injecting ignores SIGTERM
+	c := exec.Command("/bin/bash", "-c", "trap '' TERM; sleep 30; : intermittent "+uid)
+	if err := c.Start(); err != nil {
+		t.Fatalf("start process error: %v", err)
+	}
+	go c.Wait()
+
+	// wait for the trap to be set
+	time.Sleep(200 * time.Millisecond)
+	start := time.Now()
+	if killed, err := stopIntermittent(context.Background(), uid, c.Process.Pid); err != nil || !killed {
+		t.Fatalf("stopIntermittent() = %v, %v, want true, nil", killed, err)
+	}
+
+	if time.Since(start) > 3*time.Second {
+		t.Fatalf("stopIntermittent() takes %s, want about %s", time.Since(start), intermittentStopTimeout)
+	}
+
+	if isIntermittentProcess(uid, c.Process.Pid) {
+		t.Fatalf("intermittent process[%d] is not killed", c.Process.Pid)
+	}
+}
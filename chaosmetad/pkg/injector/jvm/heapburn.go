@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/guardrail"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
@@ -66,11 +67,20 @@ func (i *HeapBurnInjector) Validator(ctx context.Context) error {
 		return err
 	}
 
-	_, err := process.GetPidListByPidOrKeyInContainer(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, i.Args.Key)
+	pidList, err := process.GetPidListByPidOrKeyInContainer(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Pid, i.Args.Key)
 	if err != nil {
 		return fmt.Errorf("get target process's pid error: %s", err.Error())
 	}
 
+	r, err := guardrail.ProcessResources(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, pidList)
+	if err != nil {
+		return fmt.Errorf("get resources of target process error: %s", err.Error())
+	}
+
+	if err := i.CheckGuardrail(ctx, r); err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/guardrail"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
@@ -87,6 +88,15 @@ func (i *FdfullInjector) Validator(ctx context.Context) error {
 		return fmt.Errorf("\"count\" must larger than 0")
 	}
 
+	affectedPath := FileMaxPath
+	if i.Args.Mode == ModeFdFill {
+		affectedPath = i.getFdFullDir()
+	}
+
+	if err := i.CheckGuardrail(ctx, &guardrail.Resources{Paths: []string{affectedPath}}); err != nil {
+		return err
+	}
+
 	nowFd, maxFd, err := filesys.GetKernelFdStatus(ctx)
 	if err != nil {
 		return fmt.Errorf("get kernel max fd count error: %s", err.Error())
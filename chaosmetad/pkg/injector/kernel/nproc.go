@@ -20,12 +20,14 @@ import (
 	"context"
 	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/guardrail"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/user"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -81,6 +83,16 @@ func (i *NprocInjector) Validator(ctx context.Context) error {
 		return fmt.Errorf("\"user\" is invalid: %s", err.Error())
 	}
 
+	// the processes of the user can not fork any more
+	r, err := getUserProcessResources(ctx, i.Args.User)
+	if err != nil {
+		return fmt.Errorf("get processes of user[%s] error: %s", i.Args.User, err.Error())
+	}
+
+	if err := i.CheckGuardrail(ctx, r); err != nil {
+		return err
+	}
+
 	isExist, err := process.ExistProcessByKey(ctx, fmt.Sprintf("%s %s", FdFullKey, i.Args.User))
 	if err != nil {
 		return fmt.Errorf("check if running error: %s", err.Error())
@@ -93,6 +105,30 @@ func (i *NprocInjector) Validator(ctx context.Context) error {
 	return nil
 }
 
+func getUserProcessResources(ctx context.Context, userName string) (*guardrail.Resources, error) {
+	// ps exits with 1 when the user has no process
+	re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("ps -u %s -o pid=,comm= || true", utils.ShellQuote(userName)))
+	if err != nil {
+		return nil, err
+	}
+
+	r := &guardrail.Resources{}
+	for _, line := range strings.Split(strings.TrimSpace(re), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("pid[%s] is not a num", fields[0])
+		}
+		r.Pids, r.ProcessNames = append(r.Pids, pid), append(r.ProcessNames, fields[1])
+	}
+
+	return r, nil
+}
+
 func (i *NprocInjector) Inject(ctx context.Context) error {
 	var timeout int64
 	if i.Info.Timeout != "" {
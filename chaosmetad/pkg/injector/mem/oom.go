@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/guardrail"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
@@ -94,6 +95,10 @@ func (i *OOMInjector) Validator(ctx context.Context) error {
 		if !cmdexec.SupportCmd("mount") {
 			return fmt.Errorf("not support cmd \"mount\", can not fill cache")
 		}
+
+		if err := i.CheckGuardrail(ctx, &guardrail.Resources{Paths: []string{getOOMDir(i.Info.Uid)}}); err != nil {
+			return err
+		}
 	}
 
 	return nil
@@ -44,6 +44,22 @@ const (
 	DefaultGap     = 3
 	DefaultLatency = "1s"
 
+	FaultLinkDown = "linkdown"
+
+	FaultLinkFlap   = "linkflap"
+	DefaultFlapDown = "5s"
+	DefaultFlapUp   = "5s"
+
+	FaultFlood       = "flood"
+	FloodKey         = "chaosmeta_flood"
+	FloodRoleSend    = "send"
+	FloodRoleSink    = "sink"
+	FloodStatFile    = "/tmp/chaosmeta_flood_%s_%s.json"
+	DefaultFloodSize = "1KB"
+	MaxFloodConn     = 1024
+	MaxUDPFloodSize  = 65507
+	MaxTCPFloodSize  = 1024 * 1024
+
 	//NetworkExec = "chaosmeta_network"
 )
 
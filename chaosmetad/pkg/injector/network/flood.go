@@ -0,0 +1,274 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/guardrail"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/net"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+	gonet "net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	injector.Register(TargetNetwork, FaultFlood, func() injector.IInjector { return &FloodInjector{} })
+}
+
+type FloodInjector struct {
+	injector.BaseInjector
+	Args    FloodArgs
+	Runtime FloodRuntime
+}
+
+type FloodArgs struct {
+	DstIp    string `json:"dst_ip,omitempty"`
+	DstPort  int    `json:"dst_port,omitempty"`
+	Protocol string `json:"protocol,omitempty"`
+	Rate     string `json:"rate,omitempty"`
+	Size     string `json:"size,omitempty"`
+	Conn     int    `json:"conn,omitempty"`
+	SinkPort int    `json:"sink_port,omitempty"`
+}
+
+type FloodRuntime struct {
+}
+
+// FloodStat is reported by the flood tool of each role through the stat file
+type FloodStat struct {
+	Role       string `json:"role"`
+	Protocol   string `json:"protocol"`
+	Address    string `json:"address"`
+	StartTime  string `json:"start_time"`
+	UpdateAt   string `json:"update_at"`
+	Bytes      int64  `json:"bytes"`
+	Packets    int64  `json:"packets"`
+	Errors     int64  `json:"errors"`
+	Conns      int64  `json:"conns"`
+	RateBps    int64  `json:"rate_bps"`
+	AvgRateBps int64  `json:"avg_rate_bps"`
+}
+
+func (i *FloodInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *FloodInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *FloodInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Protocol == "" {
+		i.Args.Protocol = net.ProtocolTCP
+	}
+
+	if i.Args.Size == "" {
+		i.Args.Size = DefaultFloodSize
+	}
+
+	if i.Args.Conn == 0 {
+		i.Args.Conn = 1
+	}
+}
+
+func (i *FloodInjector) SetOption(cmd *cobra.Command) {
+	// i.BaseInjector.SetOption(cmd)
+	cmd.Flags().StringVar(&i.Args.DstIp, "dst-ip", "", "destination ip of the flood traffic, eg: 192.168.2.5")
+	cmd.Flags().IntVar(&i.Args.DstPort, "dst-port", 0, "destination port of the flood traffic, tcp destination must be listening, eg: the sink of another experiment")
+	cmd.Flags().StringVarP(&i.Args.Protocol, "protocol", "P", "",
+		fmt.Sprintf("flood protocol, support: %s、%s、%s、%s（default %s）",
+			net.ProtocolTCP, net.ProtocolUDP, net.ProtocolTCP6, net.ProtocolUDP6, net.ProtocolTCP))
+	cmd.Flags().StringVarP(&i.Args.Rate, "rate", "r", "", "total send rate of all connections, support unit: \"bit、kbit、mbit、gbit、tbit\"(default bit), empty means as fast as possible")
+	cmd.Flags().StringVarP(&i.Args.Size, "size", "s", "", fmt.Sprintf("size of each packet(udp) or write(tcp), support unit: B/KB（default %s）, udp max %dB", DefaultFloodSize, MaxUDPFloodSize))
+	cmd.Flags().IntVarP(&i.Args.Conn, "conn", "c", 0, fmt.Sprintf("connection count, range: 1-%d（default 1）", MaxFloodConn))
+	cmd.Flags().IntVar(&i.Args.SinkPort, "sink-port", 0, "if provided, run a local sink on the port to receive and discard flood traffic, can be used without dst-ip")
+}
+
+func (i *FloodInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if i.Args.Protocol != net.ProtocolTCP && i.Args.Protocol != net.ProtocolUDP && i.Args.Protocol != net.ProtocolTCP6 && i.Args.Protocol != net.ProtocolUDP6 {
+		return fmt.Errorf("\"protocol\" is not support %s", i.Args.Protocol)
+	}
+
+	if i.Args.DstIp == "" && i.Args.SinkPort == 0 {
+		return fmt.Errorf("must provide \"dst-ip\" or \"sink-port\"")
+	}
+
+	if i.Args.SinkPort < 0 || i.Args.SinkPort > 65535 {
+		return fmt.Errorf("\"sink-port\"[%d] is invalid", i.Args.SinkPort)
+	}
+
+	r := &guardrail.Resources{}
+	if i.Args.SinkPort > 0 {
+		r.Ports = strconv.Itoa(i.Args.SinkPort)
+	}
+
+	if i.Args.DstIp != "" {
+		ip := gonet.ParseIP(i.Args.DstIp)
+		if ip == nil {
+			return fmt.Errorf("\"dst-ip\"[%s] is not a valid ip", i.Args.DstIp)
+		}
+
+		if (ip.To4() == nil) != strings.HasSuffix(i.Args.Protocol, "6") {
+			return fmt.Errorf("\"dst-ip\"[%s] does not match protocol %s", i.Args.DstIp, i.Args.Protocol)
+		}
+
+		if i.Args.DstPort <= 0 || i.Args.DstPort > 65535 {
+			return fmt.Errorf("\"dst-port\"[%d] is invalid", i.Args.DstPort)
+		}
+
+		if i.Args.Rate != "" {
+			if _, err := utils.GetBitRate(i.Args.Rate); err != nil {
+				return fmt.Errorf("\"rate\"[%s] is invalid: %s", i.Args.Rate, err.Error())
+			}
+		}
+
+		size, err := utils.GetBytes(i.Args.Size)
+		if err != nil {
+			return fmt.Errorf("\"size\"[%s] is invalid: %s", i.Args.Size, err.Error())
+		}
+
+		maxSize := int64(MaxTCPFloodSize)
+		if i.Args.Protocol == net.ProtocolUDP || i.Args.Protocol == net.ProtocolUDP6 {
+			maxSize = MaxUDPFloodSize
+		}
+
+		if size <= 0 || size > maxSize {
+			return fmt.Errorf("\"size\"[%s] must be in range: 1-%dB", i.Args.Size, maxSize)
+		}
+
+		if i.Args.Conn <= 0 || i.Args.Conn > MaxFloodConn {
+			return fmt.Errorf("\"conn\"[%d] must be in range: 1-%d", i.Args.Conn, MaxFloodConn)
+		}
+
+		r.IPs = i.Args.DstIp
+		if r.Ports != "" {
+			r.Ports += ","
+		}
+		r.Ports += strconv.Itoa(i.Args.DstPort)
+	}
+
+	return i.CheckGuardrail(ctx, r)
+}
+
+func (i *FloodInjector) Inject(ctx context.Context) error {
+	var timeout int64
+	if i.Info.Timeout != "" {
+		timeout, _ = utils.GetTimeSecond(i.Info.Timeout)
+	}
+
+	// the sink is started first, so that the flood can be sent to the local sink
+	if i.Args.SinkPort > 0 {
+		cmd := fmt.Sprintf("%s %s %s %s %d %d %s", utils.GetToolPath(FloodKey), i.Info.Uid, FloodRoleSink,
+			i.Args.Protocol, i.Args.SinkPort, timeout, i.getStatFile(FloodRoleSink))
+		if err := cmdexec.WaitCommonWithNS(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, cmd, []string{namespace.NET, namespace.PID}); err != nil {
+			return fmt.Errorf("start sink error: %s", err.Error())
+		}
+	}
+
+	if i.Args.DstIp == "" {
+		return nil
+	}
+
+	var rate int64
+	if i.Args.Rate != "" {
+		rate, _ = utils.GetBitRate(i.Args.Rate)
+	}
+	size, _ := utils.GetBytes(i.Args.Size)
+
+	cmd := fmt.Sprintf("%s %s %s %s %s %d %d %d %d %s", utils.GetToolPath(FloodKey), i.Info.Uid, FloodRoleSend, i.Args.Protocol,
+		gonet.JoinHostPort(i.Args.DstIp, strconv.Itoa(i.Args.DstPort)), rate, size, i.Args.Conn, timeout, i.getStatFile(FloodRoleSend))
+	if err := cmdexec.WaitCommonWithNS(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, cmd, []string{namespace.NET, namespace.PID}); err != nil {
+		if err := i.Recover(ctx); err != nil {
+			log.GetLogger(ctx).Warnf("undo error: %s", err.Error())
+		}
+
+		return fmt.Errorf("start flood error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (i *FloodInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	if err := process.CheckExistAndKillByKey(ctx, fmt.Sprintf("%s %s", FloodKey, i.Info.Uid)); err != nil {
+		return err
+	}
+
+	for _, role := range []string{FloodRoleSend, FloodRoleSink} {
+		if err := os.Remove(i.getStatFile(role)); err != nil && !os.IsNotExist(err) {
+			log.GetLogger(ctx).Warnf("remove stat file error: %s", err.Error())
+		}
+	}
+
+	return nil
+}
+
+// Query return the achieved rate reported by the sender and the sink
+func (i *FloodInjector) Query(ctx context.Context) (string, error) {
+	detail := make(map[string]interface{})
+	for _, role := range []string{FloodRoleSend, FloodRoleSink} {
+		if (role == FloodRoleSend && i.Args.DstIp == "") || (role == FloodRoleSink && i.Args.SinkPort == 0) {
+			continue
+		}
+
+		data, err := os.ReadFile(i.getStatFile(role))
+		if err != nil {
+			if os.IsNotExist(err) {
+				detail[role] = "stat is not reported yet"
+			} else {
+				detail[role] = err.Error()
+			}
+			continue
+		}
+
+		var stat FloodStat
+		if err := json.Unmarshal(data, &stat); err != nil {
+			detail[role] = fmt.Sprintf("stat is invalid: %s", err.Error())
+			continue
+		}
+		detail[role] = stat
+	}
+
+	reByte, err := json.Marshal(detail)
+	if err != nil {
+		return "", fmt.Errorf("marshal detail error: %s", err.Error())
+	}
+
+	return string(reByte), nil
+}
+
+func (i *FloodInjector) getStatFile(role string) string {
+	return fmt.Sprintf(FloodStatFile, i.Info.Uid, role)
+}
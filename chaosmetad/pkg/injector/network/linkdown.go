@@ -0,0 +1,167 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package network
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/guardrail"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/net"
+)
+
+func init() {
+	injector.Register(TargetNetwork, FaultLinkDown, func() injector.IInjector { return &LinkDownInjector{} })
+}
+
+type LinkDownInjector struct {
+	injector.BaseInjector
+	Args    LinkDownArgs
+	Runtime LinkRuntime
+}
+
+type LinkDownArgs struct {
+	Interface string `json:"interface"`
+}
+
+type LinkRuntime struct {
+	// OriginalState is the state of interface before the first injection, which is restored on recovery
+	OriginalState string `json:"original_state,omitempty"`
+	// Routes and Routes6 are the routes on the interface before the first injection, kernel flushes them when the
+	// interface is set down, so they are added again on recovery
+	Routes  []string `json:"routes,omitempty"`
+	Routes6 []string `json:"routes6,omitempty"`
+}
+
+func (i *LinkDownInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *LinkDownInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *LinkDownInjector) SetOption(cmd *cobra.Command) {
+	// i.BaseInjector.SetOption(cmd)
+
+	cmd.Flags().StringVarP(&i.Args.Interface, "interface", "i", "", "network interface to take down. eg: eth0")
+}
+
+func (i *LinkDownInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	return validateLink(ctx, &i.BaseInjector, i.Args.Interface)
+}
+
+func (i *LinkDownInjector) Inject(ctx context.Context) error {
+	return injectLinkDown(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface, &i.Runtime)
+}
+
+func (i *LinkDownInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return recoverLink(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface, &i.Runtime)
+}
+
+// validateLink refuse the interface carrying the default route of host, because the host will lose its connection
+func validateLink(ctx context.Context, i *injector.BaseInjector, netInterface string) error {
+	cr, cId := i.Info.ContainerRuntime, i.Info.ContainerId
+	if netInterface == "" {
+		return fmt.Errorf("\"interface\" is empty")
+	}
+
+	if !cmdexec.SupportCmd("ip") {
+		return fmt.Errorf("not support command \"ip\"")
+	}
+
+	if _, err := net.GetLinkState(ctx, cr, cId, netInterface); err != nil {
+		return fmt.Errorf("\"interface\"[%s] is invalid: %s", netInterface, err.Error())
+	}
+
+	isHost, err := net.IsHostNetNs(ctx, cr, cId)
+	if err != nil {
+		return fmt.Errorf("check net namespace error: %s", err.Error())
+	}
+
+	if isHost {
+		routeInterfaces, err := net.GetDefaultRouteInterfaces(ctx, cr, cId)
+		if err != nil {
+			return err
+		}
+
+		if utils.StrListContain(routeInterfaces, netInterface) {
+			return fmt.Errorf("interface[%s] carries the default route of host, taking it down will disconnect the host", netInterface)
+		}
+	}
+
+	r, err := guardrail.NetworkResources(ctx, cr, cId, netInterface, net.ModeNormal, "", "", "", "")
+	if err != nil {
+		return fmt.Errorf("get resources of network error: %s", err.Error())
+	}
+
+	return i.CheckGuardrail(ctx, r)
+}
+
+func injectLinkDown(ctx context.Context, cr, cId, netInterface string, r *LinkRuntime) error {
+	if r.OriginalState == "" {
+		state, err := net.GetLinkState(ctx, cr, cId, netInterface)
+		if err != nil {
+			return err
+		}
+
+		if r.Routes, err = net.GetLinkRoutes(ctx, cr, cId, netInterface, false); err != nil {
+			return err
+		}
+
+		if r.Routes6, err = net.GetLinkRoutes(ctx, cr, cId, netInterface, true); err != nil {
+			return err
+		}
+		r.OriginalState = state
+	}
+
+	if err := net.SetLinkState(ctx, cr, cId, netInterface, net.LinkDown); err != nil {
+		return fmt.Errorf("set interface[%s] down error: %s", netInterface, err.Error())
+	}
+
+	return nil
+}
+
+func recoverLink(ctx context.Context, cr, cId, netInterface string, r *LinkRuntime) error {
+	if r.OriginalState == net.LinkDown {
+		return nil
+	}
+
+	if err := net.SetLinkState(ctx, cr, cId, netInterface, net.LinkUp); err != nil {
+		return fmt.Errorf("set interface[%s] up error: %s", netInterface, err.Error())
+	}
+
+	if err := net.AddLinkRoutes(ctx, cr, cId, netInterface, false, r.Routes); err != nil {
+		return fmt.Errorf("restore routes of interface[%s] error: %s", netInterface, err.Error())
+	}
+
+	if err := net.AddLinkRoutes(ctx, cr, cId, netInterface, true, r.Routes6); err != nil {
+		return fmt.Errorf("restore ipv6 routes of interface[%s] error: %s", netInterface, err.Error())
+	}
+
+	return nil
+}
@@ -0,0 +1,104 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package network
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+)
+
+func init() {
+	injector.Register(TargetNetwork, FaultLinkFlap, func() injector.IInjector { return &LinkFlapInjector{} })
+}
+
+// LinkFlapInjector takes the interface down and up repeatedly, the cycle is driven by the intermittent mode
+type LinkFlapInjector struct {
+	injector.BaseInjector
+	Args    LinkFlapArgs
+	Runtime LinkRuntime
+}
+
+type LinkFlapArgs struct {
+	Interface string `json:"interface"`
+	Down      string `json:"down"`
+	Up        string `json:"up"`
+}
+
+func (i *LinkFlapInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *LinkFlapInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *LinkFlapInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Down == "" {
+		i.Args.Down = DefaultFlapDown
+	}
+
+	if i.Args.Up == "" {
+		i.Args.Up = DefaultFlapUp
+	}
+
+	if i.Info.Intermittent.On == "" && i.Info.Intermittent.Off == "" {
+		i.Info.Intermittent.On, i.Info.Intermittent.Off = i.Args.Down, i.Args.Up
+	}
+}
+
+func (i *LinkFlapInjector) SetOption(cmd *cobra.Command) {
+	// i.BaseInjector.SetOption(cmd)
+
+	cmd.Flags().StringVarP(&i.Args.Interface, "interface", "i", "", "network interface to flap. eg: eth0")
+	cmd.Flags().StringVar(&i.Args.Down, "down", "", fmt.Sprintf("duration of interface down in each cycle, support unit: \"s、m、h\"(default s)（default %s）", DefaultFlapDown))
+	cmd.Flags().StringVar(&i.Args.Up, "up", "", fmt.Sprintf("duration of interface up in each cycle, support unit: \"s、m、h\"(default s)（default %s）", DefaultFlapUp))
+}
+
+func (i *LinkFlapInjector) Validator(ctx context.Context) error {
+	for name, value := range map[string]string{"down": i.Args.Down, "up": i.Args.Up} {
+		second, err := utils.GetTimeSecond(value)
+		if err != nil || second <= 0 {
+			return fmt.Errorf("\"%s\" is not valid, must be a positive duration: %s", name, value)
+		}
+	}
+
+	if i.Info.Intermittent.On != i.Args.Down || i.Info.Intermittent.Off != i.Args.Up {
+		return fmt.Errorf("the cycle of %s is set by \"down\" and \"up\", not \"on\" and \"off\"", FaultLinkFlap)
+	}
+
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	return validateLink(ctx, &i.BaseInjector, i.Args.Interface)
+}
+
+func (i *LinkFlapInjector) Inject(ctx context.Context) error {
+	return injectLinkDown(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface, &i.Runtime)
+}
+
+func (i *LinkFlapInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	return recoverLink(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.Interface, &i.Runtime)
+}
@@ -0,0 +1,834 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package network
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/net"
+	"golang.org/x/sys/unix"
+	gonet "net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// The harness runs the network injectors in throwaway network namespaces connected by a veth pair:
+// the injector attacks the veth in namespace "a", and the echo and sink servers listen in namespace "b".
+// It must be run as root: go test ./pkg/injector/network/
+
+const (
+	testIpA      = "10.201.0.1"
+	testIpB      = "10.201.0.2"
+	testEchoPort = 7007
+	testSinkPort = 7008
+	packetLen    = 64
+)
+
+var (
+	netSeq       int32
+	netemOnce    sync.Once
+	netemSupport bool
+)
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "chaosmetad-netns-test")
+	if err != nil {
+		fmt.Printf("create temp dir error: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	storage.DBPath = filepath.Join(dir, "chaosmetad.dat")
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+type testNet struct {
+	nsA, nsB string
+	ifA, ifB string
+	sinkRecv int64
+}
+
+func requireRoot(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("network namespace harness must run as root")
+	}
+
+	for _, c := range []string{"ip", "tc"} {
+		if !cmdexec.SupportCmd(c) {
+			t.Skipf("command \"%s\" is not found", c)
+		}
+	}
+}
+
+func requireNetem(t *testing.T) {
+	netemOnce.Do(func() {
+		ns := fmt.Sprintf("cmt%d-netem", os.Getpid())
+		netemSupport = runCmd(fmt.Sprintf("ip netns add %s && ip netns exec %s tc qdisc add dev lo root netem delay 1ms", ns, ns)) == nil
+		_ = runCmd(fmt.Sprintf("ip netns del %s", ns))
+	})
+
+	if !netemSupport {
+		t.Skip("qdisc \"netem\" is not supported by kernel")
+	}
+}
+
+func runCmd(cmd string) error {
+	if out, err := exec.Command("/bin/bash", "-c", cmd).CombinedOutput(); err != nil {
+		return fmt.Errorf("run [%s] error: %s, output: %s", cmd, err.Error(), string(out))
+	}
+
+	return nil
+}
+
+func cmdOutput(t *testing.T, cmd string) string {
+	out, err := exec.Command("/bin/bash", "-c", cmd).CombinedOutput()
+	if err != nil {
+		t.Fatalf("run [%s] error: %v, output: %s", cmd, err, string(out))
+	}
+
+	return string(out)
+}
+
+// newTestNet creates namespace "a" and "b" connected by a veth pair, the echo and sink servers are started in "b"
+func newTestNet(t *testing.T) *testNet {
+	requireRoot(t)
+	id := fmt.Sprintf("cmt%d-%d", os.Getpid()%100000, atomic.AddInt32(&netSeq, 1))
+	n := &testNet{nsA: id + "-a", nsB: id + "-b", ifA: id + "a", ifB: id + "b"}
+	t.Cleanup(func() {
+		_ = runCmd(fmt.Sprintf("ip netns del %s", n.nsA))
+		_ = runCmd(fmt.Sprintf("ip netns del %s", n.nsB))
+	})
+
+	for _, cmd := range []string{
+		fmt.Sprintf("ip netns add %s", n.nsA),
+		fmt.Sprintf("ip netns add %s", n.nsB),
+		fmt.Sprintf("ip link add %s type veth peer name %s", n.ifA, n.ifB),
+		fmt.Sprintf("ip link set %s netns %s", n.ifA, n.nsA),
+		fmt.Sprintf("ip link set %s netns %s", n.ifB, n.nsB),
+		fmt.Sprintf("ip -n %s addr add %s/24 dev %s", n.nsA, testIpA, n.ifA),
+		fmt.Sprintf("ip -n %s addr add %s/24 dev %s", n.nsB, testIpB, n.ifB),
+		fmt.Sprintf("ip -n %s link set %s up && ip -n %s link set lo up", n.nsA, n.ifA, n.nsA),
+		fmt.Sprintf("ip -n %s link set %s up && ip -n %s link set lo up", n.nsB, n.ifB, n.nsB),
+	} {
+		if err := runCmd(cmd); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	n.startServers(t)
+	return n
+}
+
+// runInNS runs f in a new thread which joins the network namespace, the processes started by f inherit the namespace.
+// The thread is never unlocked, so it is destroyed after f returns
+func runInNS(ns string, f func() error) error {
+	errCh := make(chan error, 1)
+	go func() {
+		runtime.LockOSThread()
+		fd, err := os.Open(filepath.Join("/var/run/netns", ns))
+		if err != nil {
+			errCh <- fmt.Errorf("open namespace[%s] error: %s", ns, err.Error())
+			return
+		}
+		defer fd.Close()
+
+		if err := unix.Setns(int(fd.Fd()), unix.CLONE_NEWNET); err != nil {
+			errCh <- fmt.Errorf("join namespace[%s] error: %s", ns, err.Error())
+			return
+		}
+
+		errCh <- f()
+	}()
+
+	return <-errCh
+}
+
+func (n *testNet) startServers(t *testing.T) {
+	var (
+		echo *gonet.UDPConn
+		sink gonet.Listener
+	)
+	if err := runInNS(n.nsB, func() (err error) {
+		if echo, err = gonet.ListenUDP("udp4", &gonet.UDPAddr{IP: gonet.ParseIP(testIpB), Port: testEchoPort}); err != nil {
+			return err
+		}
+
+		sink, err = gonet.Listen("tcp4", gonet.JoinHostPort(testIpB, strconv.Itoa(testSinkPort)))
+		return err
+	}); err != nil {
+		t.Fatalf("start servers error: %v", err)
+	}
+
+	t.Cleanup(func() {
+		_ = echo.Close()
+		_ = sink.Close()
+	})
+
+	go func() {
+		buf := make([]byte, packetLen)
+		for {
+			l, addr, err := echo.ReadFromUDP(buf)
+			if err != nil {
+				return
+			}
+			_, _ = echo.WriteToUDP(buf[:l], addr)
+		}
+	}()
+
+	go func() {
+		for {
+			conn, err := sink.Accept()
+			if err != nil {
+				return
+			}
+
+			go func() {
+				defer conn.Close()
+				buf := make([]byte, 32*1024)
+				for {
+					l, err := conn.Read(buf)
+					atomic.AddInt64(&n.sinkRecv, int64(l))
+					if err != nil {
+						return
+					}
+				}
+			}()
+		}
+	}()
+}
+
+type udpStats struct {
+	sent       int
+	received   int
+	duplicated int
+	reordered  int
+	corrupted  int
+	rtt        time.Duration
+}
+
+func (s *udpStats) lossRate() float64 {
+	return 1 - float64(s.received-s.duplicated-s.corrupted)/float64(s.sent)
+}
+
+// measureUDP sends packets with sequence to the echo server from namespace "a" and analyzes the echoes
+func (n *testNet) measureUDP(t *testing.T, count int, interval time.Duration) *udpStats {
+	var stats = &udpStats{sent: count}
+	if err := runInNS(n.nsA, func() error {
+		conn, err := gonet.DialUDP("udp4", nil, &gonet.UDPAddr{IP: gonet.ParseIP(testIpB), Port: testEchoPort})
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		var (
+			sendTime = make([]time.Time, count)
+			seen     = make(map[uint32]bool)
+			rtts     []time.Duration
+			maxSeq   = -1
+			done     = make(chan struct{})
+		)
+
+		go func() {
+			defer close(done)
+			buf := make([]byte, packetLen)
+			for {
+				l, err := conn.Read(buf)
+				if err != nil {
+					return
+				}
+
+				seq := binary.BigEndian.Uint32(buf[:4])
+				if l != packetLen || int(seq) >= count || !validPayload(buf) {
+					stats.corrupted++
+					continue
+				}
+
+				stats.received++
+				if seen[seq] {
+					stats.duplicated++
+					continue
+				}
+				seen[seq] = true
+				rtts = append(rtts, time.Since(sendTime[seq]))
+
+				if int(seq) < maxSeq {
+					stats.reordered++
+				} else {
+					maxSeq = int(seq)
+				}
+			}
+		}()
+
+		packet := make([]byte, packetLen)
+		for seq := 0; seq < count; seq++ {
+			binary.BigEndian.PutUint32(packet[:4], uint32(seq))
+			fillPayload(packet)
+			sendTime[seq] = time.Now()
+			if _, err := conn.Write(packet); err != nil {
+				return err
+			}
+			time.Sleep(interval)
+		}
+
+		time.Sleep(time.Second)
+		_ = conn.SetReadDeadline(time.Now())
+		<-done
+
+		if len(rtts) > 0 {
+			sort.Slice(rtts, func(i, j int) bool { return rtts[i] < rtts[j] })
+			stats.rtt = rtts[len(rtts)/2]
+		}
+
+		return nil
+	}); err != nil {
+		t.Fatalf("measure udp error: %v", err)
+	}
+
+	return stats
+}
+
+func fillPayload(packet []byte) {
+	for i := 4; i < len(packet); i++ {
+		packet[i] = byte(i) ^ packet[i%4]
+	}
+}
+
+func validPayload(packet []byte) bool {
+	for i := 4; i < len(packet); i++ {
+		if packet[i] != byte(i)^packet[i%4] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// measureThroughput sends data to the sink server from namespace "a", and returns the bits per second received by the sink
+func (n *testNet) measureThroughput(t *testing.T, duration time.Duration) float64 {
+	var rate float64
+	if err := runInNS(n.nsA, func() error {
+		conn, err := gonet.Dial("tcp4", gonet.JoinHostPort(testIpB, strconv.Itoa(testSinkPort)))
+		if err != nil {
+			return err
+		}
+		defer conn.Close()
+
+		start, startRecv := time.Now(), atomic.LoadInt64(&n.sinkRecv)
+		buf := make([]byte, 32*1024)
+		_ = conn.SetWriteDeadline(start.Add(duration))
+		for {
+			if _, err := conn.Write(buf); err != nil {
+				break
+			}
+		}
+
+		rate = float64(atomic.LoadInt64(&n.sinkRecv)-startRecv) * 8 / time.Since(start).Seconds()
+		return nil
+	}); err != nil {
+		t.Fatalf("measure throughput error: %v", err)
+	}
+
+	return rate
+}
+
+// canListen checks if the tcp port is available in namespace "a"
+func (n *testNet) canListen(t *testing.T, port int) bool {
+	var listenErr error
+	if err := runInNS(n.nsA, func() error {
+		l, err := gonet.Listen("tcp4", gonet.JoinHostPort("", strconv.Itoa(port)))
+		if err != nil {
+			listenErr = err
+			return nil
+		}
+		return l.Close()
+	}); err != nil {
+		t.Fatalf("listen error: %v", err)
+	}
+
+	return listenErr == nil
+}
+
+// inject creates the experiment in namespace "a" through the same process as command line, and returns the uid
+func (n *testNet) inject(t *testing.T, fault, args string) string {
+	uid, err := n.tryInject(t, fault, args)
+	if err != nil {
+		t.Fatalf("ProcessInject() error = %v", err)
+	}
+
+	return uid
+}
+
+func (n *testNet) tryInject(t *testing.T, fault, args string) (string, error) {
+	i, err := injector.NewInjector(TargetNetwork, fault)
+	if err != nil {
+		t.Fatalf("NewInjector() error = %v", err)
+	}
+
+	if err := i.LoadInjector(&storage.Experiment{Target: TargetNetwork, Fault: fault, Args: args, Runtime: "{}"}, i.GetArgs(), i.GetRuntime()); err != nil {
+		t.Fatalf("LoadInjector() error = %v", err)
+	}
+
+	return i.GetInfo().Uid, runInNS(n.nsA, func() error {
+		if code, msg := injector.ProcessInject(context.Background(), i); code != errutil.NoErr {
+			return fmt.Errorf("code: %d, msg: %s", code, msg)
+		}
+		return nil
+	})
+}
+
+// recover recovers the experiment in namespace "a" and asserts that all qdiscs and filters are removed
+func (n *testNet) recover(t *testing.T, uid string) {
+	if err := runInNS(n.nsA, func() error {
+		if code, msg := injector.ProcessRecover(context.Background(), uid); code != errutil.NoErr {
+			return fmt.Errorf("code: %d, msg: %s", code, msg)
+		}
+		return nil
+	}); err != nil {
+		t.Fatalf("ProcessRecover() error = %v", err)
+	}
+
+	n.assertNoTcRule(t)
+
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		t.Fatalf("GetExperimentStore() error = %v", err)
+	}
+
+	if exp, err := db.GetByUid(uid); err != nil || exp.Status != utils.StatusDestroyed {
+		t.Errorf("experiment after recover = %+v, %v, want status %s", exp, err, utils.StatusDestroyed)
+	}
+}
+
+func (n *testNet) assertNoTcRule(t *testing.T) {
+	qdisc := cmdOutput(t, fmt.Sprintf("ip netns exec %s tc qdisc show dev %s", n.nsA, n.ifA))
+	for _, kind := range []string{"netem", "prio", "htb"} {
+		if strings.Contains(qdisc, kind) {
+			t.Errorf("qdisc is not cleaned up: %s", qdisc)
+		}
+	}
+
+	if filter := cmdOutput(t, fmt.Sprintf("ip netns exec %s tc filter show dev %s", n.nsA, n.ifA)); strings.TrimSpace(filter) != "" {
+		t.Errorf("filter is not cleaned up: %s", filter)
+	}
+}
+
+func TestNetemInjector(t *testing.T) {
+	tests := []struct {
+		name  string
+		fault string
+		args  string
+		check func(s *udpStats) error
+	}{
+		{
+			name:  "delay",
+			fault: FaultDelay,
+			args:  `{"interface":"%s","latency":"100ms"}`,
+			check: func(s *udpStats) error {
+				if s.rtt < 90*time.Millisecond {
+					return fmt.Errorf("rtt = %s, want >= 90ms", s.rtt)
+				}
+				return nil
+			},
+		},
+		{
+			name:  "delay with port filter",
+			fault: FaultDelay,
+			args:  fmt.Sprintf(`{"interface":"%%s","latency":"100ms","dst_port":"%d"}`, testEchoPort),
+			check: func(s *udpStats) error {
+				if s.rtt < 90*time.Millisecond {
+					return fmt.Errorf("rtt = %s, want >= 90ms", s.rtt)
+				}
+				return nil
+			},
+		},
+		{
+			name:  "delay with unmatched port filter",
+			fault: FaultDelay,
+			args:  fmt.Sprintf(`{"interface":"%%s","latency":"100ms","dst_port":"%d"}`, testEchoPort+100),
+			check: func(s *udpStats) error {
+				if s.rtt >= 50*time.Millisecond {
+					return fmt.Errorf("rtt = %s, want < 50ms", s.rtt)
+				}
+				return nil
+			},
+		},
+		{
+			name:  "loss",
+			fault: FaultLoss,
+			args:  `{"interface":"%s","percent":50}`,
+			check: func(s *udpStats) error {
+				if r := s.lossRate(); r < 0.3 || r > 0.7 {
+					return fmt.Errorf("loss rate = %.2f, want in [0.3, 0.7]", r)
+				}
+				return nil
+			},
+		},
+		{
+			name:  "corrupt",
+			fault: FaultCorrupt,
+			args:  `{"interface":"%s","percent":50}`,
+			check: func(s *udpStats) error {
+				if r := s.lossRate() + float64(s.corrupted)/float64(s.sent); r < 0.2 {
+					return fmt.Errorf("corrupted or dropped rate = %.2f, want >= 0.2", r)
+				}
+				return nil
+			},
+		},
+		{
+			name:  "duplicate",
+			fault: FaultDuplicate,
+			args:  `{"interface":"%s","percent":50}`,
+			check: func(s *udpStats) error {
+				if r := float64(s.duplicated) / float64(s.sent); r < 0.2 {
+					return fmt.Errorf("duplicated rate = %.2f, want >= 0.2", r)
+				}
+				return nil
+			},
+		},
+		{
+			name:  "reorder",
+			fault: FaultReorder,
+			args:  `{"interface":"%s","gap":5,"latency":"50ms"}`,
+			check: func(s *udpStats) error {
+				if s.reordered == 0 {
+					return fmt.Errorf("reordered = 0, want > 0")
+				}
+				return nil
+			},
+		},
+	}
+
+	n := newTestNet(t)
+	requireNetem(t)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uid := n.inject(t, tt.fault, fmt.Sprintf(tt.args, n.ifA))
+			s := n.measureUDP(t, 200, 5*time.Millisecond)
+			n.recover(t, uid)
+			if err := tt.check(s); err != nil {
+				t.Errorf("during injection: %v, stats: %+v", err, *s)
+			}
+
+			s = n.measureUDP(t, 50, 5*time.Millisecond)
+			if s.lossRate() > 0 || s.duplicated > 0 || s.rtt >= 50*time.Millisecond {
+				t.Errorf("after recover: stats = %+v, want no fault", *s)
+			}
+		})
+	}
+}
+
+func TestLimitInjector(t *testing.T) {
+	n := newTestNet(t)
+	before := n.measureThroughput(t, time.Second)
+
+	// in normal mode the limit class only takes effect on the filtered flow
+	uid := n.inject(t, FaultLimit, fmt.Sprintf(`{"interface":"%s","rate":"1mbit","dst_ip":"%s"}`, n.ifA, testIpB))
+	during := n.measureThroughput(t, 3*time.Second)
+	n.recover(t, uid)
+	after := n.measureThroughput(t, time.Second)
+
+	if during > 5e6 {
+		t.Errorf("throughput during injection = %.0fbit/s, want < 5mbit/s", during)
+	}
+
+	if after < 10*during || after < before/10 {
+		t.Errorf("throughput after recover = %.0fbit/s, before = %.0fbit/s, during = %.0fbit/s", after, before, during)
+	}
+}
+
+func TestOccupyInjector(t *testing.T) {
+	n := newTestNet(t)
+	requireTool(t, OccupyKey)
+	const port = 7009
+
+	if !n.canListen(t, port) {
+		t.Fatalf("port[%d] is not available before injection", port)
+	}
+
+	uid := n.inject(t, FaultOccupy, fmt.Sprintf(`{"port":%d,"protocol":"tcp"}`, port))
+	occupied := !n.canListen(t, port)
+	n.recover(t, uid)
+
+	if !occupied {
+		t.Errorf("port[%d] is available during injection", port)
+	}
+
+	if !n.canListen(t, port) {
+		t.Errorf("port[%d] is not available after recover", port)
+	}
+}
+
+// requireTool builds the tool to the tools directory of the test binary
+func requireTool(t *testing.T, key string) {
+	tool := utils.GetToolPath(key)
+	if _, err := os.Stat(tool); err == nil {
+		return
+	}
+
+	src, _ := filepath.Abs(filepath.Join("..", "..", "..", "tools", key+".go"))
+	cmd := exec.Command("go", "build", "-o", tool, src)
+	cmd.Env = append(os.Environ(), "CGO_ENABLED=0")
+	if out, err := cmd.CombinedOutput(); err != nil {
+		t.Skipf("build tool[%s] error: %v, output: %s", key, err, string(out))
+	}
+}
+
+// linkState returns the state of the veth in namespace "a"
+func (n *testNet) linkState(t *testing.T) string {
+	var state string
+	if err := runInNS(n.nsA, func() (err error) {
+		state, err = net.GetLinkState(context.Background(), "", "", n.ifA)
+		return err
+	}); err != nil {
+		t.Fatalf("get link state error: %v", err)
+	}
+
+	return state
+}
+
+// reachable checks if the echo server answers namespace "a"
+func (n *testNet) reachable(t *testing.T) bool {
+	var ok bool
+	if err := runInNS(n.nsA, func() error {
+		conn, err := gonet.DialUDP("udp4", nil, &gonet.UDPAddr{IP: gonet.ParseIP(testIpB), Port: testEchoPort})
+		if err != nil {
+			return nil
+		}
+		defer conn.Close()
+
+		buf := make([]byte, packetLen)
+		_ = conn.SetDeadline(time.Now().Add(time.Second))
+		if _, err := conn.Write(buf); err != nil {
+			return nil
+		}
+
+		_, err = conn.Read(buf)
+		ok = err == nil
+		return nil
+	}); err != nil {
+		t.Fatalf("check reachable error: %v", err)
+	}
+
+	return ok
+}
+
+// routes returns the ipv4 routes on the veth in namespace "a"
+func (n *testNet) routes(t *testing.T) string {
+	return strings.TrimSpace(cmdOutput(t, fmt.Sprintf("ip -n %s -4 route show dev %s", n.nsA, n.ifA)))
+}
+
+// runFlap drives the cycles of the intermittent experiment in namespace "a" like the background process, and returns
+// the states of the veth observed in duration
+func (n *testNet) runFlap(t *testing.T, uid string, duration time.Duration) []string {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- runInNS(n.nsA, func() error {
+			return injector.RunIntermittent(ctx, uid)
+		})
+	}()
+
+	var states []string
+	for deadline := time.Now().Add(duration); time.Now().Before(deadline); time.Sleep(100 * time.Millisecond) {
+		if state := n.linkState(t); len(states) == 0 || states[len(states)-1] != state {
+			states = append(states, state)
+		}
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("RunIntermittent() error = %v", err)
+	}
+
+	return states
+}
+
+func TestLinkInjector(t *testing.T) {
+	tests := []struct {
+		name  string
+		fault string
+		args  string
+		// flap is the duration to drive the cycles, the states must go through down, up and down
+		flap time.Duration
+	}{
+		{"linkdown", FaultLinkDown, `{"interface":"%s"}`, 0},
+		{"linkflap", FaultLinkFlap, `{"interface":"%s","down":"2s","up":"1s"}`, 4 * time.Second},
+	}
+
+	n := newTestNet(t)
+	// a route via gateway is flushed by kernel when the link is down, and must be restored by recovery
+	if err := runCmd(fmt.Sprintf("ip -n %s route add 10.202.0.0/16 via %s dev %s", n.nsA, testIpB, n.ifA)); err != nil {
+		t.Fatal(err)
+	}
+	before := n.routes(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uid := n.inject(t, tt.fault, fmt.Sprintf(tt.args, n.ifA))
+			during, reachable := n.linkState(t), n.reachable(t)
+			var states []string
+			if tt.flap > 0 {
+				states = n.runFlap(t, uid, tt.flap)
+			}
+			n.recover(t, uid)
+
+			if during != net.LinkDown || reachable {
+				t.Errorf("during injection: state = %s, reachable = %t, want %s and unreachable", during, reachable, net.LinkDown)
+			}
+
+			if tt.flap > 0 && !strings.Contains(strings.Join(states, ","), "down,up,down") {
+				t.Errorf("states during flap = %v, want a full cycle of down, up and down", states)
+			}
+
+			if after := n.linkState(t); after != net.LinkUp || !n.reachable(t) {
+				t.Errorf("after recover: state = %s, want %s and reachable", after, net.LinkUp)
+			}
+
+			if after := n.routes(t); after != before {
+				t.Errorf("after recover: routes = %q, want %q", after, before)
+			}
+		})
+	}
+}
+
+// TestLinkInjector_defaultRoute calls the injection of link directly, because the full process refuses the interface
+// carrying the default route of host, which namespace "a" is regarded as
+func TestLinkInjector_defaultRoute(t *testing.T) {
+	n := newTestNet(t)
+	if err := runCmd(fmt.Sprintf("ip -n %s route add default via %s dev %s", n.nsA, testIpB, n.ifA)); err != nil {
+		t.Fatal(err)
+	}
+	before := n.routes(t)
+
+	var r LinkRuntime
+	if err := runInNS(n.nsA, func() error {
+		return injectLinkDown(context.Background(), "", "", n.ifA, &r)
+	}); err != nil {
+		t.Fatalf("injectLinkDown() error = %v", err)
+	}
+
+	if during := n.routes(t); strings.Contains(during, "default") {
+		t.Errorf("during injection: routes = %q, want the default route flushed", during)
+	}
+
+	if err := runInNS(n.nsA, func() error {
+		return recoverLink(context.Background(), "", "", n.ifA, &r)
+	}); err != nil {
+		t.Fatalf("recoverLink() error = %v", err)
+	}
+
+	if after := n.routes(t); after != before || !strings.Contains(after, "default via "+testIpB) {
+		t.Errorf("after recover: routes = %q, want %q", after, before)
+	}
+}
+
+func TestLinkInjector_protection(t *testing.T) {
+	n := newTestNet(t)
+	if err := runCmd(fmt.Sprintf("ip -n %s route add default via %s dev %s", n.nsA, testIpB, n.ifA)); err != nil {
+		t.Fatal(err)
+	}
+
+	// namespace "a" is regarded as host because no container is provided
+	if _, err := n.tryInject(t, FaultLinkDown, fmt.Sprintf(`{"interface":"%s"}`, n.ifA)); err == nil || !strings.Contains(err.Error(), "default route") {
+		t.Errorf("ProcessInject() error = %v, want default route protection", err)
+	}
+
+	if state := n.linkState(t); state != net.LinkUp {
+		t.Errorf("state = %s, want %s", state, net.LinkUp)
+	}
+}
+
+// queryFlood returns the stat reported by the flood tool of each role
+func queryFlood(t *testing.T, uid string) map[string]FloodStat {
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		t.Fatalf("GetExperimentStore() error = %v", err)
+	}
+
+	exp, err := db.GetByUid(uid)
+	if err != nil {
+		t.Fatalf("GetByUid() error = %v", err)
+	}
+
+	detail, err := injector.QueryDetail(context.Background(), exp)
+	if err != nil {
+		t.Fatalf("QueryDetail() error = %v", err)
+	}
+
+	var re map[string]FloodStat
+	if err := json.Unmarshal([]byte(detail), &re); err != nil {
+		t.Fatalf("unmarshal detail[%s] error: %v", detail, err)
+	}
+
+	return re
+}
+
+func TestFloodInjector(t *testing.T) {
+	tests := []struct {
+		name     string
+		protocol string
+	}{
+		{"tcp", net.ProtocolTCP},
+		{"udp", net.ProtocolUDP},
+	}
+
+	n := newTestNet(t)
+	requireTool(t, FloodKey)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// the flood is sent to the sink server in namespace "b" and the local sink in namespace "a"
+			remote := n.inject(t, FaultFlood, fmt.Sprintf(`{"dst_ip":"%s","dst_port":%d,"protocol":"%s","rate":"8mbit","conn":2}`, testIpB, testSinkPort, tt.protocol))
+			local := n.inject(t, FaultFlood, fmt.Sprintf(`{"dst_ip":"%s","dst_port":7010,"protocol":"%s","rate":"4mbit","sink_port":7010}`, testIpA, tt.protocol))
+			time.Sleep(3 * time.Second)
+			remoteStat, localStat := queryFlood(t, remote), queryFlood(t, local)
+			n.recover(t, remote)
+			n.recover(t, local)
+
+			if s := remoteStat[FloodRoleSend]; s.RateBps < 6e6 || s.RateBps > 10e6 || s.Conns != 2 {
+				t.Errorf("remote send stat = %+v, want rate about 8mbit/s and 2 conns", s)
+			}
+
+			if s := localStat[FloodRoleSink]; s.RateBps < 3e6 || s.RateBps > 5e6 {
+				t.Errorf("local sink stat = %+v, want rate about 4mbit/s", s)
+			}
+
+			if tt.protocol == net.ProtocolTCP && atomic.LoadInt64(&n.sinkRecv) < 2e6 {
+				t.Errorf("sink server received %d bytes, want > 2MB", atomic.LoadInt64(&n.sinkRecv))
+			}
+
+			if _, err := os.Stat(fmt.Sprintf(FloodStatFile, remote, FloodRoleSend)); !os.IsNotExist(err) {
+				t.Errorf("stat file is not removed after recover: %v", err)
+			}
+		})
+	}
+}
@@ -20,12 +20,14 @@ import (
 	"context"
 	"fmt"
 	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/guardrail"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/net"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+	"strconv"
 )
 
 func init() {
@@ -84,7 +86,11 @@ func (i *OccupyInjector) Validator(ctx context.Context) error {
 		return fmt.Errorf("\"protocol\" is not support %s", i.Args.Protocol)
 	}
 
-	return i.BaseInjector.Validator(ctx)
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	return i.CheckGuardrail(ctx, &guardrail.Resources{Ports: strconv.Itoa(i.Args.Port)})
 }
 
 func (i *OccupyInjector) Inject(ctx context.Context) error {
@@ -0,0 +1,196 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package plugin
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+)
+
+// PluginInjector forwards the calls of injector to the plugin which provides the fault
+type PluginInjector struct {
+	injector.BaseInjector
+	Args    map[string]interface{}
+	Runtime map[string]interface{}
+
+	path  string
+	fault FaultDescriptor
+	cmd   *cobra.Command
+}
+
+func (i *PluginInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *PluginInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *PluginInjector) SetOption(cmd *cobra.Command) {
+	i.cmd = cmd
+	if i.fault.Description != "" {
+		cmd.Short = i.fault.Description
+	}
+
+	for _, arg := range i.fault.Args {
+		usage := arg.Description
+		if arg.Required {
+			usage += "（required）"
+		}
+		if arg.Default != nil {
+			usage += fmt.Sprintf("（default %v）", arg.Default)
+		}
+
+		switch arg.Type {
+		case ArgTypeString:
+			cmd.Flags().String(arg.Name, "", usage)
+		case ArgTypeInt:
+			cmd.Flags().Int(arg.Name, 0, usage)
+		case ArgTypeFloat:
+			cmd.Flags().Float64(arg.Name, 0, usage)
+		case ArgTypeBool:
+			cmd.Flags().Bool(arg.Name, false, usage)
+		}
+	}
+}
+
+func (i *PluginInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args == nil {
+		i.Args = make(map[string]interface{})
+	}
+
+	if i.Runtime == nil {
+		i.Runtime = make(map[string]interface{})
+	}
+
+	for _, arg := range i.fault.Args {
+		if i.cmd != nil && i.cmd.Flags().Changed(arg.Name) {
+			var value interface{}
+			switch arg.Type {
+			case ArgTypeString:
+				value, _ = i.cmd.Flags().GetString(arg.Name)
+			case ArgTypeInt:
+				value, _ = i.cmd.Flags().GetInt(arg.Name)
+			case ArgTypeFloat:
+				value, _ = i.cmd.Flags().GetFloat64(arg.Name)
+			case ArgTypeBool:
+				value, _ = i.cmd.Flags().GetBool(arg.Name)
+			}
+			i.Args[arg.Name] = value
+		}
+
+		if _, ok := i.Args[arg.Name]; !ok && arg.Default != nil {
+			i.Args[arg.Name] = arg.Default
+		}
+	}
+}
+
+func (i *PluginInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	var declared = make(map[string]bool)
+	for _, arg := range i.fault.Args {
+		declared[arg.Name] = true
+		value, ok := i.Args[arg.Name]
+		if !ok {
+			if arg.Required {
+				return fmt.Errorf("\"%s\" is required", arg.Name)
+			}
+			continue
+		}
+
+		converted, err := arg.Convert(value)
+		if err != nil {
+			return err
+		}
+		i.Args[arg.Name] = converted
+	}
+
+	for name := range i.Args {
+		if !declared[name] {
+			return fmt.Errorf("unknown arg: %s", name)
+		}
+	}
+
+	if _, err := i.call(ctx, MethodValidate); err != nil {
+		return fmt.Errorf("plugin validate error: %s", err.Error())
+	}
+
+	return nil
+}
+
+func (i *PluginInjector) Inject(ctx context.Context) error {
+	resp, err := i.call(ctx, MethodInject)
+	if err != nil {
+		return err
+	}
+
+	if len(resp.Data) != 0 {
+		if err := json.Unmarshal(resp.Data, &i.Runtime); err != nil {
+			return fmt.Errorf("runtime returned by plugin must be an object: %s", err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (i *PluginInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	_, err := i.call(ctx, MethodRecover)
+	return err
+}
+
+// Query return the live status of the fault reported by plugin
+func (i *PluginInjector) Query(ctx context.Context) (string, error) {
+	resp, err := i.call(ctx, MethodQuery)
+	if err != nil {
+		return "", err
+	}
+
+	var detail string
+	if err := json.Unmarshal(resp.Data, &detail); err == nil {
+		return detail, nil
+	}
+
+	return string(resp.Data), nil
+}
+
+func (i *PluginInjector) call(ctx context.Context, method string) (*Response, error) {
+	return call(ctx, i.path, &Request{
+		Method: method,
+		Experiment: &ExperimentInfo{
+			Uid:              i.Info.Uid,
+			Target:           i.Info.Target,
+			Fault:            i.Info.Fault,
+			Timeout:          i.Info.Timeout,
+			Creator:          i.Info.Creator,
+			ContainerId:      i.Info.ContainerId,
+			ContainerRuntime: i.Info.ContainerRuntime,
+		},
+		Args:    i.Args,
+		Runtime: i.Runtime,
+	}, CallTimeout)
+}
@@ -0,0 +1,264 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package plugin
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/audit"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	// LoadAnnotation marks the command which needs the faults of plugins, see LoadDefault
+	LoadAnnotation = "chaosmetad/plugin"
+	// DirEnv is the environment variable to specify the plugin directory, it is inherited by the delay recover process
+	DirEnv    = "CHAOSMETAD_PLUGIN_DIR"
+	pluginDir = "plugins"
+
+	DescribeTimeout = 5 * time.Second
+	CallTimeout     = 60 * time.Second
+)
+
+// the names used by the common flags of inject command
+var reservedArgs = []string{"timeout", "creator", "uid", "container-id", "container-runtime", "guardrail-override",
+	"help", "log-level", "log-path", "trace-id", "db-path", "guardrail-file", "on", "off", "on-off-jitter", "probability"}
+
+var (
+	loadOnce   sync.Once
+	loadErrors []string
+)
+
+// LoadDefault registers the faults of the plugins in GetDir only once. It must be called after all the built-in
+// injectors are registered, and only by the commands which need to find injectors
+func LoadDefault() {
+	loadOnce.Do(func() {
+		loadErrors = Load(GetDir())
+	})
+}
+
+// GetDir return the plugin directory, default "[run path]/plugins"
+func GetDir() string {
+	if dir := os.Getenv(DirEnv); dir != "" {
+		return dir
+	}
+
+	return filepath.Join(utils.GetRunPath(), pluginDir)
+}
+
+// LogLoadErrors log the reasons of the plugins or faults which are failed to be registered by LoadDefault
+func LogLoadErrors(ctx context.Context) {
+	for _, loadErr := range loadErrors {
+		log.GetLogger(ctx).Warnf("load plugin from %s error: %s", GetDir(), loadErr)
+	}
+}
+
+// Load registers the faults provided by the executable files in dir. The built-in injectors will never be overwritten
+func Load(dir string) []string {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+
+		return []string{fmt.Sprintf("read plugin dir[%s] error: %s", dir, err.Error())}
+	}
+
+	var errs []string
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil || info.Mode()&0111 == 0 {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		desc, err := describe(path)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("describe plugin[%s] error: %s", path, err.Error()))
+			continue
+		}
+
+		for _, f := range desc.Faults {
+			if err := f.Validate(); err != nil {
+				errs = append(errs, fmt.Sprintf("fault[%s %s] of plugin[%s] is invalid: %s", f.Target, f.Fault, path, err.Error()))
+				continue
+			}
+
+			if _, err := injector.NewInjector(f.Target, f.Fault); err == nil {
+				errs = append(errs, fmt.Sprintf("fault[%s %s] of plugin[%s] is already registered", f.Target, f.Fault, path))
+				continue
+			}
+
+			fault := f
+			injector.Register(fault.Target, fault.Fault, func() injector.IInjector {
+				return &PluginInjector{path: path, fault: fault}
+			})
+		}
+	}
+
+	return errs
+}
+
+func describe(path string) (*Descriptor, error) {
+	resp, err := call(context.Background(), path, &Request{Method: MethodDescribe}, DescribeTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var desc = &Descriptor{}
+	if err := json.Unmarshal(resp.Data, desc); err != nil {
+		return nil, fmt.Errorf("descriptor format error: %s", err.Error())
+	}
+
+	if desc.ProtocolVersion != ProtocolVersion {
+		return nil, fmt.Errorf("protocol version[%d] is not supported, expected: %d", desc.ProtocolVersion, ProtocolVersion)
+	}
+
+	return desc, nil
+}
+
+// Validate check the names and types declared by plugin
+func (f *FaultDescriptor) Validate() error {
+	for _, name := range []string{f.Target, f.Fault} {
+		if name == "" || strings.ContainsAny(name, utils.BuilderSplit+" ") {
+			return fmt.Errorf("target and fault must not be empty or contain \"%s\" and space", utils.BuilderSplit)
+		}
+	}
+
+	var names = make(map[string]bool)
+	for _, arg := range f.Args {
+		if arg.Name == "" || strings.Contains(arg.Name, " ") {
+			return fmt.Errorf("arg name[%s] is invalid", arg.Name)
+		}
+
+		if names[arg.Name] || utils.StrListContain(reservedArgs, arg.Name) {
+			return fmt.Errorf("arg name[%s] is duplicated or reserved", arg.Name)
+		}
+		names[arg.Name] = true
+
+		if arg.Default != nil {
+			if _, err := arg.Convert(arg.Default); err != nil {
+				return fmt.Errorf("default value of arg[%s] is invalid: %s", arg.Name, err.Error())
+			}
+		} else if _, err := arg.Convert(nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Convert check the type of value and convert it to the declared type, nil returns the zero value
+func (a *ArgDescriptor) Convert(value interface{}) (interface{}, error) {
+	switch a.Type {
+	case ArgTypeString:
+		if value == nil {
+			return "", nil
+		}
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+	case ArgTypeBool:
+		if value == nil {
+			return false, nil
+		}
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	case ArgTypeInt:
+		switch v := value.(type) {
+		case nil:
+			return 0, nil
+		case int:
+			return v, nil
+		case float64:
+			if v == float64(int(v)) {
+				return int(v), nil
+			}
+		}
+	case ArgTypeFloat:
+		switch v := value.(type) {
+		case nil:
+			return float64(0), nil
+		case int:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		}
+	default:
+		return nil, fmt.Errorf("type[%s] of arg[%s] is not supported, support: %s, %s, %s, %s",
+			a.Type, a.Name, ArgTypeString, ArgTypeInt, ArgTypeFloat, ArgTypeBool)
+	}
+
+	return nil, fmt.Errorf("value[%v] of arg[%s] is not a valid %s", value, a.Name, a.Type)
+}
+
+// call send the request to plugin and wait for the response. The call is recorded to the audit trail of experiment bound to ctx
+func call(ctx context.Context, path string, req *Request, timeout time.Duration) (*Response, error) {
+	req.ProtocolVersion = ProtocolVersion
+	reqBytes, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("request convert to string error: %s", err.Error())
+	}
+
+	timeoutCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	c, startTime := exec.CommandContext(timeoutCtx, path), time.Now()
+	var stdout, stderr bytes.Buffer
+	c.Stdin, c.Stdout, c.Stderr = bytes.NewReader(reqBytes), &stdout, &stderr
+	runErr := c.Run()
+
+	exitCode := audit.NoExitCode
+	if c.ProcessState != nil {
+		exitCode = c.ProcessState.Sys().(syscall.WaitStatus).ExitStatus()
+	}
+	audit.Record(ctx, audit.KindCmd, "", fmt.Sprintf("%s %s", path, req.Method), exitCode, stdout.String()+stderr.String(), runErr, startTime)
+
+	var resp = &Response{}
+	if err := json.Unmarshal(stdout.Bytes(), resp); err != nil {
+		if runErr != nil {
+			return nil, fmt.Errorf("run plugin error: %s, stderr: %s", runErr.Error(), strings.TrimSpace(stderr.String()))
+		}
+
+		return nil, fmt.Errorf("response format error: %s, stdout: %s", err.Error(), strings.TrimSpace(stdout.String()))
+	}
+
+	if resp.Code != 0 {
+		return nil, fmt.Errorf("code: %d, message: %s", resp.Code, resp.Message)
+	}
+
+	if runErr != nil {
+		return nil, fmt.Errorf("run plugin error: %s, stderr: %s", runErr.Error(), strings.TrimSpace(stderr.String()))
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,134 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package plugin
+
+import (
+	"context"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testPlugin = `#!/bin/bash
+req=$(cat)
+case "$req" in
+*'"method":"describe"'*)
+	echo '{"code":0,"data":{"protocol_version":1,"faults":[{"target":"plugintest","fault":"hang","args":[{"name":"duration","type":"int","required":true},{"name":"mode","type":"string","default":"all"}]},{"target":"plugintest","fault":"bad-name"}]}}' ;;
+*'"method":"inject"'*)
+	echo '{"code":0,"data":{"pid":100}}' ;;
+*'"method":"query"'*)
+	echo '{"code":0,"data":"running"}' ;;
+*'"method":"recover"'*)
+	echo '{"code":1,"message":"recover failed"}' ;;
+*)
+	echo '{"code":0}' ;;
+esac
+`
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "test-plugin"), []byte(testPlugin), 0755); err != nil {
+		t.Fatalf("write plugin error: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte("not a plugin"), 0644); err != nil {
+		t.Fatalf("write file error: %v", err)
+	}
+
+	errs := Load(dir)
+	if len(errs) != 1 {
+		t.Errorf("Load() errors = %v, want 1 error for invalid fault name", errs)
+	}
+
+	i, err := injector.NewInjector("plugintest", "hang")
+	if err != nil {
+		t.Fatalf("NewInjector() error = %v", err)
+	}
+
+	p := i.(*PluginInjector)
+	p.Args = map[string]interface{}{"duration": float64(10)}
+	p.SetDefault()
+	if p.Args["mode"] != "all" {
+		t.Errorf("SetDefault() args = %v, want default mode", p.Args)
+	}
+
+	ctx := context.Background()
+	if err := p.Inject(ctx); err != nil {
+		t.Fatalf("Inject() error = %v", err)
+	}
+	if p.Runtime["pid"] != float64(100) {
+		t.Errorf("Inject() runtime = %v", p.Runtime)
+	}
+
+	if detail, err := p.Query(ctx); err != nil || detail != "running" {
+		t.Errorf("Query() = %s, %v", detail, err)
+	}
+
+	if err := p.Recover(ctx); err == nil {
+		t.Errorf("Recover() should return the error of plugin")
+	}
+}
+
+func TestArgDescriptor_Convert(t *testing.T) {
+	tests := []struct {
+		name    string
+		arg     ArgDescriptor
+		value   interface{}
+		want    interface{}
+		wantErr bool
+	}{
+		{name: "int from json", arg: ArgDescriptor{Name: "a", Type: ArgTypeInt}, value: float64(3), want: 3},
+		{name: "int not integral", arg: ArgDescriptor{Name: "a", Type: ArgTypeInt}, value: 3.5, wantErr: true},
+		{name: "float from int", arg: ArgDescriptor{Name: "a", Type: ArgTypeFloat}, value: 3, want: float64(3)},
+		{name: "string", arg: ArgDescriptor{Name: "a", Type: ArgTypeString}, value: "x", want: "x"},
+		{name: "string mismatch", arg: ArgDescriptor{Name: "a", Type: ArgTypeString}, value: true, wantErr: true},
+		{name: "bool zero", arg: ArgDescriptor{Name: "a", Type: ArgTypeBool}, value: nil, want: false},
+		{name: "unknown type", arg: ArgDescriptor{Name: "a", Type: "list"}, value: "x", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.arg.Convert(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Convert() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Convert() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFaultDescriptor_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		fault   FaultDescriptor
+		wantErr bool
+	}{
+		{name: "normal", fault: FaultDescriptor{Target: "mq", Fault: "block", Args: []ArgDescriptor{{Name: "topic", Type: ArgTypeString}}}},
+		{name: "split in fault", fault: FaultDescriptor{Target: "mq", Fault: "block-all"}, wantErr: true},
+		{name: "reserved arg", fault: FaultDescriptor{Target: "mq", Fault: "block", Args: []ArgDescriptor{{Name: "timeout", Type: ArgTypeString}}}, wantErr: true},
+		{name: "duplicated arg", fault: FaultDescriptor{Target: "mq", Fault: "block", Args: []ArgDescriptor{{Name: "a", Type: ArgTypeInt}, {Name: "a", Type: ArgTypeInt}}}, wantErr: true},
+		{name: "invalid default", fault: FaultDescriptor{Target: "mq", Fault: "block", Args: []ArgDescriptor{{Name: "a", Type: ArgTypeInt, Default: "x"}}}, wantErr: true},
+		{name: "invalid type", fault: FaultDescriptor{Target: "mq", Fault: "block", Args: []ArgDescriptor{{Name: "a", Type: "map"}}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.fault.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+// Package plugin registers the external injectors found in the plugin directory.
+//
+// A plugin is an executable file. For every call, chaosmetad starts the plugin without arguments,
+// writes one Request as JSON to its stdin and reads one Response as JSON from its stdout. Anything
+// written to stderr is only logged. A plugin may provide several faults, and must support the methods:
+//
+//   - describe: return a Descriptor in "data", used to register the faults and create the command line flags
+//   - validate: check the args and the environment before the experiment is created
+//   - inject: inject the fault, the object returned in "data" is saved as the runtime of the experiment
+//   - recover: recover the fault, "runtime" is what inject returned
+//   - query: return the live status of the injected fault in "data", shown when the experiment is queried by uid
+//
+// A non-zero "code" in Response means the call fails, and "message" is the reason.
+package plugin
+
+import "encoding/json"
+
+// ProtocolVersion is increased only if the protocol is changed incompatibly
+const ProtocolVersion = 1
+
+const (
+	MethodDescribe = "describe"
+	MethodValidate = "validate"
+	MethodInject   = "inject"
+	MethodRecover  = "recover"
+	MethodQuery    = "query"
+)
+
+// type of fault args
+const (
+	ArgTypeString = "string"
+	ArgTypeInt    = "int"
+	ArgTypeFloat  = "float"
+	ArgTypeBool   = "bool"
+)
+
+type Request struct {
+	ProtocolVersion int                    `json:"protocol_version"`
+	Method          string                 `json:"method"`
+	Experiment      *ExperimentInfo        `json:"experiment,omitempty"`
+	Args            map[string]interface{} `json:"args,omitempty"`
+	Runtime         map[string]interface{} `json:"runtime,omitempty"`
+}
+
+type ExperimentInfo struct {
+	Uid              string `json:"uid"`
+	Target           string `json:"target"`
+	Fault            string `json:"fault"`
+	Timeout          string `json:"timeout,omitempty"`
+	Creator          string `json:"creator,omitempty"`
+	ContainerId      string `json:"container_id,omitempty"`
+	ContainerRuntime string `json:"container_runtime,omitempty"`
+}
+
+type Response struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message,omitempty"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+// Descriptor is the data returned by "describe"
+type Descriptor struct {
+	ProtocolVersion int               `json:"protocol_version"`
+	Faults          []FaultDescriptor `json:"faults"`
+}
+
+type FaultDescriptor struct {
+	Target      string          `json:"target"`
+	Fault       string          `json:"fault"`
+	Description string          `json:"description,omitempty"`
+	Args        []ArgDescriptor `json:"args,omitempty"`
+}
+
+type ArgDescriptor struct {
+	Name        string      `json:"name"`
+	Type        string      `json:"type"`
+	Required    bool        `json:"required,omitempty"`
+	Default     interface{} `json:"default,omitempty"`
+	Description string      `json:"description,omitempty"`
+}
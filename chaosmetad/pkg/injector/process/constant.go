@@ -23,5 +23,9 @@ const (
 
 	FaultProcessStop = "stop"
 
+	FaultProcessCrashLoop = "crashloop"
+
+	CrashLoopKey = "crashloop"
+
 	//ProcessExec = "chaosmeta_process"
 )
@@ -0,0 +1,404 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package process
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/audit"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/guardrail"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/net"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/process"
+	"os/signal"
+	"regexp"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+const (
+	DefaultCrashLoopInterval = "5s"
+	// MaxCrashLoopHistory is the max number of kill records kept in the runtime, "kills" keeps the total count
+	MaxCrashLoopHistory   = 50
+	crashLoopCheckTime    = time.Second
+	crashLoopStartTimeout = 30 * time.Second
+)
+
+func init() {
+	injector.Register(TargetProcess, FaultProcessCrashLoop, func() injector.IInjector { return &CrashLoopInjector{} })
+}
+
+// CrashLoopInjector keeps killing the matched processes started by supervisor during the experiment.
+// The kills are done by a background process, each process is killed once and recorded in the runtime
+type CrashLoopInjector struct {
+	injector.BaseInjector
+	Args    CrashLoopArgs
+	Runtime CrashLoopRuntime
+}
+
+type CrashLoopArgs struct {
+	Key        string `json:"key,omitempty"`
+	Cmdline    string `json:"cmdline,omitempty"`
+	Signal     int    `json:"signal,omitempty"`
+	Interval   string `json:"interval,omitempty"`
+	WaitPort   int    `json:"wait_port,omitempty"`
+	RecoverCmd string `json:"recover_cmd,omitempty"`
+}
+
+type CrashLoopRuntime struct {
+	Kills     int             `json:"kills"`
+	History   []CrashLoopKill `json:"history,omitempty"`
+	LastError string          `json:"last_error,omitempty"`
+}
+
+type CrashLoopKill struct {
+	Pid   int    `json:"pid"`
+	Time  string `json:"time"`
+	Error string `json:"error,omitempty"`
+}
+
+func (i *CrashLoopInjector) GetArgs() interface{} {
+	return &i.Args
+}
+
+func (i *CrashLoopInjector) GetRuntime() interface{} {
+	return &i.Runtime
+}
+
+func (i *CrashLoopInjector) SetDefault() {
+	i.BaseInjector.SetDefault()
+
+	if i.Args.Signal == 0 {
+		i.Args.Signal = process.SIGKILL
+	}
+
+	if i.Args.Interval == "" {
+		i.Args.Interval = DefaultCrashLoopInterval
+	}
+}
+
+func (i *CrashLoopInjector) SetOption(cmd *cobra.Command) {
+	// i.BaseInjector.SetOption(cmd)
+
+	cmd.Flags().StringVarP(&i.Args.Key, "key", "k", "", "the key contained in the cmdline of target process")
+	cmd.Flags().StringVarP(&i.Args.Cmdline, "cmdline", "c", "", "the regular expression matched with the whole cmdline of target process, eg: \"^java .*-jar app.jar\"")
+	cmd.Flags().IntVarP(&i.Args.Signal, "signal", "s", 0, fmt.Sprintf("send target signal to the target process（default %d）", process.SIGKILL))
+	cmd.Flags().StringVarP(&i.Args.Interval, "interval", "i", "", fmt.Sprintf("the min interval between two kills, support unit: s、m、h（default %s）", DefaultCrashLoopInterval))
+	cmd.Flags().IntVarP(&i.Args.WaitPort, "wait-port", "w", 0, "if provided, the new process is killed only after the tcp port is listened")
+	cmd.Flags().StringVarP(&i.Args.RecoverCmd, "recover-cmd", "r", "", "the cmd which execute in the recover stage")
+}
+
+func (i *CrashLoopInjector) Validator(ctx context.Context) error {
+	if err := i.BaseInjector.Validator(ctx); err != nil {
+		return err
+	}
+
+	if (i.Args.Key == "") == (i.Args.Cmdline == "") {
+		return fmt.Errorf("must provide one of \"key\" and \"cmdline\"")
+	}
+
+	if i.Args.Cmdline != "" {
+		if _, err := regexp.Compile(i.Args.Cmdline); err != nil {
+			return fmt.Errorf("\"cmdline\" is not a valid regular expression: %s", err.Error())
+		}
+	}
+
+	if i.Args.Signal <= 0 {
+		return fmt.Errorf("signal[%d] is invalid, must larget than 0", i.Args.Signal)
+	}
+
+	if second, err := utils.GetTimeSecond(i.Args.Interval); err != nil || second < 0 {
+		return fmt.Errorf("\"interval\" is invalid: %s", i.Args.Interval)
+	}
+
+	if i.Args.WaitPort < 0 || i.Args.WaitPort > 65535 {
+		return fmt.Errorf("\"wait-port\"[%d] is invalid", i.Args.WaitPort)
+	}
+
+	if i.Info.Intermittent.IsEnabled() {
+		return fmt.Errorf("%s %s already kills repeatedly, not support intermittent mode", TargetProcess, FaultProcessCrashLoop)
+	}
+
+	// the target may be restarting now, so an empty match is allowed
+	pidList, err := i.getPidList(ctx)
+	if err != nil {
+		return fmt.Errorf("get target process's pid error: %s", err.Error())
+	}
+
+	if len(pidList) > 0 {
+		if err := i.checkGuardrail(ctx, pidList); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (i *CrashLoopInjector) Inject(ctx context.Context) error {
+	return cmdexec.StartCrashLoop(ctx, i.Info.Uid, guardrail.GetPathArgs())
+}
+
+func (i *CrashLoopInjector) Recover(ctx context.Context) error {
+	if i.BaseInjector.Recover(ctx) == nil {
+		return nil
+	}
+
+	if err := process.CheckExistAndKillByKey(ctx, getCrashLoopKey(i.Info.Uid)); err != nil {
+		return fmt.Errorf("stop crash loop process error: %s", err.Error())
+	}
+
+	if i.Args.RecoverCmd != "" {
+		return cmdexec.ExecBackGroundCommon(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.RecoverCmd)
+	}
+
+	return nil
+}
+
+func getCrashLoopKey(uid string) string {
+	return fmt.Sprintf("%s %s %s", utils.RootName, CrashLoopKey, uid)
+}
+
+func (i *CrashLoopInjector) checkGuardrail(ctx context.Context, pidList []int) error {
+	r, err := guardrail.ProcessResources(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, pidList)
+	if err != nil {
+		return fmt.Errorf("get resources of target process error: %s", err.Error())
+	}
+
+	return i.CheckGuardrail(ctx, r)
+}
+
+// getPidList return the matched pid list in container's pid ns, empty if no process matched.
+// "key" is matched as a plain substring of the cmdline
+func (i *CrashLoopInjector) getPidList(ctx context.Context) ([]int, error) {
+	pattern := i.Args.Cmdline
+	if i.Args.Key != "" {
+		pattern = regexp.QuoteMeta(i.Args.Key)
+	}
+
+	re, err := cmdexec.ExecCommonWithNS(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, "ps -eo pid,args", []string{namespace.PID, namespace.MNT})
+	if err != nil {
+		return nil, fmt.Errorf("get process list error: %s", err.Error())
+	}
+
+	return matchCmdline(re, regexp.MustCompile(pattern)), nil
+}
+
+// matchCmdline parses the output of "ps -eo pid,args", the processes of chaosmetad itself and ps are ignored
+func matchCmdline(psOutput string, pattern *regexp.Regexp) []int {
+	var pidList []int
+	for _, line := range strings.Split(psOutput, "\n") {
+		fields := strings.SplitN(strings.TrimSpace(line), " ", 2)
+		if len(fields) != 2 {
+			continue
+		}
+
+		pid, err := strconv.Atoi(fields[0])
+		if err != nil {
+			continue
+		}
+
+		cmdline := strings.TrimSpace(fields[1])
+		if strings.Contains(cmdline, utils.RootName+" ") || strings.Contains(cmdline, "ps -eo pid,args") || strings.Contains(cmdline, "chaosmeta_execns ") {
+			continue
+		}
+
+		if pattern.MatchString(cmdline) {
+			pidList = append(pidList, pid)
+		}
+	}
+
+	return pidList
+}
+
+// isReady checks if the new process can be killed now, the listened port is checked in container's net ns
+func (i *CrashLoopInjector) isReady(ctx context.Context) (bool, error) {
+	if i.Args.WaitPort == 0 {
+		return true, nil
+	}
+
+	pid, err := net.GetPidByPort(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, i.Args.WaitPort, net.ProtocolTCP)
+	if err != nil {
+		return false, fmt.Errorf("get pid by port[%d] error: %s", i.Args.WaitPort, err.Error())
+	}
+
+	return pid != utils.NoPid, nil
+}
+
+func (r *CrashLoopRuntime) addKill(pid int, err error) {
+	k := CrashLoopKill{Pid: pid, Time: time.Now().Format(utils.TimeFormat)}
+	if err != nil {
+		k.Error = err.Error()
+	} else {
+		r.Kills++
+	}
+
+	r.History = append(r.History, k)
+	if len(r.History) > MaxCrashLoopHistory {
+		r.History = r.History[len(r.History)-MaxCrashLoopHistory:]
+	}
+}
+
+// RunCrashLoop kills the new matched processes until the experiment is not running or SIGTERM received
+func RunCrashLoop(ctx context.Context, uid string) error {
+	logger := log.GetLogger(ctx)
+	sigCtx, cancel := signal.NotifyContext(ctx, syscall.SIGTERM, syscall.SIGINT)
+	defer cancel()
+
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		return fmt.Errorf("connect db error: %s", err.Error())
+	}
+
+	exp, err := waitCrashLoopStart(sigCtx, uid)
+	if err != nil || exp == nil {
+		return err
+	}
+
+	i := &CrashLoopInjector{}
+	if err := i.LoadInjector(exp, i.GetArgs(), i.GetRuntime()); err != nil {
+		return fmt.Errorf("load experiment to injector error: %s", err.Error())
+	}
+
+	var (
+		interval, _ = utils.GetTimeSecond(i.Args.Interval)
+		lastKill    time.Time
+		killed      = make(map[int]bool)
+		ticker      = time.NewTicker(crashLoopCheckTime)
+	)
+	defer ticker.Stop()
+	ctx = audit.WithExperiment(ctx, uid, utils.MethodInject)
+	for _, k := range i.Runtime.History {
+		killed[k.Pid] = true
+	}
+
+	for {
+		select {
+		case <-sigCtx.Done():
+			logger.Infof("crash loop experiment[%s] stopped", uid)
+			return nil
+		case <-ticker.C:
+		}
+
+		if exp, err := db.GetByUid(uid); err != nil || exp.Status != utils.StatusSuccess {
+			logger.Infof("crash loop experiment[%s] is not running, exit", uid)
+			return nil
+		}
+
+		if time.Since(lastKill) < time.Duration(interval)*time.Second {
+			continue
+		}
+
+		if changed := i.killOnce(ctx, killed); changed {
+			lastKill = time.Now()
+			runtime, _ := i.OptionToExp(i.GetArgs(), i.GetRuntime())
+			if err := db.UpdateRuntime(uid, runtime.Runtime, runtime.Intermittent); err != nil {
+				logger.Warnf("save crash loop experiment[%s] error: %s", uid, err.Error())
+			}
+		}
+	}
+}
+
+// killOnce kills the new matched processes if they are ready, return true if the runtime is changed
+func (i *CrashLoopInjector) killOnce(ctx context.Context, killed map[int]bool) bool {
+	logger := log.GetLogger(ctx)
+	pidList, err := i.getPidList(ctx)
+	if err != nil {
+		logger.Warnf("get target process's pid error: %s", err.Error())
+		return false
+	}
+
+	var newPidList []int
+	for _, pid := range pidList {
+		if !killed[pid] {
+			newPidList = append(newPidList, pid)
+		}
+	}
+
+	if len(newPidList) == 0 {
+		return false
+	}
+
+	if ready, err := i.isReady(ctx); err != nil || !ready {
+		if err != nil {
+			logger.Warnf("check process ready error: %s", err.Error())
+		}
+		return false
+	}
+
+	if err := i.checkGuardrail(ctx, newPidList); err != nil {
+		for _, pid := range newPidList {
+			killed[pid] = true
+		}
+		i.Runtime.LastError = fmt.Sprintf("skip process%v: %s", newPidList, err.Error())
+		logger.Warnf(i.Runtime.LastError)
+		return true
+	}
+
+	for _, pid := range newPidList {
+		err := process.SignalProcessByPid(ctx, i.Info.ContainerRuntime, i.Info.ContainerId, pid, i.Args.Signal)
+		killed[pid] = true
+		i.Runtime.addKill(pid, err)
+		if err != nil {
+			i.Runtime.LastError = fmt.Sprintf("signal process[%d] error: %s", pid, err.Error())
+			logger.Warnf(i.Runtime.LastError)
+		} else {
+			logger.Infof("process[%d] is killed by signal[%d]", pid, i.Args.Signal)
+		}
+	}
+
+	return true
+}
+
+// waitCrashLoopStart waits for the inject process to save the experiment as running, return nil if it never starts
+func waitCrashLoopStart(ctx context.Context, uid string) (*storage.Experiment, error) {
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		return nil, fmt.Errorf("connect db error: %s", err.Error())
+	}
+
+	deadline := time.Now().Add(crashLoopStartTimeout)
+	for time.Now().Before(deadline) {
+		exp, err := db.GetByUid(uid)
+		if err != nil {
+			return nil, fmt.Errorf("query experiment by uid[%s] error: %s", uid, err.Error())
+		}
+
+		if exp.Status == utils.StatusSuccess {
+			return exp, nil
+		}
+
+		if exp.Status != utils.StatusCreated {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, nil
+		case <-time.After(crashLoopCheckTime):
+		}
+	}
+
+	log.GetLogger(ctx).Infof("crash loop experiment[%s] is not running, exit", uid)
+	return nil, nil
+}
@@ -0,0 +1,73 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package process
+
+import (
+	"errors"
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestMatchCmdline(t *testing.T) {
+	psOutput := `    PID COMMAND
+      1 /sbin/init
+    100 java -jar app.jar --port 8080
+    101 /bin/bash -c java -jar app.jar
+    102 /usr/local/chaosmetad inject process crashloop -k app.jar
+    103 /usr/local/chaosmetad crashloop 2023080112000001
+    104 ps -eo pid,args
+    105 /bin/bash -c ps -eo pid,args
+    106 /usr/local/tools/chaosmeta_execns -t 1 -c java -jar app.jar
+`
+	tests := []struct {
+		name    string
+		pattern string
+		want    []int
+	}{
+		{name: "substring", pattern: regexp.QuoteMeta("app.jar"), want: []int{100, 101}},
+		{name: "regexp", pattern: "^java .*--port 8080$", want: []int{100}},
+		{name: "no match", pattern: "nginx", want: nil},
+		{name: "ignore ps", pattern: "pid,args", want: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := matchCmdline(psOutput, regexp.MustCompile(tt.pattern)); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("matchCmdline() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCrashLoopRuntime_addKill(t *testing.T) {
+	r := &CrashLoopRuntime{}
+	for pid := 1; pid <= MaxCrashLoopHistory+10; pid++ {
+		r.addKill(pid, nil)
+	}
+	r.addKill(0, errors.New("no such process"))
+
+	if r.Kills != MaxCrashLoopHistory+10 {
+		t.Errorf("Kills = %d, want %d", r.Kills, MaxCrashLoopHistory+10)
+	}
+
+	if len(r.History) != MaxCrashLoopHistory {
+		t.Fatalf("len(History) = %d, want %d", len(r.History), MaxCrashLoopHistory)
+	}
+
+	if first, last := r.History[0], r.History[len(r.History)-1]; first.Pid != 12 || last.Error == "" {
+		t.Errorf("History is not trimmed correctly, first = %+v, last = %+v", first, last)
+	}
+}
@@ -21,9 +21,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/bndr/gotabulate"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/watch"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/handler"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/model"
 )
@@ -68,19 +70,37 @@ func PrintExpByOption(ctx context.Context, o *OptionExpQuery, ifAll bool, format
 		errutil.SolveErr(ctx, errutil.DBErr, queryErr.Error())
 	}
 
+	// the live status is only queried for one experiment, because it may be expensive
+	var details = make(map[string]string)
+	if o.Uid != "" {
+		for _, exp := range exps {
+			detail, err := injector.QueryDetail(ctx, exp)
+			if err != nil {
+				detail = fmt.Sprintf("query detail error: %s", err.Error())
+			}
+			details[exp.Uid] = detail
+		}
+	}
+
 	if format == JsonFormat {
-		printJson(ctx, exps, total)
+		printJson(ctx, exps, total, details)
 	} else {
 		log.GetLogger(ctx).Infof("query args: %s", string(temp))
 		printTable(ctx, exps, total, ifAll)
+		for uid, detail := range details {
+			if detail != "" {
+				log.GetLogger(ctx).Infof("detail of experiment[%s]: %s", uid, detail)
+			}
+		}
 	}
 }
 
-func printJson(ctx context.Context, exps []*storage.Experiment, total int64) {
+func printJson(ctx context.Context, exps []*storage.Experiment, total int64, details map[string]string) {
 	logger := log.GetLogger(ctx)
 	reList := make([]model.ExperimentDataUnit, len(exps))
 	for i, exp := range exps {
 		reList[i] = handler.ExpToExperimentDataUnit(exp)
+		reList[i].Detail = details[exp.Uid]
 	}
 
 	res := &model.QueryResponseData{
@@ -109,7 +129,7 @@ func printTable(ctx context.Context, exps []*storage.Experiment, total int64, if
 			var aData []interface{}
 			if ifAll {
 				aData = []interface{}{exp.Uid, exp.Status, exp.Target, exp.Fault, exp.Args, exp.Creator, exp.Runtime,
-					exp.ContainerId, exp.ContainerRuntime, exp.Timeout, exp.Error, exp.CreateTime, exp.UpdateTime}
+					exp.ContainerId, exp.ContainerRuntime, exp.Timeout, exp.Error, exp.CreateTime, exp.UpdateTime, exp.GuardrailViolation, exp.Intermittent}
 			} else {
 				aData = []interface{}{exp.Uid, exp.Status, exp.Target, exp.Fault, exp.Args}
 			}
@@ -120,7 +140,7 @@ func printTable(ctx context.Context, exps []*storage.Experiment, total int64, if
 		t := gotabulate.Create(data)
 		if ifAll {
 			t.SetHeaders([]string{"UID", "STATUS", "TARGET", "FAULT", "ARGS", "CREATOR", "RUNTIME",
-				"CONTAINER_ID", "CONTAINER_RUNTIME", "TIMEOUT", "ERROR", "CREATE_TIME", "UPDATE_TIME"})
+				"CONTAINER_ID", "CONTAINER_RUNTIME", "TIMEOUT", "ERROR", "CREATE_TIME", "UPDATE_TIME", "GUARDRAIL_VIOLATION", "INTERMITTENT"})
 		} else {
 			t.SetHeaders([]string{"UID", "STATUS", "TARGET", "FAULT", "ARGS"})
 		}
@@ -132,3 +152,109 @@ func printTable(ctx context.Context, exps []*storage.Experiment, total int64, if
 
 	logger.Infof("total count of experiments: %d\n%s\n", total, formatData)
 }
+
+// WatchExpByOption print the status transitions of experiments until interrupted.
+// If uid is provided, it will exit after the experiment comes to a terminal status
+func WatchExpByOption(ctx context.Context, o *OptionExpQuery, revision int64, format string) {
+	logger := log.GetLogger(ctx)
+	if format != TableFormat && format != JsonFormat {
+		errutil.SolveErr(ctx, errutil.BadArgsErr, fmt.Sprintf("not support format: %s", format))
+	}
+
+	if o == nil {
+		errutil.SolveErr(ctx, errutil.BadArgsErr, fmt.Sprintf("option is empty"))
+	}
+
+	_, err := watch.Watch(ctx, &watch.OptionWatch{
+		Uid:      o.Uid,
+		Target:   o.Target,
+		Fault:    o.Fault,
+		Revision: revision,
+	}, func(events []*storage.ExperimentEvent) bool {
+		for _, event := range events {
+			if format == JsonFormat {
+				reBytes, err := json.Marshal(handler.EventToExperimentEventUnit(event))
+				if err != nil {
+					errutil.SolveErr(ctx, errutil.InternalErr, fmt.Sprintf("event change to string error: %s", err.Error()))
+				}
+
+				if log.Path != "" {
+					logger.Info(string(reBytes))
+				} else {
+					fmt.Println(string(reBytes))
+				}
+			} else {
+				logger.Infof("revision: %d, uid: %s, target: %s, fault: %s, status: %s, error: %s, time: %s",
+					event.Revision, event.Uid, event.Target, event.Fault, event.Status, event.Error, event.CreateTime)
+			}
+
+			if o.Uid != "" && watch.IsTerminal(event.Status) {
+				return false
+			}
+		}
+
+		return true
+	})
+
+	if err != nil {
+		errutil.SolveErr(ctx, errutil.DBErr, err.Error())
+	}
+}
+
+// PrintAuditByUid print the commands, file writes, cgroup changes and container execs performed for the experiment
+func PrintAuditByUid(ctx context.Context, uid, format string) {
+	logger := log.GetLogger(ctx)
+	if format != TableFormat && format != JsonFormat {
+		errutil.SolveErr(ctx, errutil.BadArgsErr, fmt.Sprintf("not support format: %s", format))
+	}
+
+	db, dbErr := storage.GetExperimentStore()
+	if dbErr != nil {
+		errutil.SolveErr(ctx, errutil.DBErr, dbErr.Error())
+	}
+
+	if _, err := db.GetByUid(uid); err != nil {
+		errutil.SolveErr(ctx, errutil.DBErr, fmt.Sprintf("query experiment by uid[%s] error: %s", uid, err.Error()))
+	}
+
+	audits, err := db.ListAudits(uid)
+	if err != nil {
+		errutil.SolveErr(ctx, errutil.DBErr, fmt.Sprintf("query audits of experiment[%s] error: %s", uid, err.Error()))
+	}
+
+	if format == JsonFormat {
+		reList := make([]model.ExperimentAuditUnit, len(audits))
+		for i, a := range audits {
+			reList[i] = handler.AuditToExperimentAuditUnit(a)
+		}
+
+		reBytes, err := json.Marshal(reList)
+		if err != nil {
+			errutil.SolveErr(ctx, errutil.InternalErr, fmt.Sprintf("audits change to string error: %s", err.Error()))
+		}
+
+		if log.Path != "" {
+			logger.Info(string(reBytes))
+		} else {
+			fmt.Println(string(reBytes))
+		}
+		return
+	}
+
+	var formatData string
+	if len(audits) != 0 {
+		var data [][]interface{}
+		for _, a := range audits {
+			data = append(data, []interface{}{a.Phase, a.Kind, a.ContainerId, a.Command, a.ExitCode, a.Output, a.Error, a.StartTime, a.EndTime})
+		}
+
+		t := gotabulate.Create(data)
+		t.SetHeaders([]string{"PHASE", "KIND", "CONTAINER_ID", "COMMAND", "EXIT_CODE", "OUTPUT", "ERROR", "START_TIME", "END_TIME"})
+		t.SetEmptyString("None")
+		t.SetAlign("left")
+		t.SetWrapStrings(true)
+		formatData = t.Render("grid")
+	}
+
+	logger.Infof("total count of audits of experiment[%s]: %d\n%s\n", uid, len(audits), formatData)
+}
@@ -0,0 +1,281 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.2.0
+// - protoc             v3.21.12
+// source: chaosmetad.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// ChaosmetadClient is the client API for Chaosmetad service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ChaosmetadClient interface {
+	Inject(ctx context.Context, in *InjectRequest, opts ...grpc.CallOption) (*InjectResponse, error)
+	Recover(ctx context.Context, in *RecoverRequest, opts ...grpc.CallOption) (*CommonResponse, error)
+	Query(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*QueryResponse, error)
+	// Watch streams the events after the revision until the client cancels
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Chaosmetad_WatchClient, error)
+	// Describe lists the supported targets and faults with their args
+	Describe(ctx context.Context, in *DescribeRequest, opts ...grpc.CallOption) (*DescribeResponse, error)
+}
+
+type chaosmetadClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewChaosmetadClient(cc grpc.ClientConnInterface) ChaosmetadClient {
+	return &chaosmetadClient{cc}
+}
+
+func (c *chaosmetadClient) Inject(ctx context.Context, in *InjectRequest, opts ...grpc.CallOption) (*InjectResponse, error) {
+	out := new(InjectResponse)
+	err := c.cc.Invoke(ctx, "/chaosmetad.v1.Chaosmetad/Inject", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chaosmetadClient) Recover(ctx context.Context, in *RecoverRequest, opts ...grpc.CallOption) (*CommonResponse, error) {
+	out := new(CommonResponse)
+	err := c.cc.Invoke(ctx, "/chaosmetad.v1.Chaosmetad/Recover", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chaosmetadClient) Query(ctx context.Context, in *QueryRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
+	out := new(QueryResponse)
+	err := c.cc.Invoke(ctx, "/chaosmetad.v1.Chaosmetad/Query", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *chaosmetadClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (Chaosmetad_WatchClient, error) {
+	stream, err := c.cc.NewStream(ctx, &Chaosmetad_ServiceDesc.Streams[0], "/chaosmetad.v1.Chaosmetad/Watch", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &chaosmetadWatchClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type Chaosmetad_WatchClient interface {
+	Recv() (*ExperimentEvent, error)
+	grpc.ClientStream
+}
+
+type chaosmetadWatchClient struct {
+	grpc.ClientStream
+}
+
+func (x *chaosmetadWatchClient) Recv() (*ExperimentEvent, error) {
+	m := new(ExperimentEvent)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *chaosmetadClient) Describe(ctx context.Context, in *DescribeRequest, opts ...grpc.CallOption) (*DescribeResponse, error) {
+	out := new(DescribeResponse)
+	err := c.cc.Invoke(ctx, "/chaosmetad.v1.Chaosmetad/Describe", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ChaosmetadServer is the server API for Chaosmetad service.
+// All implementations must embed UnimplementedChaosmetadServer
+// for forward compatibility
+type ChaosmetadServer interface {
+	Inject(context.Context, *InjectRequest) (*InjectResponse, error)
+	Recover(context.Context, *RecoverRequest) (*CommonResponse, error)
+	Query(context.Context, *QueryRequest) (*QueryResponse, error)
+	// Watch streams the events after the revision until the client cancels
+	Watch(*WatchRequest, Chaosmetad_WatchServer) error
+	// Describe lists the supported targets and faults with their args
+	Describe(context.Context, *DescribeRequest) (*DescribeResponse, error)
+	mustEmbedUnimplementedChaosmetadServer()
+}
+
+// UnimplementedChaosmetadServer must be embedded to have forward compatible implementations.
+type UnimplementedChaosmetadServer struct {
+}
+
+func (UnimplementedChaosmetadServer) Inject(context.Context, *InjectRequest) (*InjectResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Inject not implemented")
+}
+func (UnimplementedChaosmetadServer) Recover(context.Context, *RecoverRequest) (*CommonResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Recover not implemented")
+}
+func (UnimplementedChaosmetadServer) Query(context.Context, *QueryRequest) (*QueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Query not implemented")
+}
+func (UnimplementedChaosmetadServer) Watch(*WatchRequest, Chaosmetad_WatchServer) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedChaosmetadServer) Describe(context.Context, *DescribeRequest) (*DescribeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Describe not implemented")
+}
+func (UnimplementedChaosmetadServer) mustEmbedUnimplementedChaosmetadServer() {}
+
+// UnsafeChaosmetadServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ChaosmetadServer will
+// result in compilation errors.
+type UnsafeChaosmetadServer interface {
+	mustEmbedUnimplementedChaosmetadServer()
+}
+
+func RegisterChaosmetadServer(s grpc.ServiceRegistrar, srv ChaosmetadServer) {
+	s.RegisterService(&Chaosmetad_ServiceDesc, srv)
+}
+
+func _Chaosmetad_Inject_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InjectRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChaosmetadServer).Inject(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chaosmetad.v1.Chaosmetad/Inject",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChaosmetadServer).Inject(ctx, req.(*InjectRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chaosmetad_Recover_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecoverRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChaosmetadServer).Recover(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chaosmetad.v1.Chaosmetad/Recover",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChaosmetadServer).Recover(ctx, req.(*RecoverRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chaosmetad_Query_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QueryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChaosmetadServer).Query(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chaosmetad.v1.Chaosmetad/Query",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChaosmetadServer).Query(ctx, req.(*QueryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Chaosmetad_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ChaosmetadServer).Watch(m, &chaosmetadWatchServer{stream})
+}
+
+type Chaosmetad_WatchServer interface {
+	Send(*ExperimentEvent) error
+	grpc.ServerStream
+}
+
+type chaosmetadWatchServer struct {
+	grpc.ServerStream
+}
+
+func (x *chaosmetadWatchServer) Send(m *ExperimentEvent) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _Chaosmetad_Describe_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DescribeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChaosmetadServer).Describe(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/chaosmetad.v1.Chaosmetad/Describe",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChaosmetadServer).Describe(ctx, req.(*DescribeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Chaosmetad_ServiceDesc is the grpc.ServiceDesc for Chaosmetad service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Chaosmetad_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "chaosmetad.v1.Chaosmetad",
+	HandlerType: (*ChaosmetadServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Inject",
+			Handler:    _Chaosmetad_Inject_Handler,
+		},
+		{
+			MethodName: "Recover",
+			Handler:    _Chaosmetad_Recover_Handler,
+		},
+		{
+			MethodName: "Query",
+			Handler:    _Chaosmetad_Query_Handler,
+		},
+		{
+			MethodName: "Describe",
+			Handler:    _Chaosmetad_Describe_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _Chaosmetad_Watch_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "chaosmetad.proto",
+}
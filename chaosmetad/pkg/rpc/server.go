@@ -0,0 +1,241 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/rpc/pb"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/watch"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/handler"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/model"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Server serves the same handlers as the HTTP API
+type Server struct {
+	pb.UnimplementedChaosmetadServer
+}
+
+func NewServer() *grpc.Server {
+	s := grpc.NewServer(
+		grpc.UnaryInterceptor(unaryAuthInterceptor),
+		grpc.StreamInterceptor(streamAuthInterceptor),
+	)
+	pb.RegisterChaosmetadServer(s, &Server{})
+	return s
+}
+
+func (s *Server) Inject(ctx context.Context, req *pb.InjectRequest) (*pb.InjectResponse, error) {
+	ctx = utils.GetCtxWithTraceId(ctx, req.TraceId)
+	var remoteAddr string
+	if p, ok := peer.FromContext(ctx); ok {
+		remoteAddr = p.Addr.String()
+	}
+
+	res := handler.Inject(ctx, &model.InjectRequest{
+		Target:            req.Target,
+		Fault:             req.Fault,
+		Timeout:           req.Timeout,
+		Creator:           req.Creator,
+		Args:              req.Args,
+		ContainerId:       req.ContainerId,
+		ContainerRuntime:  req.ContainerRuntime,
+		TraceId:           req.TraceId,
+		Uid:               req.Uid,
+		GuardrailOverride: req.GuardrailOverride,
+		On:                req.On,
+		Off:               req.Off,
+		OnOffJitter:       req.OnOffJitter,
+		Probability:       req.Probability,
+	}, remoteAddr)
+
+	re := &pb.InjectResponse{
+		Code:    int32(res.Code),
+		Message: res.Message,
+		TraceId: res.TraceId,
+	}
+	if res.Data != nil {
+		re.Experiment = experimentToPb(&res.Data.Experiment)
+	}
+
+	return re, nil
+}
+
+func (s *Server) Recover(ctx context.Context, req *pb.RecoverRequest) (*pb.CommonResponse, error) {
+	ctx = utils.GetCtxWithTraceId(ctx, req.TraceId)
+	res := handler.Recover(ctx, &model.RecoverRequest{
+		Uid:     req.Uid,
+		TraceId: req.TraceId,
+	})
+
+	return &pb.CommonResponse{
+		Code:    int32(res.Code),
+		Message: res.Message,
+		TraceId: res.TraceId,
+	}, nil
+}
+
+func (s *Server) Query(ctx context.Context, req *pb.QueryRequest) (*pb.QueryResponse, error) {
+	ctx = utils.GetCtxWithTraceId(ctx, req.TraceId)
+	res := handler.Query(ctx, &model.QueryRequest{
+		Uid:              req.Uid,
+		Status:           req.Status,
+		Target:           req.Target,
+		Fault:            req.Fault,
+		Creator:          req.Creator,
+		ContainerId:      req.ContainerId,
+		ContainerRuntime: req.ContainerRuntime,
+		Offset:           req.Offset,
+		Limit:            req.Limit,
+		Audit:            req.Audit,
+		TraceId:          req.TraceId,
+	})
+
+	re := &pb.QueryResponse{
+		Code:    int32(res.Code),
+		Message: res.Message,
+		TraceId: res.TraceId,
+	}
+	if res.Data != nil {
+		re.Total = res.Data.Total
+		re.Experiments = make([]*pb.Experiment, len(res.Data.Experiments))
+		for i := range res.Data.Experiments {
+			re.Experiments[i] = experimentToPb(&res.Data.Experiments[i])
+		}
+	}
+
+	return re, nil
+}
+
+// Watch send the events after the revision one by one until the client cancels, the revision of each event can be used to resume
+func (s *Server) Watch(req *pb.WatchRequest, stream pb.Chaosmetad_WatchServer) error {
+	var (
+		ctx     = utils.GetCtxWithTraceId(stream.Context(), req.TraceId)
+		logger  = log.GetLogger(ctx)
+		sendErr error
+	)
+
+	if _, err := watch.Watch(ctx, &watch.OptionWatch{
+		Uid:      req.Uid,
+		Target:   req.Target,
+		Fault:    req.Fault,
+		Revision: req.Revision,
+	}, func(events []*storage.ExperimentEvent) bool {
+		for _, event := range events {
+			e := handler.EventToExperimentEventUnit(event)
+			if sendErr = stream.Send(&pb.ExperimentEvent{
+				Revision:   e.Revision,
+				Uid:        e.Uid,
+				Target:     e.Target,
+				Fault:      e.Fault,
+				Status:     e.Status,
+				Error:      e.Error_,
+				CreateTime: e.CreateTime,
+			}); sendErr != nil {
+				logger.Warnf("send event[%d] error: %s", event.Revision, sendErr.Error())
+				return false
+			}
+		}
+		return true
+	}); err != nil {
+		logger.Errorf("watch error: %s", err.Error())
+		return status.Errorf(codes.Internal, "watch error: %s", err.Error())
+	}
+
+	return sendErr
+}
+
+func (s *Server) Describe(ctx context.Context, req *pb.DescribeRequest) (*pb.DescribeResponse, error) {
+	ctx = utils.GetCtxWithTraceId(ctx, req.TraceId)
+	res := handler.Describe(ctx, &model.DescribeRequest{
+		Target:  req.Target,
+		Fault:   req.Fault,
+		TraceId: req.TraceId,
+	})
+
+	re := &pb.DescribeResponse{
+		Code:    int32(res.Code),
+		Message: res.Message,
+		TraceId: res.TraceId,
+	}
+	if res.Data != nil {
+		re.Version, re.BuildDate = res.Data.Version, res.Data.BuildDate
+		re.Faults = make([]*pb.FaultDescriptor, len(res.Data.Faults))
+		for i, f := range res.Data.Faults {
+			re.Faults[i] = &pb.FaultDescriptor{
+				Target:      f.Target,
+				Fault:       f.Fault,
+				Description: f.Description,
+				Args:        make([]*pb.ArgDescriptor, len(f.Args)),
+			}
+			for j, a := range f.Args {
+				re.Faults[i].Args[j] = &pb.ArgDescriptor{
+					Name:        a.Name,
+					Type:        a.Type,
+					Default:     a.Default,
+					Description: a.Description,
+				}
+			}
+		}
+	}
+
+	return re, nil
+}
+
+func experimentToPb(exp *model.ExperimentDataUnit) *pb.Experiment {
+	re := &pb.Experiment{
+		Uid:                exp.Uid,
+		Target:             exp.Target,
+		Fault:              exp.Fault,
+		Args:               exp.Args,
+		Runtime:            exp.Runtime,
+		Status:             exp.Status,
+		Creator:            exp.Creator,
+		Timeout:            exp.Timeout,
+		Error:              exp.Error_,
+		CreateTime:         exp.CreateTime,
+		UpdateTime:         exp.UpdateTime,
+		ContainerId:        exp.ContainerId,
+		ContainerRuntime:   exp.ContainerRuntime,
+		GuardrailOverride:  exp.GuardrailOverride,
+		GuardrailViolation: exp.GuardrailViolation,
+		Intermittent:       exp.Intermittent,
+		Detail:             exp.Detail,
+	}
+
+	for _, a := range exp.Audits {
+		re.Audits = append(re.Audits, &pb.ExperimentAudit{
+			Phase:       a.Phase,
+			Kind:        a.Kind,
+			ContainerId: a.ContainerId,
+			Command:     a.Command,
+			ExitCode:    int32(a.ExitCode),
+			Output:      a.Output,
+			Error:       a.Error_,
+			StartTime:   a.StartTime,
+			EndTime:     a.EndTime,
+		})
+	}
+
+	return re
+}
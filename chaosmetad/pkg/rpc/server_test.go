@@ -0,0 +1,178 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/auth"
+	_ "github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector/network"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/rpc/pb"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "chaosmetad-rpc-test")
+	if err != nil {
+		fmt.Printf("create temp dir error: %s\n", err.Error())
+		os.Exit(1)
+	}
+
+	storage.DBPath = filepath.Join(dir, "chaosmetad.dat")
+	code := m.Run()
+	_ = os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+func newTestClient(t *testing.T) pb.ChaosmetadClient {
+	lis := bufconn.Listen(1024 * 1024)
+	s := NewServer()
+	go func() {
+		_ = s.Serve(lis)
+	}()
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.Dial("bufnet", grpc.WithContextDialer(func(ctx context.Context, s string) (net.Conn, error) {
+		return lis.DialContext(ctx)
+	}), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("dial error: %s", err.Error())
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+	})
+
+	return pb.NewChaosmetadClient(conn)
+}
+
+func TestServer_Describe(t *testing.T) {
+	client := newTestClient(t)
+	tests := []struct {
+		name     string
+		req      *pb.DescribeRequest
+		wantCode int32
+		wantArg  string
+	}{
+		{"all", &pb.DescribeRequest{}, errutil.NoErr, ""},
+		{"by fault", &pb.DescribeRequest{Target: "network", Fault: "delay"}, errutil.NoErr, "dst_port"},
+		{"not exist", &pb.DescribeRequest{Target: "network", Fault: "none"}, errutil.BadArgsErr, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := client.Describe(context.Background(), tt.req)
+			if err != nil {
+				t.Fatalf("Describe() error = %v", err)
+			}
+			if res.Code != tt.wantCode {
+				t.Fatalf("Describe() code = %d, want %d, msg: %s", res.Code, tt.wantCode, res.Message)
+			}
+			if tt.wantCode != errutil.NoErr {
+				return
+			}
+			if len(res.Faults) == 0 {
+				t.Fatalf("Describe() return no fault")
+			}
+			if tt.wantArg == "" {
+				return
+			}
+			for _, a := range res.Faults[0].Args {
+				if a.Name == tt.wantArg {
+					return
+				}
+			}
+			t.Errorf("Describe() args of %s-%s do not contain %s", tt.req.Target, tt.req.Fault, tt.wantArg)
+		})
+	}
+}
+
+func TestServer_InjectBadArgs(t *testing.T) {
+	client := newTestClient(t)
+	res, err := client.Inject(context.Background(), &pb.InjectRequest{Target: "network", Fault: "none", TraceId: "test"})
+	if err != nil {
+		t.Fatalf("Inject() error = %v", err)
+	}
+	if res.Code != errutil.BadArgsErr || res.TraceId != "test" {
+		t.Errorf("Inject() = %v, want code %d with trace id", res, errutil.BadArgsErr)
+	}
+}
+
+func TestServer_Auth(t *testing.T) {
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	if err := os.WriteFile(tokenPath, []byte("secret"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	auth.TokenPath = tokenPath
+	if err := auth.LoadToken(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		auth.TokenPath = ""
+		_ = auth.LoadToken()
+	}()
+
+	client := newTestClient(t)
+	tests := []struct {
+		name     string
+		token    string
+		wantCode codes.Code
+	}{
+		{"no token", "", codes.Unauthenticated},
+		{"wrong token", "Bearer xxx", codes.Unauthenticated},
+		{"valid token", "Bearer secret", codes.OK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.token != "" {
+				ctx = metadata.AppendToOutgoingContext(ctx, auth.HeaderKey, tt.token)
+			}
+
+			_, err := client.Query(ctx, &pb.QueryRequest{Limit: 1})
+			if status.Code(err) != tt.wantCode {
+				t.Errorf("unary code = %v, want %v", status.Code(err), tt.wantCode)
+			}
+
+			// the watch of authenticated client blocks until deadline
+			wCtx, cancel := context.WithTimeout(ctx, time.Second)
+			defer cancel()
+			wantStreamCode := tt.wantCode
+			if tt.wantCode == codes.OK {
+				wantStreamCode = codes.DeadlineExceeded
+			}
+
+			stream, err := client.Watch(wCtx, &pb.WatchRequest{Revision: -1})
+			if err == nil {
+				_, err = stream.Recv()
+			}
+			if status.Code(err) != wantStreamCode {
+				t.Errorf("stream code = %v, want %v", status.Code(err), wantStreamCode)
+			}
+		})
+	}
+}
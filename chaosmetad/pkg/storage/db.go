@@ -22,20 +22,44 @@ import (
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"os"
 	"path"
+	"path/filepath"
 )
 
 const storageFile = "chaosmetad.dat"
 
+// DBPath is the sqlite file used to store experiments, empty means "[run path]/chaosmetad.dat"
+var DBPath string
+
 type dbStorage struct {
 	*gorm.DB
 }
 
+func GetDBPath() string {
+	if DBPath != "" {
+		return DBPath
+	}
+
+	return path.Join(utils.GetRunPath(), storageFile)
+}
+
+// GetDBPathArgs return the args need to be passed to the child chaosmetad process to use the same db
+func GetDBPathArgs() string {
+	if DBPath == "" {
+		return ""
+	}
+
+	return fmt.Sprintf("--db-path %s", utils.ShellQuote(DBPath))
+}
+
 func newDBStorage() (*dbStorage, error) {
-	// TODO: db path can be config
-	dsn := path.Join(utils.GetRunPath(), storageFile)
+	dbPath := GetDBPath()
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		return nil, fmt.Errorf("create dir of db file[%s] error: %s", dbPath, err.Error())
+	}
 
-	dsn += "?cache=shared&loc=Local"
+	dsn := dbPath + "?cache=shared&loc=Local"
 
 	gormDB, err := gorm.Open(sqlite.Open(dsn), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
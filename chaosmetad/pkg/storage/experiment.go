@@ -46,7 +46,7 @@ func GetExperimentStore() (*experimentStore, error) {
 }
 
 func newExperimentStore(db *dbStorage) (*experimentStore, error) {
-	if err := db.AutoMigrate(&Experiment{}); err != nil {
+	if err := migrate(db); err != nil {
 		return nil, err
 	}
 
@@ -56,47 +56,124 @@ func newExperimentStore(db *dbStorage) (*experimentStore, error) {
 func (e *experimentStore) Insert(exp *Experiment) error {
 	nowTime := time.Now().Format(utils.TimeFormat)
 	exp.CreateTime, exp.UpdateTime = nowTime, nowTime
-	if err := e.db.Model(Experiment{}).
-		Create(exp).
-		Error; err != nil {
-		return err
-	}
+	return e.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(Experiment{}).
+			Create(exp).
+			Error; err != nil {
+			return err
+		}
 
-	return nil
+		return addEvent(tx, exp.Uid, exp.Status, exp.Error, nowTime)
+	})
 }
 
 func (e *experimentStore) Update(exp *Experiment) error {
 	exp.UpdateTime = time.Now().Format(utils.TimeFormat)
-	if err := e.db.Model(Experiment{}).
-		Where("uid = ?", exp.Uid).
-		Updates(exp).
-		Error; err != nil {
-		return err
-	}
+	return e.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(Experiment{}).
+			Where("uid = ?", exp.Uid).
+			Updates(exp).
+			Error; err != nil {
+			return err
+		}
 
-	return nil
+		if exp.Status == "" {
+			return nil
+		}
+
+		return addEvent(tx, exp.Uid, exp.Status, exp.Error, exp.UpdateTime)
+	})
 }
 
 func (e *experimentStore) UpdateStatus(uid, status string) error {
-	if err := e.db.Model(Experiment{}).
-		Where("uid = ?", uid).
-		Updates(Experiment{Status: status, UpdateTime: time.Now().Format(utils.TimeFormat)}).
-		Error; err != nil {
-		return err
-	}
-
-	return nil
+	return e.UpdateStatusAndErr(uid, status, "")
 }
 
 func (e *experimentStore) UpdateStatusAndErr(uid, status, errMsg string) error {
-	if err := e.db.Model(Experiment{}).
+	nowTime := time.Now().Format(utils.TimeFormat)
+	return e.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(Experiment{}).
+			Where("uid = ?", uid).
+			Updates(Experiment{Status: status, Error: errMsg, UpdateTime: nowTime}).
+			Error; err != nil {
+			return err
+		}
+
+		return addEvent(tx, uid, status, errMsg, nowTime)
+	})
+}
+
+// UpdateErr only updates the error message, so that the status changed by other process will not be overwritten.
+// An event with the current status is recorded to carry the message
+func (e *experimentStore) UpdateErr(uid, errMsg string) error {
+	nowTime := time.Now().Format(utils.TimeFormat)
+	return e.db.Transaction(func(tx *gorm.DB) error {
+		var exp = &Experiment{}
+		if err := tx.Model(Experiment{}).
+			Where("uid = ?", uid).
+			First(exp).
+			Error; err != nil {
+			return fmt.Errorf("get experiment[%s] error: %s", uid, err.Error())
+		}
+
+		if err := tx.Model(Experiment{}).
+			Where("uid = ?", uid).
+			Updates(map[string]interface{}{
+				"error":       errMsg,
+				"update_time": nowTime,
+			}).
+			Error; err != nil {
+			return err
+		}
+
+		return addEvent(tx, uid, exp.Status, errMsg, nowTime)
+	})
+}
+
+// UpdateRuntime only updates the runtime information, so that the status changed by other process will not be overwritten
+func (e *experimentStore) UpdateRuntime(uid, runtime, intermittent string) error {
+	return e.db.Model(Experiment{}).
+		Where("uid = ?", uid).
+		Updates(map[string]interface{}{
+			"runtime":      runtime,
+			"intermittent": intermittent,
+			"update_time":  time.Now().Format(utils.TimeFormat),
+		}).
+		Error
+}
+
+// addEvent record the status transition of experiment, nothing will be recorded if the status is not changed
+func addEvent(tx *gorm.DB, uid, status, errMsg, createTime string) error {
+	var exp = &Experiment{}
+	if err := tx.Model(Experiment{}).
 		Where("uid = ?", uid).
-		Updates(Experiment{Status: status, Error: errMsg, UpdateTime: time.Now().Format(utils.TimeFormat)}).
+		First(exp).
 		Error; err != nil {
-		return err
+		return fmt.Errorf("get experiment[%s] error: %s", uid, err.Error())
 	}
 
-	return nil
+	var last = &ExperimentEvent{}
+	err := tx.Model(ExperimentEvent{}).
+		Where("uid = ?", uid).
+		Order("revision DESC").
+		First(last).
+		Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return fmt.Errorf("get last event of experiment[%s] error: %s", uid, err.Error())
+	}
+
+	if err == nil && last.Status == status && last.Error == errMsg {
+		return nil
+	}
+
+	return tx.Create(&ExperimentEvent{
+		Uid:        uid,
+		Target:     exp.Target,
+		Fault:      exp.Fault,
+		Status:     status,
+		Error:      errMsg,
+		CreateTime: createTime,
+	}).Error
 }
 
 func (e *experimentStore) GetByUid(uid string) (*Experiment, error) {
@@ -158,3 +235,217 @@ func (e *experimentStore) QueryByOption(uid, status, target, fault, creator, cr,
 
 	return exps, total, nil
 }
+
+// ListAll return all experiments order by create time
+func (e *experimentStore) ListAll() ([]*Experiment, error) {
+	var exps []*Experiment
+	if err := e.db.Model(Experiment{}).
+		Order("create_time ASC").
+		Find(&exps).
+		Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return exps, nil
+}
+
+func (e *experimentStore) ListByStatus(status string) ([]*Experiment, error) {
+	var exps []*Experiment
+	if err := e.db.Model(Experiment{}).
+		Where("status = ?", status).
+		Order("create_time ASC").
+		Find(&exps).
+		Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return exps, nil
+}
+
+// Import save experiments with their original time. Existed experiment will be skipped unless overwrite is true
+// Import insert the experiments with their events and audits. The events and audits of an overwritten experiment are
+// replaced, and the imported ones get new revisions and ids in the order of file
+func (e *experimentStore) Import(data *ExperimentHistory, overwrite bool) (imported, skipped int, err error) {
+	err = e.db.Transaction(func(tx *gorm.DB) error {
+		importedUid := make(map[string]bool)
+		for _, exp := range data.Experiments {
+			var count int64
+			if err := tx.Model(Experiment{}).Where("uid = ?", exp.Uid).Count(&count).Error; err != nil {
+				return fmt.Errorf("check experiment[%s] error: %s", exp.Uid, err.Error())
+			}
+
+			if count > 0 {
+				if !overwrite {
+					skipped++
+					continue
+				}
+
+				if err := tx.Where("uid = ?", exp.Uid).Delete(&Experiment{}).Error; err != nil {
+					return fmt.Errorf("delete old experiment[%s] error: %s", exp.Uid, err.Error())
+				}
+
+				if err := tx.Where("uid = ?", exp.Uid).Delete(&ExperimentEvent{}).Error; err != nil {
+					return fmt.Errorf("delete old events of experiment[%s] error: %s", exp.Uid, err.Error())
+				}
+
+				if err := tx.Where("uid = ?", exp.Uid).Delete(&ExperimentAudit{}).Error; err != nil {
+					return fmt.Errorf("delete old audits of experiment[%s] error: %s", exp.Uid, err.Error())
+				}
+			}
+
+			if err := tx.Create(exp).Error; err != nil {
+				return fmt.Errorf("insert experiment[%s] error: %s", exp.Uid, err.Error())
+			}
+			importedUid[exp.Uid] = true
+			imported++
+		}
+
+		for _, event := range data.Events {
+			if event == nil || !importedUid[event.Uid] {
+				continue
+			}
+
+			event.Revision = 0
+			if err := tx.Create(event).Error; err != nil {
+				return fmt.Errorf("insert event of experiment[%s] error: %s", event.Uid, err.Error())
+			}
+		}
+
+		for _, audit := range data.Audits {
+			if audit == nil || !importedUid[audit.Uid] {
+				continue
+			}
+
+			audit.Id = 0
+			if err := tx.Create(audit).Error; err != nil {
+				return fmt.Errorf("insert audit of experiment[%s] error: %s", audit.Uid, err.Error())
+			}
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return
+}
+
+// Prune delete the experiments in terminal status which are older than maxAge or beyond the newest maxCount.
+// maxAge <= 0 or maxCount <= 0 means no limit
+func (e *experimentStore) Prune(maxAge time.Duration, maxCount int) (int64, error) {
+	var deleted int64
+	terminal := []string{utils.StatusDestroyed, utils.StatusError}
+
+	if maxAge > 0 {
+		deadline := time.Now().Add(-maxAge).Format(utils.TimeFormat)
+		re := e.db.Where("status IN ? AND update_time < ?", terminal, deadline).Delete(&Experiment{})
+		if re.Error != nil {
+			return deleted, fmt.Errorf("prune experiments by age error: %s", re.Error.Error())
+		}
+		deleted += re.RowsAffected
+	}
+
+	if maxCount > 0 {
+		keep := e.db.Model(Experiment{}).
+			Select("uid").
+			Where("status IN ?", terminal).
+			Order("update_time DESC").
+			Limit(maxCount)
+		re := e.db.Where("status IN ? AND uid NOT IN (?)", terminal, keep).Delete(&Experiment{})
+		if re.Error != nil {
+			return deleted, fmt.Errorf("prune experiments by count error: %s", re.Error.Error())
+		}
+		deleted += re.RowsAffected
+	}
+
+	if deleted > 0 {
+		if err := e.db.Where("uid NOT IN (?)", e.db.Model(Experiment{}).Select("uid")).Delete(&ExperimentEvent{}).Error; err != nil {
+			return deleted, fmt.Errorf("prune events of deleted experiments error: %s", err.Error())
+		}
+
+		if err := e.db.Where("uid NOT IN (?)", e.db.Model(Experiment{}).Select("uid")).Delete(&ExperimentAudit{}).Error; err != nil {
+			return deleted, fmt.Errorf("prune audits of deleted experiments error: %s", err.Error())
+		}
+	}
+
+	return deleted, nil
+}
+
+// ListEvents return the events whose revision is larger than revision in ascending order. limit <= 0 means no limit
+func (e *experimentStore) ListEvents(revision int64, uid, target, fault string, limit int) ([]*ExperimentEvent, error) {
+	var events []*ExperimentEvent
+	db := e.db.Model(ExperimentEvent{}).Where("revision > ?", revision)
+
+	if uid != "" {
+		db = db.Where("uid = ?", uid)
+	}
+
+	if target != "" {
+		db = db.Where("target = ?", target)
+	}
+
+	if fault != "" {
+		db = db.Where("fault = ?", fault)
+	}
+
+	if limit > 0 {
+		db = db.Limit(limit)
+	}
+
+	if err := db.
+		Order("revision ASC").
+		Find(&events).
+		Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// LatestRevision return the revision of the newest event, 0 means no event
+func (e *experimentStore) LatestRevision() (int64, error) {
+	var revision int64
+	if err := e.db.Model(ExperimentEvent{}).
+		Select("COALESCE(MAX(revision), 0)").
+		Scan(&revision).
+		Error; err != nil {
+		return 0, err
+	}
+
+	return revision, nil
+}
+
+func (e *experimentStore) InsertAudit(audit *ExperimentAudit) error {
+	return e.db.Model(ExperimentAudit{}).
+		Create(audit).
+		Error
+}
+
+// ListAudits return the audit trail of the experiment in the order of execution
+func (e *experimentStore) ListAudits(uid string) ([]*ExperimentAudit, error) {
+	var audits []*ExperimentAudit
+	if err := e.db.Model(ExperimentAudit{}).
+		Where("uid = ?", uid).
+		Order("id ASC").
+		Find(&audits).
+		Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return audits, nil
+}
+
+// ListAllAudits return the audit trail of all the experiments in the order of execution
+func (e *experimentStore) ListAllAudits() ([]*ExperimentAudit, error) {
+	var audits []*ExperimentAudit
+	if err := e.db.Model(ExperimentAudit{}).
+		Order("id ASC").
+		Find(&audits).
+		Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	return audits, nil
+}
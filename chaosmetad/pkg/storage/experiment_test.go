@@ -0,0 +1,243 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package storage
+
+import (
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newTestStore(t *testing.T) *experimentStore {
+	DBPath, globalExpStorage = filepath.Join(t.TempDir(), storageFile), nil
+	t.Cleanup(func() {
+		DBPath, globalExpStorage = "", nil
+	})
+
+	db, err := GetExperimentStore()
+	if err != nil {
+		t.Fatalf("GetExperimentStore() error = %v", err)
+	}
+
+	return db
+}
+
+func TestMigrate(t *testing.T) {
+	db := newTestStore(t)
+	if err := migrate(db.db); err != nil {
+		t.Fatalf("migrate() again error = %v", err)
+	}
+
+	var versions []SchemaMigration
+	if err := db.db.Order("version").Find(&versions).Error; err != nil {
+		t.Fatalf("query schema migration error = %v", err)
+	}
+
+	if len(versions) != len(migrations) || versions[len(versions)-1].Version != SchemaVersion() {
+		t.Errorf("schema migrations = %v, want %d versions", versions, len(migrations))
+	}
+}
+
+func TestPrune(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name     string
+		maxAge   time.Duration
+		maxCount int
+		wantLeft []string
+	}{
+		{name: "age", maxAge: time.Hour, wantLeft: []string{"running-old", "destroyed-new", "error-new"}},
+		{name: "count", maxCount: 1, wantLeft: []string{"running-old", "destroyed-new"}},
+		{name: "age and count", maxAge: time.Hour, maxCount: 3, wantLeft: []string{"running-old", "destroyed-new", "error-new"}},
+		{name: "none", wantLeft: []string{"running-old", "destroyed-old", "error-old", "destroyed-new", "error-new"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := newTestStore(t)
+			oldTime, newTime := now.Add(-2*time.Hour).Format(utils.TimeFormat), now.Format(utils.TimeFormat)
+			errTime := now.Add(-time.Minute).Format(utils.TimeFormat)
+			exps := []*Experiment{
+				{Uid: "running-old", Status: utils.StatusSuccess, CreateTime: oldTime, UpdateTime: oldTime},
+				{Uid: "destroyed-old", Status: utils.StatusDestroyed, CreateTime: oldTime, UpdateTime: oldTime},
+				{Uid: "error-old", Status: utils.StatusError, CreateTime: oldTime, UpdateTime: oldTime},
+				{Uid: "destroyed-new", Status: utils.StatusDestroyed, CreateTime: newTime, UpdateTime: newTime},
+				{Uid: "error-new", Status: utils.StatusError, CreateTime: errTime, UpdateTime: errTime},
+			}
+			if _, _, err := db.Import(&ExperimentHistory{Experiments: exps}, false); err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+
+			if _, err := db.Prune(tt.maxAge, tt.maxCount); err != nil {
+				t.Fatalf("Prune() error = %v", err)
+			}
+
+			left, err := db.ListAll()
+			if err != nil {
+				t.Fatalf("ListAll() error = %v", err)
+			}
+
+			if len(left) != len(tt.wantLeft) {
+				t.Fatalf("Prune() left %d experiments, want %d", len(left), len(tt.wantLeft))
+			}
+			for _, uid := range tt.wantLeft {
+				if _, err := db.GetByUid(uid); err != nil {
+					t.Errorf("Prune() deleted %s unexpectedly", uid)
+				}
+			}
+		})
+	}
+}
+
+func TestImport(t *testing.T) {
+	db := newTestStore(t)
+	exp := &Experiment{Uid: "import-test", Status: utils.StatusDestroyed, CreateTime: "2023-01-01 00:00:00", UpdateTime: "2023-01-01 00:00:00"}
+	newHistory := func(errMsg string) *ExperimentHistory {
+		return &ExperimentHistory{
+			Experiments: []*Experiment{exp},
+			Events: []*ExperimentEvent{
+				{Revision: 7, Uid: exp.Uid, Status: utils.StatusCreated, Error: errMsg},
+				{Revision: 8, Uid: exp.Uid, Status: utils.StatusDestroyed, Error: errMsg},
+				{Revision: 9, Uid: "not-imported", Status: utils.StatusCreated},
+			},
+			Audits: []*ExperimentAudit{{Id: 3, Uid: exp.Uid, Command: "echo", Error: errMsg}},
+		}
+	}
+
+	if imported, skipped, err := db.Import(newHistory(""), false); err != nil || imported != 1 || skipped != 0 {
+		t.Fatalf("Import() = %d, %d, %v, want 1, 0, nil", imported, skipped, err)
+	}
+
+	if imported, skipped, err := db.Import(newHistory("skipped"), false); err != nil || imported != 0 || skipped != 1 {
+		t.Fatalf("Import() again = %d, %d, %v, want 0, 1, nil", imported, skipped, err)
+	}
+
+	exp.Error = "overwrite"
+	if imported, _, err := db.Import(newHistory("overwrite"), true); err != nil || imported != 1 {
+		t.Fatalf("Import() overwrite = %d, %v, want 1, nil", imported, err)
+	}
+
+	got, err := db.GetByUid(exp.Uid)
+	if err != nil {
+		t.Fatalf("GetByUid() error = %v", err)
+	}
+
+	if got.Error != "overwrite" || got.CreateTime != exp.CreateTime {
+		t.Errorf("GetByUid() = %+v, want error and create time kept", got)
+	}
+
+	events, err := db.ListEvents(0, "", "", "", 0)
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+
+	if len(events) != 2 || events[0].Status != utils.StatusCreated || events[1].Error != "overwrite" || events[1].Revision <= events[0].Revision {
+		t.Errorf("ListEvents() = %+v, want the 2 events of overwrite", events)
+	}
+
+	audits, err := db.ListAllAudits()
+	if err != nil {
+		t.Fatalf("ListAllAudits() error = %v", err)
+	}
+
+	if len(audits) != 1 || audits[0].Error != "overwrite" {
+		t.Errorf("ListAllAudits() = %+v, want the audit of overwrite", audits)
+	}
+}
+
+func TestListEvents(t *testing.T) {
+	db := newTestStore(t)
+	exp := &Experiment{Uid: "event-test", Target: "cpu", Fault: "burn", Status: utils.StatusCreated}
+	if err := db.Insert(exp); err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+
+	exp.Status = utils.StatusSuccess
+	if err := db.Update(exp); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	if err := db.UpdateStatus(exp.Uid, utils.StatusSuccess); err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+
+	if err := db.UpdateStatus(exp.Uid, utils.StatusDestroyed); err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+
+	events, err := db.ListEvents(0, exp.Uid, "", "", 0)
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+
+	wantStatus := []string{utils.StatusCreated, utils.StatusSuccess, utils.StatusDestroyed}
+	if len(events) != len(wantStatus) {
+		t.Fatalf("ListEvents() got %d events, want %d", len(events), len(wantStatus))
+	}
+	for i, event := range events {
+		if event.Status != wantStatus[i] || event.Target != exp.Target || event.Fault != exp.Fault {
+			t.Errorf("ListEvents()[%d] = %+v, want status %s", i, event, wantStatus[i])
+		}
+	}
+
+	resumed, err := db.ListEvents(events[0].Revision, "", "cpu", "burn", 0)
+	if err != nil {
+		t.Fatalf("ListEvents() resume error = %v", err)
+	}
+
+	if len(resumed) != 2 || resumed[0].Revision != events[1].Revision {
+		t.Errorf("ListEvents() resume = %v, want the last 2 events", resumed)
+	}
+
+	latest, err := db.LatestRevision()
+	if err != nil || latest != events[2].Revision {
+		t.Errorf("LatestRevision() = %d, %v, want %d", latest, err, events[2].Revision)
+	}
+}
+
+func TestUpdateErr(t *testing.T) {
+	db := newTestStore(t)
+	if err := db.Insert(&Experiment{Uid: "update-err", Status: utils.StatusSuccess}); err != nil {
+		t.Fatalf("Insert() error = %v", err)
+	}
+
+	// the status recorded by recovery must not be overwritten
+	if err := db.UpdateStatus("update-err", utils.StatusDestroyed); err != nil {
+		t.Fatalf("UpdateStatus() error = %v", err)
+	}
+
+	if err := db.UpdateErr("update-err", "aborted by watchdog"); err != nil {
+		t.Fatalf("UpdateErr() error = %v", err)
+	}
+
+	exp, err := db.GetByUid("update-err")
+	if err != nil {
+		t.Fatalf("GetByUid() error = %v", err)
+	}
+
+	if exp.Status != utils.StatusDestroyed || exp.Error != "aborted by watchdog" {
+		t.Errorf("UpdateErr() got status %s and error %s", exp.Status, exp.Error)
+	}
+
+	events, err := db.ListEvents(0, "update-err", "", "", 0)
+	if err != nil {
+		t.Fatalf("ListEvents() error = %v", err)
+	}
+
+	if last := events[len(events)-1]; last.Status != utils.StatusDestroyed || last.Error != "aborted by watchdog" {
+		t.Errorf("last event = %+v, want the reason recorded", last)
+	}
+}
@@ -0,0 +1,203 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package storage
+
+import (
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"gorm.io/gorm"
+	"time"
+)
+
+// SchemaMigration records the schema versions which have been applied to the db
+type SchemaMigration struct {
+	Version     int    `gorm:"primary_key" json:"version"`
+	Description string `json:"description"`
+	CreateTime  string `json:"create_time"`
+}
+
+type migration struct {
+	version     int
+	description string
+	migrate     func(tx *gorm.DB) error
+}
+
+// migrations must be appended in ascending order of version, and an applied migration must never be changed.
+// each migration uses its own snapshot of table struct, so that it does not change with the latest struct.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "create experiments table",
+		migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&experimentV1{})
+		},
+	},
+	{
+		version:     2,
+		description: "create experiment_events table",
+		migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&experimentEventV2{})
+		},
+	},
+	{
+		version:     3,
+		description: "add guardrail columns to experiments table",
+		migrate: func(tx *gorm.DB) error {
+			return addColumns(tx, &experimentV3{}, "GuardrailOverride", "GuardrailViolation")
+		},
+	},
+	{
+		version:     4,
+		description: "create experiment_audits table",
+		migrate: func(tx *gorm.DB) error {
+			return tx.AutoMigrate(&experimentAuditV4{})
+		},
+	},
+	{
+		version:     5,
+		description: "add intermittent column to experiments table",
+		migrate: func(tx *gorm.DB) error {
+			return addColumns(tx, &experimentV5{}, "Intermittent")
+		},
+	},
+}
+
+type experimentV1 struct {
+	Uid              string `gorm:"primary_key"`
+	Target           string `gorm:"index:target"`
+	Fault            string `gorm:"index:fault"`
+	Args             string
+	Runtime          string
+	Timeout          string
+	Status           string `gorm:"index:status"`
+	Creator          string `gorm:"index:creator"`
+	Error            string
+	CreateTime       string
+	UpdateTime       string
+	ContainerId      string
+	ContainerRuntime string
+}
+
+func (experimentV1) TableName() string {
+	return "experiments"
+}
+
+type experimentEventV2 struct {
+	Revision   int64  `gorm:"primary_key;autoIncrement"`
+	Uid        string `gorm:"index:event_uid"`
+	Target     string
+	Fault      string
+	Status     string
+	Error      string
+	CreateTime string
+}
+
+func (experimentEventV2) TableName() string {
+	return "experiment_events"
+}
+
+type experimentV3 struct {
+	experimentV1
+	GuardrailOverride  bool
+	GuardrailViolation string
+}
+
+func (experimentV3) TableName() string {
+	return "experiments"
+}
+
+type experimentAuditV4 struct {
+	Id          int64  `gorm:"primary_key;autoIncrement"`
+	Uid         string `gorm:"index:audit_uid"`
+	Phase       string
+	Kind        string
+	ContainerId string
+	Command     string
+	ExitCode    int
+	Output      string
+	Error       string
+	StartTime   string
+	EndTime     string
+}
+
+func (experimentAuditV4) TableName() string {
+	return "experiment_audits"
+}
+
+type experimentV5 struct {
+	experimentV3
+	Intermittent string
+}
+
+func (experimentV5) TableName() string {
+	return "experiments"
+}
+
+// SchemaVersion is the latest schema version of this binary
+func SchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+func migrate(db *dbStorage) error {
+	if err := db.AutoMigrate(&SchemaMigration{}); err != nil {
+		return fmt.Errorf("create schema migration table error: %s", err.Error())
+	}
+
+	var current int
+	if err := db.Model(&SchemaMigration{}).Select("COALESCE(MAX(version), 0)").Scan(&current).Error; err != nil {
+		return fmt.Errorf("get current schema version error: %s", err.Error())
+	}
+
+	if current > SchemaVersion() {
+		return fmt.Errorf("schema version of db[%d] is newer than the binary[%d]", current, SchemaVersion())
+	}
+
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+
+		if err := db.Transaction(func(tx *gorm.DB) error {
+			if err := m.migrate(tx); err != nil {
+				return err
+			}
+
+			return tx.Create(&SchemaMigration{
+				Version:     m.version,
+				Description: m.description,
+				CreateTime:  time.Now().Format(utils.TimeFormat),
+			}).Error
+		}); err != nil {
+			return fmt.Errorf("migrate schema to version[%d] error: %s", m.version, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func addColumns(tx *gorm.DB, table interface{}, fields ...string) error {
+	for _, field := range fields {
+		if tx.Migrator().HasColumn(table, field) {
+			continue
+		}
+
+		if err := tx.Migrator().AddColumn(table, field); err != nil {
+			return fmt.Errorf("add column[%s] error: %s", field, err.Error())
+		}
+	}
+
+	return nil
+}
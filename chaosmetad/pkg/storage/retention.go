@@ -0,0 +1,105 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package storage
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"time"
+)
+
+const DefaultRetentionInterval = "1h"
+
+// RetentionPolicy only applies to experiments in terminal status: destroyed, error
+type RetentionPolicy struct {
+	MaxAge   string
+	MaxCount int
+	Interval string
+}
+
+func (p *RetentionPolicy) IsEnabled() bool {
+	return p.MaxAge != "" || p.MaxCount > 0
+}
+
+func (p *RetentionPolicy) Validate() error {
+	if p.MaxAge != "" {
+		if _, err := utils.GetTimeSecond(p.MaxAge); err != nil {
+			return fmt.Errorf("\"retention-age\" is invalid: %s", err.Error())
+		}
+	}
+
+	if p.MaxCount < 0 {
+		return fmt.Errorf("\"retention-count\" can not be less than 0")
+	}
+
+	if p.Interval != "" {
+		interval, err := utils.GetTimeSecond(p.Interval)
+		if err != nil {
+			return fmt.Errorf("\"retention-interval\" is invalid: %s", err.Error())
+		}
+
+		if interval <= 0 {
+			return fmt.Errorf("\"retention-interval\" must be larger than 0")
+		}
+	}
+
+	return nil
+}
+
+// RunRetention prune experiments by the policy periodically until ctx done
+func RunRetention(ctx context.Context, p *RetentionPolicy) {
+	logger := log.GetLogger(ctx)
+	if !p.IsEnabled() {
+		return
+	}
+
+	var maxAge time.Duration
+	if p.MaxAge != "" {
+		ageSec, _ := utils.GetTimeSecond(p.MaxAge)
+		maxAge = time.Duration(ageSec) * time.Second
+	}
+
+	intervalStr := p.Interval
+	if intervalStr == "" {
+		intervalStr = DefaultRetentionInterval
+	}
+	intervalSec, _ := utils.GetTimeSecond(intervalStr)
+	ticker := time.NewTicker(time.Duration(intervalSec) * time.Second)
+	defer ticker.Stop()
+
+	logger.Infof("experiment retention start, max age: %s, max count: %d, interval: %s", p.MaxAge, p.MaxCount, intervalStr)
+	for {
+		db, err := GetExperimentStore()
+		if err != nil {
+			logger.Warnf("retention get db error: %s", err.Error())
+		} else {
+			deleted, err := db.Prune(maxAge, p.MaxCount)
+			if err != nil {
+				logger.Warnf("retention prune experiments error: %s", err.Error())
+			} else if deleted > 0 {
+				logger.Infof("retention pruned %d experiments", deleted)
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
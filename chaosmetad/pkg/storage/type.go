@@ -30,4 +30,44 @@ type Experiment struct {
 	UpdateTime       string `json:"update_time"`
 	ContainerId      string `json:"container_id"`
 	ContainerRuntime string `json:"container_runtime"`
+	// GuardrailOverride means the experiment is allowed to attack the resources protected by guardrail
+	GuardrailOverride  bool   `json:"guardrail_override"`
+	GuardrailViolation string `json:"guardrail_violation"`
+	// Intermittent is the config and cycle state of intermittent experiment, empty means the fault is injected continuously
+	Intermittent string `json:"intermittent"`
+}
+
+// ExperimentEvent is a status transition of experiment, revision increases monotonically
+type ExperimentEvent struct {
+	Revision   int64  `gorm:"primary_key;autoIncrement" json:"revision"`
+	Uid        string `gorm:"index:event_uid" json:"uid"`
+	Target     string `json:"target"`
+	Fault      string `json:"fault"`
+	Status     string `json:"status"`
+	Error      string `json:"error"`
+	CreateTime string `json:"create_time"`
+}
+
+// ExperimentAudit is an operation performed on the node for an experiment, such as command, file write, cgroup change and container exec
+type ExperimentAudit struct {
+	Id          int64  `gorm:"primary_key;autoIncrement" json:"id"`
+	Uid         string `gorm:"index:audit_uid" json:"uid"`
+	Phase       string `json:"phase"`
+	Kind        string `json:"kind"`
+	ContainerId string `json:"container_id"`
+	Command     string `json:"command"`
+	ExitCode    int    `json:"exit_code"`
+	Output      string `json:"output"`
+	Error       string `json:"error"`
+	StartTime   string `json:"start_time"`
+	EndTime     string `json:"end_time"`
+}
+
+// ExperimentHistory is the data format of "export" and "import" command
+type ExperimentHistory struct {
+	SchemaVersion int                `json:"schema_version"`
+	ExportTime    string             `json:"export_time"`
+	Experiments   []*Experiment      `json:"experiments"`
+	Events        []*ExperimentEvent `json:"events"`
+	Audits        []*ExperimentAudit `json:"audits"`
 }
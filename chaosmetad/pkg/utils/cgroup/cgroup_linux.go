@@ -19,6 +19,7 @@ package cgroup
 import (
 	"context"
 	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/audit"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/crclient"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
@@ -29,7 +30,7 @@ import (
 )
 
 func NewCgroup(ctx context.Context, cgroupPath string, configCmdStr string) error {
-	if err := cmdexec.RunBashCmdWithoutOutput(ctx, fmt.Sprintf("mkdir %s%s%s", cgroupPath, utils.CmdSplit, configCmdStr)); err != nil {
+	if err := cmdexec.RunBashCmdWithoutOutput(audit.WithKind(ctx, audit.KindCgroup), fmt.Sprintf("mkdir %s%s%s", cgroupPath, utils.CmdSplit, configCmdStr)); err != nil {
 		return err
 	}
 
@@ -137,7 +138,7 @@ func MovePidListToCgroup(ctx context.Context, pidList []int, cgroupPath string)
 }
 
 func MoveTaskToCgroup(ctx context.Context, pid int, cgroupPath string) error {
-	if err := cmdexec.RunBashCmdWithoutOutput(ctx, fmt.Sprintf("echo %d > %s/tasks", pid, cgroupPath)); err != nil {
+	if err := cmdexec.RunBashCmdWithoutOutput(audit.WithKind(ctx, audit.KindCgroup), fmt.Sprintf("echo %d > %s/tasks", pid, cgroupPath)); err != nil {
 		return err
 	}
 
@@ -177,7 +178,7 @@ func GetPidStrListByCgroup(ctx context.Context, cgroupPath string) ([]int, error
 }
 
 func RemoveCgroup(ctx context.Context, cgroupPath string) error {
-	if err := cmdexec.RunBashCmdWithoutOutput(ctx, fmt.Sprintf("rmdir %s", cgroupPath)); err != nil {
+	if err := cmdexec.RunBashCmdWithoutOutput(audit.WithKind(ctx, audit.KindCgroup), fmt.Sprintf("rmdir %s", cgroupPath)); err != nil {
 		return fmt.Errorf("cmd exec error: %s", err.Error())
 	}
 
@@ -21,8 +21,10 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/audit"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/crclient"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/containercgroup"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
@@ -120,11 +122,28 @@ func CpContainerFile(ctx context.Context, cr, containerID, src, dst string) erro
 		return fmt.Errorf("get %s client error: %s", cr, err.Error())
 	}
 
-	return client.CpFile(ctx, containerID, src, dst)
+	startTime := time.Now()
+	err = client.CpFile(ctx, containerID, src, dst)
+	audit.Record(ctx, audit.KindFile, containerID, fmt.Sprintf("cp %s %s", src, dst), audit.NoExitCode, "", err, startTime)
+	return err
 }
 
 func StartSleepRecover(ctx context.Context, sleepTime int64, uid string) error {
-	return StartBashCmd(ctx, utils.GetSleepRecoverCmd(sleepTime, uid))
+	return StartBashCmd(ctx, utils.GetSleepRecoverCmd(sleepTime, uid, storage.GetDBPathArgs()))
+}
+
+// StartIntermittent guardrailArgs is passed to the child process, which injects the fault again and again
+func StartIntermittent(ctx context.Context, uid, guardrailArgs string) error {
+	return StartBashCmd(ctx, utils.GetIntermittentCmd(uid, getChildArgs(guardrailArgs)))
+}
+
+// StartCrashLoop guardrailArgs is passed to the child process, which kills the target process again and again
+func StartCrashLoop(ctx context.Context, uid, guardrailArgs string) error {
+	return StartBashCmd(ctx, utils.GetCrashLoopCmd(uid, getChildArgs(guardrailArgs)))
+}
+
+func getChildArgs(extraArgs string) string {
+	return strings.TrimSpace(fmt.Sprintf("%s %s", storage.GetDBPathArgs(), extraArgs))
 }
 
 func waitProExec(ctx context.Context, stdout, stderr *bytes.Buffer, timeoutSec int) (err error) {
@@ -171,12 +190,22 @@ func SupportCmd(cmd string) bool {
 	return true
 }
 
+// getExitCode return the exit code of the finished process, NoExitCode if the process is not started
+func getExitCode(c *exec.Cmd) int {
+	if c.ProcessState == nil {
+		return audit.NoExitCode
+	}
+
+	return c.ProcessState.Sys().(syscall.WaitStatus).ExitStatus()
+}
+
 func RunBashCmdWithOutput(ctx context.Context, cmd string) (string, error) {
 	log.GetLogger(ctx).Debugf("run cmd with output: %s", cmd)
-	c := exec.Command("/bin/bash", "-c", cmd)
+	c, startTime := exec.Command("/bin/bash", "-c", cmd), time.Now()
 
 	reByte, err := c.CombinedOutput()
 	re := string(reByte)
+	audit.Record(ctx, audit.KindCmd, "", cmd, getExitCode(c), re, err, startTime)
 	errMsg := fmt.Sprintf("exit code: %d, output: %s, error: %v", c.ProcessState.Sys().(syscall.WaitStatus).ExitStatus(), re, err)
 	log.GetLogger(ctx).Debugf("exec result: %s", errMsg)
 	if err != nil || c.ProcessState.Sys().(syscall.WaitStatus).ExitStatus() != 0 {
@@ -196,20 +225,30 @@ func RunBashCmdWithOutput(ctx context.Context, cmd string) (string, error) {
 
 func RunBashCmdWithoutOutput(ctx context.Context, cmd string) error {
 	log.GetLogger(ctx).Debugf("run cmd: %s", cmd)
-	return exec.Command("/bin/bash", "-c", cmd).Run()
+	c, startTime := exec.Command("/bin/bash", "-c", cmd), time.Now()
+	// output is not piped: Run would otherwise wait for background children holding the pipe, e.g. "cmd &"
+	err := c.Run()
+	audit.Record(ctx, audit.KindCmd, "", cmd, getExitCode(c), "", err, startTime)
+	return err
 }
 
 func StartBashCmd(ctx context.Context, cmd string) error {
 	log.GetLogger(ctx).Debugf("start cmd: %s", cmd)
-	return exec.Command("/bin/bash", "-c", cmd).Start()
+	startTime := time.Now()
+	err := exec.Command("/bin/bash", "-c", cmd).Start()
+	audit.Record(ctx, audit.KindCmd, "", cmd, audit.NoExitCode, "", err, startTime)
+	return err
 }
 
-func StartBashCmdAndWaitPid(ctx context.Context, cmd string, timeoutSec int) (int, error) {
+func StartBashCmdAndWaitPid(ctx context.Context, cmd string, timeoutSec int) (pid int, err error) {
 	log.GetLogger(ctx).Debugf("start cmd: %s", cmd)
 
-	c := exec.Command("/bin/bash", "-c", cmd)
+	c, startTime := exec.Command("/bin/bash", "-c", cmd), time.Now()
 	var stdout, stderr bytes.Buffer
 	c.Stdout, c.Stderr = &stdout, &stderr
+	defer func() {
+		audit.Record(ctx, audit.KindCmd, "", cmd, audit.NoExitCode, "", err, startTime)
+	}()
 
 	if err := c.Start(); err != nil {
 		return utils.NoPid, fmt.Errorf("cmd start error: %s", err.Error())
@@ -222,12 +261,16 @@ func StartBashCmdAndWaitPid(ctx context.Context, cmd string, timeoutSec int) (in
 	return c.Process.Pid, nil
 }
 
-func StartBashCmdAndWaitByUser(ctx context.Context, cmd, user string) error {
+func StartBashCmdAndWaitByUser(ctx context.Context, cmd, user string) (err error) {
 	log.GetLogger(ctx).Debugf("user: %s, start cmd: %s", user, cmd)
 
-	c := exec.Command("runuser", "-l", user, "-c", cmd)
+	c, startTime := exec.Command("runuser", "-l", user, "-c", cmd), time.Now()
 	var stdout, stderr bytes.Buffer
 	c.Stdout, c.Stderr = &stdout, &stderr
+	defer func() {
+		audit.Record(ctx, audit.KindCmd, "", fmt.Sprintf("runuser -l %s -c %s", user, cmd), audit.NoExitCode, "", err, startTime)
+	}()
+
 	if err := c.Start(); err != nil {
 		return fmt.Errorf("cmd start error: %s", err.Error())
 	}
@@ -241,7 +284,7 @@ func StartBashCmdAndWaitByUser(ctx context.Context, cmd, user string) error {
 
 // finish: false[wait success], true[finish and get all output]
 
-func ExecContainer(ctx context.Context, cr, containerID string, namespaces []string, cmd string, method string) (string, error) {
+func ExecContainer(ctx context.Context, cr, containerID string, namespaces []string, cmd string, method string) (re string, err error) {
 	logger := log.GetLogger(ctx)
 
 	// get container's init process
@@ -262,12 +305,21 @@ func ExecContainer(ctx context.Context, cr, containerID string, namespaces []str
 	var stdout, stderr bytes.Buffer
 	c.Stdout, c.Stderr = &stdout, &stderr
 	logger.Debugf("container exec cmd: %s", c.Args)
+
+	var startTime, exitCode, output = time.Now(), audit.NoExitCode, ""
+	defer func() {
+		audit.Record(ctx, audit.KindContainerExec, containerID, cmd, exitCode, output, err, startTime)
+	}()
+
 	if err := c.Start(); err != nil {
 		return "", fmt.Errorf("start process error: %s", err.Error())
 	}
 
 	// set cgroup for new process
-	if err := containercgroup.AddToProCgroup(c.Process.Pid, targetPid); err != nil {
+	cgroupStartTime, cgroupErr := time.Now(), containercgroup.AddToProCgroup(c.Process.Pid, targetPid)
+	audit.Record(audit.WithKind(ctx, audit.KindCgroup), audit.KindCgroup, containerID,
+		fmt.Sprintf("add process[%d] to cgroup of process[%d]", c.Process.Pid, targetPid), audit.NoExitCode, "", cgroupErr, cgroupStartTime)
+	if err := cgroupErr; err != nil {
 		if err := c.Process.Kill(); err != nil {
 			logger.Warnf("undo: kill container exec process[%d] error: %s", c.Process.Pid, err.Error())
 		}
@@ -288,6 +340,7 @@ func ExecContainer(ctx context.Context, cr, containerID string, namespaces []str
 	case ExecRun:
 		err = c.Wait()
 		combinedOutput := stdout.String() + stderr.String()
+		exitCode, output = getExitCode(c), combinedOutput
 		errMsg := fmt.Sprintf("exit code: %d, output: %s， err: %v", c.ProcessState.Sys().(syscall.WaitStatus).ExitStatus(), combinedOutput, err)
 		logger.Debugf("container exec result: %s", errMsg)
 		if err != nil || c.ProcessState.Sys().(syscall.WaitStatus).ExitStatus() != 0 {
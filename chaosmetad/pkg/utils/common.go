@@ -36,6 +36,9 @@ const (
 	RootName   = "chaosmetad"
 	TimeFormat = "2006-01-02 15:04:05"
 	RecoverLog = "/tmp/chaosmetad_recover.log"
+
+	IntermittentLog = "/tmp/chaosmetad_intermittent.log"
+	CrashLoopLog    = "/tmp/chaosmetad_crashloop.log"
 )
 
 // TraceId for command line
@@ -116,8 +119,24 @@ func GetContainerPath(tool string) string {
 	return fmt.Sprintf("/tmp/%s", tool)
 }
 
-func GetSleepRecoverCmd(sleepTime int64, uid string) string {
-	return fmt.Sprintf("sleep %ds; %s/%s recover %s >> %s 2>&1", sleepTime, GetRunPath(), RootName, uid, RecoverLog)
+// GetSleepRecoverCmd extraArgs will be appended after uid, so that the recover process still can be grep by "chaosmetad recover"
+func GetSleepRecoverCmd(sleepTime int64, uid, extraArgs string) string {
+	return fmt.Sprintf("sleep %ds; %s/%s recover %s %s >> %s 2>&1", sleepTime, GetRunPath(), RootName, uid, extraArgs, RecoverLog)
+}
+
+// GetIntermittentCmd return the cmd to start the background process which cycles the experiment between inject and recover
+func GetIntermittentCmd(uid, extraArgs string) string {
+	return fmt.Sprintf("%s/%s intermittent %s %s >> %s 2>&1", GetRunPath(), RootName, uid, extraArgs, IntermittentLog)
+}
+
+// GetCrashLoopCmd return the cmd to start the background process which keeps killing the target processes
+func GetCrashLoopCmd(uid, extraArgs string) string {
+	return fmt.Sprintf("%s/%s crashloop %s %s >> %s 2>&1", GetRunPath(), RootName, uid, extraArgs, CrashLoopLog)
+}
+
+// ShellQuote quote the string as a single word of bash, so that the spaces and metacharacters in it are not interpreted
+func ShellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
 }
 
 func GetTraceId(ctx context.Context) string {
@@ -17,6 +17,7 @@
 package utils
 
 import (
+	"os/exec"
 	"reflect"
 	"testing"
 )
@@ -218,3 +219,26 @@ func TestIsValidUid(t *testing.T) {
 		})
 	}
 }
+
+func TestShellQuote(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+	}{
+		{name: "plain", s: "/var/lib/chaosmetad/chaosmetad.dat"},
+		{name: "space", s: "/tmp/chaos meta/chaosmetad.dat"},
+		{name: "metacharacters", s: "/tmp/a;touch /tmp/pwned $(id) `id` && b"},
+		{name: "single quote", s: "/tmp/it's/db"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := exec.Command("bash", "-c", "printf %s "+ShellQuote(tt.s)).Output()
+			if err != nil {
+				t.Fatalf("run bash error: %s", err.Error())
+			}
+			if string(out) != tt.s {
+				t.Errorf("ShellQuote() is interpreted as %q, want %q", string(out), tt.s)
+			}
+		})
+	}
+}
@@ -35,6 +35,29 @@ func CheckSpeedValue(sp string) error {
 	return nil
 }
 
+// GetBitRate return the bits per second of the speed value in the unit of tc, eg: 100mbit, 1kbit = 1000bit
+func GetBitRate(sp string) (int64, error) {
+	value, unit, err := getValueAndUnit(sp)
+	if err != nil {
+		return -1, err
+	}
+
+	switch unit {
+	case "bit", "":
+		return value, nil
+	case "kbit":
+		return value * 1000, nil
+	case "mbit":
+		return value * 1000 * 1000, nil
+	case "gbit":
+		return value * 1000 * 1000 * 1000, nil
+	case "tbit":
+		return value * 1000 * 1000 * 1000 * 1000, nil
+	}
+
+	return -1, fmt.Errorf("unit %s is not support", unit)
+}
+
 func CheckTimeValue(timeStr string) error {
 	_, unit, err := getValueAndUnit(timeStr)
 	if err != nil {
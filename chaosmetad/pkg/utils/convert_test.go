@@ -243,3 +243,54 @@ func TestCheckSpeedValue(t *testing.T) {
 		})
 	}
 }
+
+func TestGetBitRate(t *testing.T) {
+	tests := []struct {
+		name    string
+		sp      string
+		want    int64
+		wantErr bool
+	}{
+		{
+			name: "missing unit",
+			sp:   "100",
+			want: 100,
+		},
+		{
+			name: "kbit",
+			sp:   "8kbit",
+			want: 8000,
+		},
+		{
+			name: "upper case",
+			sp:   "10Mbit",
+			want: 10000000,
+		},
+		{
+			name: "gbit",
+			sp:   "1gbit",
+			want: 1000000000,
+		},
+		{
+			name:    "byte unit",
+			sp:      "100mb",
+			wantErr: true,
+		},
+		{
+			name:    "empty value",
+			sp:      "mbit",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := GetBitRate(tt.sp)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("GetBitRate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err == nil && got != tt.want {
+				t.Errorf("GetBitRate() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
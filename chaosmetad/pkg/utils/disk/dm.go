@@ -0,0 +1,193 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disk
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const (
+	DMTargetLinear = "linear"
+	DMTargetDelay  = "delay"
+	DMTargetFlakey = "flakey"
+)
+
+// DMTarget is a line of device-mapper table: "<start> <length> <type> <args...>", the unit of start and length is 512B sector
+type DMTarget struct {
+	Start  uint64
+	Length uint64
+	Type   string
+	Args   []string
+}
+
+func (t DMTarget) String() string {
+	return strings.Join(append([]string{strconv.FormatUint(t.Start, 10), strconv.FormatUint(t.Length, 10), t.Type}, t.Args...), " ")
+}
+
+func FormatDMTable(targets []DMTarget) string {
+	lines := make([]string, len(targets))
+	for i, t := range targets {
+		lines[i] = t.String()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func ParseDMTable(table string) ([]DMTarget, error) {
+	var targets []DMTarget
+	for _, line := range strings.Split(strings.TrimSpace(table), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if len(fields) < 3 {
+			return nil, fmt.Errorf("unknown table line: %s", line)
+		}
+
+		start, err := strconv.ParseUint(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("start of line[%s] is invalid: %s", line, err.Error())
+		}
+
+		length, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("length of line[%s] is invalid: %s", line, err.Error())
+		}
+
+		targets = append(targets, DMTarget{Start: start, Length: length, Type: fields[2], Args: fields[3:]})
+	}
+
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("table is empty")
+	}
+
+	return targets, nil
+}
+
+// GetMountDevice find the mount which the path belongs to from a mountinfo file, eg: /proc/self/mountinfo.
+// Return the mount point and the device number "major:minor"
+func GetMountDevice(mountInfoPath, path string) (string, string, error) {
+	f, err := os.Open(mountInfoPath)
+	if err != nil {
+		return "", "", fmt.Errorf("open %s error: %s", mountInfoPath, err.Error())
+	}
+	defer f.Close()
+
+	return getMountDevice(bufio.NewScanner(f), filepath.Clean(path))
+}
+
+// getMountDevice use the longest mount point containing the path, the later one wins if mounted on the same point
+func getMountDevice(scanner *bufio.Scanner, path string) (string, string, error) {
+	var mountPoint, devNum string
+	for scanner.Scan() {
+		// 36 35 98:0 /mnt1 /mnt2 rw,noatime master:1 - ext3 /dev/root rw,errors=continue
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 5 {
+			continue
+		}
+
+		point := unescapeMountPath(fields[4])
+		if !isUnderPath(point, path) || len(point) < len(mountPoint) {
+			continue
+		}
+
+		mountPoint, devNum = point, fields[2]
+	}
+
+	if err := scanner.Err(); err != nil {
+		return "", "", fmt.Errorf("read mount info error: %s", err.Error())
+	}
+
+	if mountPoint == "" {
+		return "", "", fmt.Errorf("no mount found for %s", path)
+	}
+
+	return mountPoint, devNum, nil
+}
+
+func isUnderPath(parent, path string) bool {
+	return parent == "/" || path == parent || strings.HasPrefix(path, parent+"/")
+}
+
+// unescapeMountPath restore the space, tab, newline and backslash escaped as octal in mountinfo
+func unescapeMountPath(path string) string {
+	if !strings.Contains(path, "\\") {
+		return path
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		if path[i] == '\\' && i+3 < len(path) {
+			if c, err := strconv.ParseUint(path[i+1:i+4], 8, 8); err == nil {
+				b.WriteByte(byte(c))
+				i += 3
+				continue
+			}
+		}
+		b.WriteByte(path[i])
+	}
+
+	return b.String()
+}
+
+// GetDMName return the device-mapper name of device number "major:minor", error if it is not a device-mapper device
+func GetDMName(devNum string) (string, error) {
+	nameBytes, err := os.ReadFile(fmt.Sprintf("/sys/dev/block/%s/dm/name", devNum))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", fmt.Errorf("device[%s] is not a device-mapper device", devNum)
+		}
+		return "", fmt.Errorf("read name of device[%s] error: %s", devNum, err.Error())
+	}
+
+	return strings.TrimSpace(string(nameBytes)), nil
+}
+
+func GetDMTable(ctx context.Context, name string) ([]DMTarget, error) {
+	re, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("dmsetup table %s", name))
+	if err != nil {
+		return nil, fmt.Errorf("get table of %s error: %s", name, err.Error())
+	}
+
+	return ParseDMTable(re)
+}
+
+// ReloadDMTable replace the live table of device without unmounting, the in-flight I/O is finished by the old table
+func ReloadDMTable(ctx context.Context, name string, targets []DMTarget) error {
+	// multi-line table can only be read from stdin
+	if _, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("dmsetup load %s <<'EOF'\n%s\nEOF", name, FormatDMTable(targets))); err != nil {
+		return fmt.Errorf("load table of %s error: %s", name, err.Error())
+	}
+
+	if _, err := cmdexec.RunBashCmdWithOutput(ctx, fmt.Sprintf("dmsetup resume %s", name)); err != nil {
+		if err := cmdexec.RunBashCmdWithoutOutput(ctx, fmt.Sprintf("dmsetup clear %s", name)); err != nil {
+			log.GetLogger(ctx).Warnf("undo: clear inactive table of %s error: %s", name, err.Error())
+		}
+		return fmt.Errorf("resume %s error: %s", name, err.Error())
+	}
+
+	return nil
+}
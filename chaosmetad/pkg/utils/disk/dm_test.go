@@ -0,0 +1,91 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package disk
+
+import (
+	"bufio"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseDMTable(t *testing.T) {
+	tests := []struct {
+		name    string
+		table   string
+		want    []DMTarget
+		wantErr bool
+	}{
+		{"empty", "", nil, true},
+		{"linear", "0 204800 linear 7:0 2048\n", []DMTarget{{0, 204800, DMTargetLinear, []string{"7:0", "2048"}}}, false},
+		{"multi line", "0 1024 linear 7:0 0\n1024 2048 linear 7:1 0", []DMTarget{
+			{0, 1024, DMTargetLinear, []string{"7:0", "0"}},
+			{1024, 2048, DMTargetLinear, []string{"7:1", "0"}},
+		}, false},
+		{"flakey", "0 1024 flakey 7:0 0 5 5 1 error_writes", []DMTarget{{0, 1024, DMTargetFlakey, []string{"7:0", "0", "5", "5", "1", "error_writes"}}}, false},
+		{"short line", "0 1024", nil, true},
+		{"bad length", "0 abc linear 7:0 0", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDMTable(tt.table)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDMTable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDMTable() = %v, want %v", got, tt.want)
+			}
+			if err == nil && FormatDMTable(got) != strings.TrimSpace(tt.table) {
+				t.Errorf("FormatDMTable() = %q, want %q", FormatDMTable(got), strings.TrimSpace(tt.table))
+			}
+		})
+	}
+}
+
+func TestGetMountDevice(t *testing.T) {
+	mountInfo := `22 1 253:0 / / rw,relatime shared:1 - ext4 /dev/mapper/vg-root rw
+23 22 0:21 / /proc rw,nosuid - proc proc rw
+40 22 253:2 / /data rw,relatime shared:20 - xfs /dev/mapper/vg-data rw
+41 40 253:3 / /data/log rw,relatime shared:21 - xfs /dev/mapper/vg-log rw
+42 22 253:4 / /mnt/my\040disk rw,relatime - ext4 /dev/mapper/vg-disk rw
+43 22 7:0 / /data2 rw - ext4 /dev/loop0 rw
+44 22 253:5 / /data2 rw - ext4 /dev/mapper/vg-new rw`
+
+	tests := []struct {
+		name      string
+		path      string
+		wantPoint string
+		wantDev   string
+	}{
+		{"root", "/etc", "/", "253:0"},
+		{"mount point", "/data", "/data", "253:2"},
+		{"nested", "/data/log/app", "/data/log", "253:3"},
+		{"prefix only", "/data-bak", "/", "253:0"},
+		{"escaped", "/mnt/my disk/a", "/mnt/my disk", "253:4"},
+		{"overmounted", "/data2/a", "/data2", "253:5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			point, dev, err := getMountDevice(bufio.NewScanner(strings.NewReader(mountInfo)), tt.path)
+			if err != nil {
+				t.Fatalf("getMountDevice() error = %v", err)
+			}
+			if point != tt.wantPoint || dev != tt.wantDev {
+				t.Errorf("getMountDevice() = %s %s, want %s %s", point, dev, tt.wantPoint, tt.wantDev)
+			}
+		})
+	}
+}
@@ -0,0 +1,93 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package disk
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// The fault injection framework of kernel fails each request of the block devices with "make-it-fail" set by the
+// probability of fail_make_request. It needs CONFIG_FAIL_MAKE_REQUEST and debugfs mounted on /sys/kernel/debug.
+// The config of fail_make_request is shared by all the devices.
+
+const FailRequestDir = "/sys/kernel/debug/fail_make_request"
+
+// FailRequestConfig the raw values of the files in FailRequestDir
+type FailRequestConfig struct {
+	Probability string `json:"probability"`
+	Interval    string `json:"interval"`
+	Times       string `json:"times"`
+	Verbose     string `json:"verbose"`
+}
+
+func (c *FailRequestConfig) files() map[string]string {
+	return map[string]string{"probability": c.Probability, "interval": c.Interval, "times": c.Times, "verbose": c.Verbose}
+}
+
+func GetFailRequestConfig() (*FailRequestConfig, error) {
+	c := &FailRequestConfig{}
+	for name, value := range map[string]*string{"probability": &c.Probability, "interval": &c.Interval, "times": &c.Times, "verbose": &c.Verbose} {
+		re, err := os.ReadFile(filepath.Join(FailRequestDir, name))
+		if err != nil {
+			return nil, fmt.Errorf("read %s of fail_make_request error: %s", name, err.Error())
+		}
+		*value = strings.TrimSpace(string(re))
+	}
+
+	return c, nil
+}
+
+// SetFailRequestConfig the probability is written last, so that no request fails by the config half written
+func SetFailRequestConfig(c *FailRequestConfig) error {
+	files := c.files()
+	for _, name := range []string{"interval", "times", "verbose", "probability"} {
+		if err := os.WriteFile(filepath.Join(FailRequestDir, name), []byte(files[name]), 0644); err != nil {
+			return fmt.Errorf("write %s of fail_make_request error: %s", name, err.Error())
+		}
+	}
+
+	return nil
+}
+
+func getMakeItFailPath(devNum string) string {
+	return fmt.Sprintf("/sys/dev/block/%s/make-it-fail", devNum)
+}
+
+// CheckMakeItFail return error if the device of "major:minor" does not support fault injection
+func CheckMakeItFail(devNum string) error {
+	if _, err := os.Stat(getMakeItFailPath(devNum)); err != nil {
+		return fmt.Errorf("device[%s] does not support make-it-fail: %s", devNum, err.Error())
+	}
+
+	return nil
+}
+
+func SetMakeItFail(devNum string, enable bool) error {
+	value := "0"
+	if enable {
+		value = "1"
+	}
+
+	if err := os.WriteFile(getMakeItFailPath(devNum), []byte(value), 0644); err != nil {
+		return fmt.Errorf("set make-it-fail of device[%s] to %s error: %s", devNum, value, err.Error())
+	}
+
+	return nil
+}
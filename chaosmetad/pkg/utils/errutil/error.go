@@ -31,6 +31,8 @@ const (
 	InternalErr
 	RecoverErr
 	UnknownErr
+	GuardrailErr
+	AuthErr
 )
 
 const (
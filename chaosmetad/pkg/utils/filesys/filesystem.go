@@ -19,6 +19,7 @@ package filesys
 import (
 	"context"
 	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/audit"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
 	"os"
@@ -100,7 +101,7 @@ func MoveFile(ctx context.Context, cr, cId string, src, dst string) error {
 		return fmt.Errorf("\"dst\" can not be empty")
 	}
 
-	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getMoveFileCmd(src, dst), []string{namespace.MNT})
+	_, err := cmdexec.ExecCommonWithNS(audit.WithKind(ctx, audit.KindFile), cr, cId, getMoveFileCmd(src, dst), []string{namespace.MNT})
 	return err
 }
 
@@ -109,7 +110,7 @@ func RemoveFile(ctx context.Context, cr, cId string, file string) error {
 		return fmt.Errorf("\"file\" can not be empty")
 	}
 
-	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getRemoveFileCmd(file), []string{namespace.MNT})
+	_, err := cmdexec.ExecCommonWithNS(audit.WithKind(ctx, audit.KindFile), cr, cId, getRemoveFileCmd(file), []string{namespace.MNT})
 	return err
 }
 
@@ -118,7 +119,7 @@ func RemoveRF(ctx context.Context, cr, cId string, path string) error {
 		return fmt.Errorf("\"path\" can not be empty")
 	}
 
-	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getRemoveRFCmd(path), []string{namespace.MNT})
+	_, err := cmdexec.ExecCommonWithNS(audit.WithKind(ctx, audit.KindFile), cr, cId, getRemoveRFCmd(path), []string{namespace.MNT})
 	return err
 }
 
@@ -127,7 +128,7 @@ func OverWriteFile(ctx context.Context, cr, cId string, path, content string) er
 		return fmt.Errorf("\"path\" can not be empty")
 	}
 
-	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getOverWriteFileCmd(path, content), []string{namespace.MNT})
+	_, err := cmdexec.ExecCommonWithNS(audit.WithKind(ctx, audit.KindFile), cr, cId, getOverWriteFileCmd(path, content), []string{namespace.MNT})
 	return err
 }
 
@@ -136,7 +137,7 @@ func MkdirForce(ctx context.Context, cr, cId string, dir string) error {
 		return fmt.Errorf("\"dir\" can not be empty")
 	}
 
-	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getMkdirForceCmd(dir), []string{namespace.MNT})
+	_, err := cmdexec.ExecCommonWithNS(audit.WithKind(ctx, audit.KindFile), cr, cId, getMkdirForceCmd(dir), []string{namespace.MNT})
 	return err
 }
 
@@ -232,7 +233,7 @@ func Chmod(ctx context.Context, cr, cId string, path, perm string) error {
 		return fmt.Errorf("\"perm\" can not be empty")
 	}
 
-	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getChmodCmd(path, perm), []string{namespace.MNT})
+	_, err := cmdexec.ExecCommonWithNS(audit.WithKind(ctx, audit.KindFile), cr, cId, getChmodCmd(path, perm), []string{namespace.MNT})
 	return err
 }
 
@@ -246,7 +247,7 @@ func DeleteLineByKey(ctx context.Context, cr, cId string, path, key string) erro
 		return fmt.Errorf("\"key\" can not be empty")
 	}
 
-	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getDeleteLineByKeyCmd(path, key), []string{namespace.MNT})
+	_, err := cmdexec.ExecCommonWithNS(audit.WithKind(ctx, audit.KindFile), cr, cId, getDeleteLineByKeyCmd(path, key), []string{namespace.MNT})
 	return err
 }
 
@@ -261,7 +262,7 @@ func AppendFile(ctx context.Context, cr, cId, path, content, flag string, count,
 	}
 
 	//_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, getAppendFileCmd(path, content, count, interval), []string{namespace.MNT})
-	return cmdexec.ExecBackGroundCommon(ctx, cr, cId, getAppendFileCmd(flag, path, content, count, interval))
+	return cmdexec.ExecBackGroundCommon(audit.WithKind(ctx, audit.KindFile), cr, cId, getAppendFileCmd(flag, path, content, count, interval))
 }
 
 func IfPathAbs(ctx context.Context, path string) bool {
@@ -274,7 +275,7 @@ func IfPathAbs(ctx context.Context, path string) bool {
 }
 
 func MkdirP(ctx context.Context, path string) error {
-	return cmdexec.RunBashCmdWithoutOutput(ctx, fmt.Sprintf("mkdir -p %s", path))
+	return cmdexec.RunBashCmdWithoutOutput(audit.WithKind(ctx, audit.KindFile), fmt.Sprintf("mkdir -p %s", path))
 }
 
 // ExistFile Must be a file
@@ -0,0 +1,207 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package net
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/crclient"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/cmdexec"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/namespace"
+	"os"
+	"strings"
+)
+
+const (
+	LinkUp   = "up"
+	LinkDown = "down"
+
+	hostNetNsPath = "/proc/1/ns/net"
+)
+
+// the flags in the output of "ip route show" which can not be used to add a route
+var routeStatusFlags = []string{"linkdown", "dead", "offload", "trap", "rt_offload", "rt_trap", "rt_offload_failed"}
+
+// GetLinkState return the administrative state of network interface in container's net namespace
+func GetLinkState(ctx context.Context, cr, cId, netInterface string) (string, error) {
+	re, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, fmt.Sprintf("ip -o link show dev %s", netInterface), []string{namespace.NET})
+	if err != nil {
+		return "", fmt.Errorf("get link of interface[%s] error: %s", netInterface, err.Error())
+	}
+
+	return parseLinkState(re)
+}
+
+func SetLinkState(ctx context.Context, cr, cId, netInterface, state string) error {
+	_, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, fmt.Sprintf("ip link set dev %s %s", netInterface, state), []string{namespace.NET})
+	return err
+}
+
+// GetLinkRoutes return the routes of the main table on the interface in container's net namespace, which are flushed by
+// kernel when the interface is set down
+func GetLinkRoutes(ctx context.Context, cr, cId, netInterface string, ipv6 bool) ([]string, error) {
+	re, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, fmt.Sprintf("ip %s route show dev %s", getIPFamily(ipv6), netInterface), []string{namespace.NET})
+	if err != nil {
+		return nil, fmt.Errorf("get routes of interface[%s] error: %s", netInterface, err.Error())
+	}
+
+	return parseLinkRoutes(re), nil
+}
+
+// AddLinkRoutes add the routes returned by GetLinkRoutes to the interface again, the existing ones are replaced
+func AddLinkRoutes(ctx context.Context, cr, cId, netInterface string, ipv6 bool, routes []string) error {
+	var errs []string
+	for _, route := range routes {
+		cmd := fmt.Sprintf("ip %s route replace %s dev %s", getIPFamily(ipv6), route, netInterface)
+		if _, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, cmd, []string{namespace.NET}); err != nil {
+			errs = append(errs, fmt.Sprintf("add route[%s] error: %s", route, err.Error()))
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+
+	return nil
+}
+
+func getIPFamily(ipv6 bool) string {
+	if ipv6 {
+		return "-6"
+	}
+
+	return "-4"
+}
+
+// GetDefaultRouteInterfaces return the interfaces of the ipv4 and ipv6 default routes in container's net namespace
+func GetDefaultRouteInterfaces(ctx context.Context, cr, cId string) ([]string, error) {
+	re, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, "ip route show default; ip -6 route show default", []string{namespace.NET})
+	if err != nil {
+		return nil, fmt.Errorf("get default route error: %s", err.Error())
+	}
+
+	return parseRouteInterfaces(re), nil
+}
+
+// IsHostNetNs check if the container shares the net namespace of host, eg: pod with hostNetwork. Empty cr means host
+func IsHostNetNs(ctx context.Context, cr, cId string) (bool, error) {
+	if cr == "" {
+		return true, nil
+	}
+
+	client, err := crclient.GetClient(ctx, cr)
+	if err != nil {
+		return false, fmt.Errorf("get %s client error: %s", cr, err.Error())
+	}
+
+	pid, err := client.GetPidById(ctx, cId)
+	if err != nil {
+		return false, fmt.Errorf("get pid of container[%s]'s init process error: %s", cId, err.Error())
+	}
+
+	hostNs, err := os.Readlink(hostNetNsPath)
+	if err != nil {
+		return false, fmt.Errorf("read host net namespace error: %s", err.Error())
+	}
+
+	containerNs, err := os.Readlink(fmt.Sprintf("/proc/%d/ns/net", pid))
+	if err != nil {
+		return false, fmt.Errorf("read net namespace of process[%d] error: %s", pid, err.Error())
+	}
+
+	return hostNs == containerNs, nil
+}
+
+// parseLinkState parse the flags of "ip -o link show", eg: "2: eth0: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu 1500 ..."
+func parseLinkState(output string) (string, error) {
+	start, end := strings.Index(output, "<"), strings.Index(output, ">")
+	if start < 0 || end < start {
+		return "", fmt.Errorf("unknown link info: %s", output)
+	}
+
+	for _, flag := range strings.Split(output[start+1:end], ",") {
+		if flag == "UP" {
+			return LinkUp, nil
+		}
+	}
+
+	return LinkDown, nil
+}
+
+// parseRouteInterfaces parse the output of "ip route show", eg: "default via 10.0.0.1 dev eth0 proto dhcp metric 100"
+func parseRouteInterfaces(output string) []string {
+	var re []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		for i := 0; i < len(fields)-1; i++ {
+			if fields[i] == "dev" && !utils.StrListContain(re, fields[i+1]) {
+				re = append(re, fields[i+1])
+			}
+		}
+	}
+
+	return re
+}
+
+// parseLinkRoutes parse the output of "ip route show dev [interface]", eg: "default via 10.0.0.1 proto dhcp metric 100".
+// The routes of kernel and router advertisement come back by themselves, and a multipath route is kept by kernel with
+// the nexthop marked dead, so they are skipped. The routes without gateway are returned first, because a gateway must
+// be reachable before the route via it is added
+func parseLinkRoutes(output string) []string {
+	var direct, gateway []string
+	lines := strings.Split(output, "\n")
+	for i, line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) == 0 || strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t") {
+			continue
+		}
+
+		if i+1 < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i+1]), "nexthop") {
+			continue
+		}
+
+		var route []string
+		isKernel, hasGateway := false, false
+		for j := 0; j < len(fields); j++ {
+			switch {
+			case fields[j] == "proto" && j+1 < len(fields) && (fields[j+1] == "kernel" || fields[j+1] == "ra"):
+				isKernel = true
+			case fields[j] == "via":
+				hasGateway = true
+			case fields[j] == "expires":
+				j++
+				continue
+			case utils.StrListContain(routeStatusFlags, fields[j]):
+				continue
+			}
+			route = append(route, fields[j])
+		}
+
+		if isKernel {
+			continue
+		}
+
+		if hasGateway {
+			gateway = append(gateway, strings.Join(route, " "))
+		} else {
+			direct = append(direct, strings.Join(route, " "))
+		}
+	}
+
+	return append(direct, gateway...)
+}
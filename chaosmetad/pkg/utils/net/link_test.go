@@ -0,0 +1,90 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package net
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseLinkState(t *testing.T) {
+	tests := []struct {
+		name    string
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{"up", "2: eth0: <BROADCAST,MULTICAST,UP,LOWER_UP> mtu 1500 qdisc noqueue state UP mode DEFAULT", LinkUp, false},
+		{"up without carrier", "3: veth0@if2: <NO-CARRIER,BROADCAST,MULTICAST,UP> mtu 1500 qdisc noqueue state LOWERLAYERDOWN", LinkUp, false},
+		{"down", "2: eth0: <BROADCAST,MULTICAST> mtu 1500 qdisc noop state DOWN mode DEFAULT", LinkDown, false},
+		{"lower up only", "2: eth0: <BROADCAST,MULTICAST,LOWER_UP> mtu 1500", LinkDown, false},
+		{"unknown", "Device \"eth9\" does not exist.", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseLinkState(tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseLinkState() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseLinkState() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRouteInterfaces(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{"empty", "", nil},
+		{"ipv4", "default via 10.0.0.1 dev eth0 proto dhcp metric 100", []string{"eth0"}},
+		{"ipv4 and ipv6", "default via 10.0.0.1 dev eth0\ndefault via fe80::1 dev eth1 proto ra metric 1024\ndefault via 10.0.0.2 dev eth0 metric 200", []string{"eth0", "eth1"}},
+		{"multipath", "default proto static metric 100\n\tnexthop via 10.0.0.1 dev eth0 weight 1\n\tnexthop via 10.1.0.1 dev bond0 weight 1", []string{"eth0", "bond0"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseRouteInterfaces(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseRouteInterfaces() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLinkRoutes(t *testing.T) {
+	tests := []struct {
+		name   string
+		output string
+		want   []string
+	}{
+		{"empty", "", nil},
+		{"kernel only", "10.0.0.0/24 proto kernel scope link src 10.0.0.5", nil},
+		{"default via gateway", "default via 10.0.0.1 proto dhcp metric 100\n10.0.0.0/24 proto kernel scope link src 10.0.0.5 metric 100", []string{"default via 10.0.0.1 proto dhcp metric 100"}},
+		{"direct before gateway", "default via 10.1.0.1 metric 10\n10.1.0.0/16 scope link linkdown", []string{"10.1.0.0/16 scope link", "default via 10.1.0.1 metric 10"}},
+		{"ipv6", "2001:db8::/64 proto kernel metric 256 pref medium\nfe80::/64 proto kernel metric 256 pref medium\ndefault via fe80::1 proto ra metric 1024 expires 1790sec pref medium\ndefault via fe80::2 metric 2048 pref medium", []string{"default via fe80::2 metric 2048 pref medium"}},
+		{"multipath", "default proto static metric 100\n\tnexthop via 10.0.0.1 dev eth0 weight 1\n\tnexthop via 10.1.0.1 dev bond0 weight 1\n10.2.0.0/16 via 10.0.0.1 dead", []string{"10.2.0.0/16 via 10.0.0.1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseLinkRoutes(tt.output); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseLinkRoutes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -357,3 +357,27 @@ func GetPidByPort(ctx context.Context, cr, cId string, port int, proto string) (
 
 	return pid, nil
 }
+
+// GetInterfaceIPs return the ipv4 addresses of network interface in container's net namespace
+func GetInterfaceIPs(ctx context.Context, cr, cId, netInterface string) ([]string, error) {
+	re, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, fmt.Sprintf("ip -o -4 addr show dev %s | awk '{print $4}'", netInterface), []string{namespace.NET})
+	if err != nil {
+		return nil, fmt.Errorf("get address of interface[%s] error: %s", netInterface, err.Error())
+	}
+
+	var ipList []string
+	for _, unit := range strings.Split(re, "\n") {
+		unit = strings.TrimSpace(unit)
+		if unit == "" {
+			continue
+		}
+
+		ip, _, err := net.ParseCIDR(unit)
+		if err != nil {
+			return nil, fmt.Errorf("%s is not a valid address: %s", unit, err.Error())
+		}
+		ipList = append(ipList, ip.String())
+	}
+
+	return ipList, nil
+}
@@ -415,3 +415,13 @@ func WaitDefunctProcess(ctx context.Context) {
 		}
 	}
 }
+
+// GetProcessName return the command name of process in container's pid namespace
+func GetProcessName(ctx context.Context, cr, cId string, pid int) (string, error) {
+	re, err := cmdexec.ExecCommonWithNS(ctx, cr, cId, fmt.Sprintf("cat /proc/%d/comm", pid), []string{namespace.PID, namespace.MNT})
+	if err != nil {
+		return "", fmt.Errorf("get name of process[%d] error: %s", pid, err.Error())
+	}
+
+	return strings.TrimSpace(re), nil
+}
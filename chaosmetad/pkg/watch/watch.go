@@ -0,0 +1,86 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package watch
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"time"
+)
+
+const (
+	// PollInterval experiments may be changed by other chaosmetad process, such as the delay recover process, so the store is polled
+	PollInterval = time.Millisecond * 500
+	BatchLimit   = 100
+)
+
+type OptionWatch struct {
+	Uid      string
+	Target   string
+	Fault    string
+	Revision int64
+}
+
+// Watch call handler with the events whose revision is larger than o.Revision in order, until ctx is done or handler return false.
+// A negative o.Revision means only watch the events created from now.
+// Revision of the last handled event will be returned, which can be used to resume watching
+func Watch(ctx context.Context, o *OptionWatch, handler func(events []*storage.ExperimentEvent) bool) (int64, error) {
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		return o.Revision, fmt.Errorf("connect db error: %s", err.Error())
+	}
+
+	if o.Revision < 0 {
+		if o.Revision, err = db.LatestRevision(); err != nil {
+			return 0, fmt.Errorf("get latest revision error: %s", err.Error())
+		}
+	}
+
+	var revision, timer = o.Revision, time.NewTimer(0)
+	defer timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return revision, nil
+		case <-timer.C:
+		}
+
+		events, err := db.ListEvents(revision, o.Uid, o.Target, o.Fault, BatchLimit)
+		if err != nil {
+			return revision, fmt.Errorf("list events error: %s", err.Error())
+		}
+
+		if len(events) != 0 {
+			revision = events[len(events)-1].Revision
+			if !handler(events) {
+				return revision, nil
+			}
+		}
+
+		if len(events) == BatchLimit {
+			timer.Reset(0)
+		} else {
+			timer.Reset(PollInterval)
+		}
+	}
+}
+
+// IsTerminal means no more status transition will happen
+func IsTerminal(status string) bool {
+	return status == utils.StatusDestroyed || status == utils.StatusError
+}
@@ -0,0 +1,331 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package watchdog
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"github.com/shirou/gopsutil/disk"
+	"github.com/shirou/gopsutil/load"
+	"github.com/shirou/gopsutil/mem"
+	"github.com/shirou/gopsutil/process"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	PSICpu    = "cpu"
+	PSIMemory = "memory"
+	PSIIO     = "io"
+
+	PSISome = "some"
+	PSIFull = "full"
+
+	psiPathFormat      = "/proc/pressure/%s"
+	healthCheckTimeout = time.Second * 3
+)
+
+// Conditions are the stop conditions of node, experiments will be aborted if any of them is breached
+type Conditions struct {
+	// MaxLoad max 1-minute load average
+	MaxLoad float64 `json:"max_load,omitempty"`
+	// MinMemAvailable percent of total memory or bytes, eg: 10%, 512mb
+	MinMemAvailable string         `json:"min_mem_available,omitempty"`
+	MinDiskFree     []DiskFreeUnit `json:"min_disk_free,omitempty"`
+	MaxPSI          []PSIUnit      `json:"max_psi,omitempty"`
+	// Processes process command name which must be alive, eg: kubelet
+	Processes []string `json:"processes,omitempty"`
+	// KubeletHealthz healthz url of kubelet, eg: http://127.0.0.1:10248/healthz
+	KubeletHealthz string `json:"kubelet_healthz,omitempty"`
+}
+
+type DiskFreeUnit struct {
+	Path string `json:"path"`
+	// Min percent of total disk or bytes, eg: 10%, 1gb
+	Min string `json:"min"`
+}
+
+type PSIUnit struct {
+	// Resource support: cpu, memory, io
+	Resource string `json:"resource"`
+	// Level support: some(default), full
+	Level string `json:"level,omitempty"`
+	// Avg10 max percent of avg10
+	Avg10 float64 `json:"avg10"`
+}
+
+func (c *Conditions) Validate() error {
+	if c.MaxLoad < 0 {
+		return fmt.Errorf("\"max_load\" can not be less than 0")
+	}
+
+	if c.MinMemAvailable != "" {
+		if _, _, err := parseThreshold(c.MinMemAvailable); err != nil {
+			return fmt.Errorf("\"min_mem_available\" is invalid: %s", err.Error())
+		}
+	}
+
+	for _, unit := range c.MinDiskFree {
+		if unit.Path == "" {
+			return fmt.Errorf("\"path\" of \"min_disk_free\" is empty")
+		}
+
+		if _, _, err := parseThreshold(unit.Min); err != nil {
+			return fmt.Errorf("\"min\" of \"min_disk_free\"[%s] is invalid: %s", unit.Path, err.Error())
+		}
+	}
+
+	for _, unit := range c.MaxPSI {
+		if unit.Resource != PSICpu && unit.Resource != PSIMemory && unit.Resource != PSIIO {
+			return fmt.Errorf("\"resource\" of \"max_psi\" only support: %s, %s, %s", PSICpu, PSIMemory, PSIIO)
+		}
+
+		if unit.Level != "" && unit.Level != PSISome && unit.Level != PSIFull {
+			return fmt.Errorf("\"level\" of \"max_psi\" only support: %s, %s", PSISome, PSIFull)
+		}
+
+		if unit.Avg10 <= 0 || unit.Avg10 > 100 {
+			return fmt.Errorf("\"avg10\" of \"max_psi\" should in (0, 100]")
+		}
+	}
+
+	return nil
+}
+
+// Evaluate return the reasons of breached conditions, and the errors of conditions which can not be evaluated this time.
+// The unknown conditions are not treated as breached, the caller decides how to handle them
+func (c *Conditions) Evaluate(ctx context.Context) (reasons []string, unknown []string) {
+	if c.MaxLoad > 0 {
+		avg, err := load.AvgWithContext(ctx)
+		if err != nil {
+			unknown = append(unknown, fmt.Sprintf("get load average error: %s", err.Error()))
+		} else if avg.Load1 > c.MaxLoad {
+			reasons = append(reasons, fmt.Sprintf("load average %.2f is larger than %.2f", avg.Load1, c.MaxLoad))
+		}
+	}
+
+	if c.MinMemAvailable != "" {
+		vm, err := mem.VirtualMemoryWithContext(ctx)
+		if err != nil {
+			unknown = append(unknown, fmt.Sprintf("get memory error: %s", err.Error()))
+		} else if isLess(c.MinMemAvailable, vm.Available, vm.Total) {
+			reasons = append(reasons, fmt.Sprintf("available memory %dB is less than %s", vm.Available, c.MinMemAvailable))
+		}
+	}
+
+	for _, unit := range c.MinDiskFree {
+		usage, err := disk.UsageWithContext(ctx, unit.Path)
+		if err != nil {
+			unknown = append(unknown, fmt.Sprintf("get disk usage of %s error: %s", unit.Path, err.Error()))
+		} else if isLess(unit.Min, usage.Free, usage.Total) {
+			reasons = append(reasons, fmt.Sprintf("free disk of %s %dB is less than %s", unit.Path, usage.Free, unit.Min))
+		}
+	}
+
+	for _, unit := range c.MaxPSI {
+		level := unit.getLevel()
+		avg10, err := getPSIAvg10(unit.Resource, level)
+		if err != nil {
+			unknown = append(unknown, fmt.Sprintf("get %s pressure error: %s", unit.Resource, err.Error()))
+		} else if avg10 > unit.Avg10 {
+			reasons = append(reasons, fmt.Sprintf("%s pressure of %s avg10 %.2f%% is larger than %.2f%%", level, unit.Resource, avg10, unit.Avg10))
+		}
+	}
+
+	if len(c.Processes) > 0 {
+		alive, err := getAliveProcesses(ctx)
+		if err != nil {
+			unknown = append(unknown, fmt.Sprintf("get processes error: %s", err.Error()))
+		} else {
+			for _, name := range c.Processes {
+				if !alive[name] {
+					reasons = append(reasons, fmt.Sprintf("process %s is not alive", name))
+				}
+			}
+		}
+	}
+
+	// an unreachable kubelet is what this condition checks, so it is breached rather than unknown
+	if c.KubeletHealthz != "" {
+		if err := checkHealthz(ctx, c.KubeletHealthz); err != nil {
+			reasons = append(reasons, fmt.Sprintf("kubelet is not healthy: %s", err.Error()))
+		}
+	}
+
+	return reasons, unknown
+}
+
+// CheckReadable return error if any configured metric can not be read on this node, such as "max_psi" on a kernel
+// without /proc/pressure. Such config can never be evaluated, so it should be rejected rather than run
+func (c *Conditions) CheckReadable(ctx context.Context) error {
+	if c.MaxLoad > 0 {
+		if _, err := load.AvgWithContext(ctx); err != nil {
+			return fmt.Errorf("\"max_load\" is not supported on this node: %s", err.Error())
+		}
+	}
+
+	if c.MinMemAvailable != "" {
+		if _, err := mem.VirtualMemoryWithContext(ctx); err != nil {
+			return fmt.Errorf("\"min_mem_available\" is not supported on this node: %s", err.Error())
+		}
+	}
+
+	for _, unit := range c.MinDiskFree {
+		if _, err := disk.UsageWithContext(ctx, unit.Path); err != nil {
+			return fmt.Errorf("\"min_disk_free\" of %s can not be read: %s", unit.Path, err.Error())
+		}
+	}
+
+	for _, unit := range c.MaxPSI {
+		if _, err := getPSIAvg10(unit.Resource, unit.getLevel()); err != nil {
+			return fmt.Errorf("\"max_psi\" of %s %s is not supported on this node: %s", unit.getLevel(), unit.Resource, err.Error())
+		}
+	}
+
+	if len(c.Processes) > 0 {
+		if _, err := getAliveProcesses(ctx); err != nil {
+			return fmt.Errorf("\"processes\" can not be read on this node: %s", err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (u *PSIUnit) getLevel() string {
+	if u.Level == "" {
+		return PSISome
+	}
+
+	return u.Level
+}
+
+// parseThreshold support percent or bytes, eg: 10%, 512mb
+func parseThreshold(threshold string) (value float64, isPercent bool, err error) {
+	threshold = strings.TrimSpace(threshold)
+	if strings.HasSuffix(threshold, "%") {
+		value, err = strconv.ParseFloat(strings.TrimSuffix(threshold, "%"), 64)
+		if err != nil {
+			return 0, false, fmt.Errorf("percent is not a num: %s", err.Error())
+		}
+
+		if value < 0 || value > 100 {
+			return 0, false, fmt.Errorf("percent should in [0, 100]")
+		}
+
+		return value, true, nil
+	}
+
+	bytes, err := utils.GetBytes(strings.ToLower(threshold))
+	if err != nil {
+		return 0, false, err
+	}
+
+	if bytes < 0 {
+		return 0, false, fmt.Errorf("bytes can not be less than 0")
+	}
+
+	return float64(bytes), false, nil
+}
+
+func isLess(threshold string, value, total uint64) bool {
+	min, isPercent, err := parseThreshold(threshold)
+	if err != nil {
+		return false
+	}
+
+	if isPercent {
+		return total > 0 && float64(value)/float64(total)*100 < min
+	}
+
+	return float64(value) < min
+}
+
+func getPSIAvg10(resource, level string) (float64, error) {
+	f, err := os.Open(fmt.Sprintf(psiPathFormat, resource))
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	return parsePSIAvg10(bufio.NewScanner(f), level)
+}
+
+// parsePSIAvg10 format: some avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func parsePSIAvg10(scanner *bufio.Scanner, level string) (float64, error) {
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != level {
+			continue
+		}
+
+		for _, field := range fields[1:] {
+			if strings.HasPrefix(field, "avg10=") {
+				return strconv.ParseFloat(strings.TrimPrefix(field, "avg10="), 64)
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, err
+	}
+
+	return 0, fmt.Errorf("level %s not found", level)
+}
+
+func getAliveProcesses(ctx context.Context) (map[string]bool, error) {
+	proList, err := process.ProcessesWithContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	alive := make(map[string]bool)
+	for _, pro := range proList {
+		name, err := pro.NameWithContext(ctx)
+		if err != nil {
+			continue
+		}
+		alive[name] = true
+	}
+
+	return alive, nil
+}
+
+func checkHealthz(ctx context.Context, url string) error {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,197 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package watchdog
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	configFile      = "watchdog.json"
+	DefaultInterval = "5s"
+	// MaxUnknownTimes the experiments are recovered if the conditions can not be evaluated for so many consecutive times
+	MaxUnknownTimes = 3
+)
+
+// Path is the watchdog config file, empty means "[run path]/watchdog.json". Watchdog is disabled if file not exist
+var Path string
+
+type Config struct {
+	// Interval to evaluate the conditions, support unit: "s、m、h"(default s)
+	Interval   string     `json:"interval,omitempty"`
+	Conditions Conditions `json:"conditions"`
+	// Scope experiments to recover when conditions are breached, format: "[target]" or "[target]-[fault]", eg: cpu, mem-fill.
+	// empty means all the running experiments
+	Scope []string `json:"scope,omitempty"`
+}
+
+func GetConfigPath() string {
+	if Path != "" {
+		return Path
+	}
+
+	return path.Join(utils.GetRunPath(), configFile)
+}
+
+// LoadConfig return nil if config file not exist
+func LoadConfig() (*Config, error) {
+	fileBytes, err := os.ReadFile(GetConfigPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+
+		return nil, fmt.Errorf("read watchdog file[%s] error: %s", GetConfigPath(), err.Error())
+	}
+
+	c := &Config{}
+	if err := json.Unmarshal(fileBytes, c); err != nil {
+		return nil, fmt.Errorf("watchdog file[%s] format error: %s", GetConfigPath(), err.Error())
+	}
+
+	if c.Interval == "" {
+		c.Interval = DefaultInterval
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, fmt.Errorf("watchdog file[%s] is invalid: %s", GetConfigPath(), err.Error())
+	}
+
+	if err := c.Conditions.CheckReadable(context.Background()); err != nil {
+		return nil, fmt.Errorf("watchdog file[%s] can not be evaluated: %s", GetConfigPath(), err.Error())
+	}
+
+	return c, nil
+}
+
+func (c *Config) Validate() error {
+	interval, err := utils.GetTimeSecond(c.Interval)
+	if err != nil {
+		return fmt.Errorf("\"interval\" is invalid: %s", err.Error())
+	}
+
+	if interval <= 0 {
+		return fmt.Errorf("\"interval\" must be larger than 0")
+	}
+
+	for _, unit := range c.Scope {
+		if unit == "" || len(strings.Split(unit, utils.BuilderSplit)) > 2 {
+			return fmt.Errorf("\"scope\"[%s] is invalid, format: [target] or [target]%s[fault]", unit, utils.BuilderSplit)
+		}
+	}
+
+	return c.Conditions.Validate()
+}
+
+// InScope whether the experiment should be recovered when conditions are breached
+func (c *Config) InScope(target, fault string) bool {
+	if len(c.Scope) == 0 {
+		return true
+	}
+
+	for _, unit := range c.Scope {
+		unitArr := strings.Split(unit, utils.BuilderSplit)
+		if unitArr[0] != target {
+			continue
+		}
+
+		if len(unitArr) == 1 || unitArr[1] == fault {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Run evaluate the conditions periodically until ctx done, and recover the experiments in scope when any condition is breached
+func Run(ctx context.Context, c *Config) {
+	logger := log.GetLogger(ctx)
+	intervalSec, _ := utils.GetTimeSecond(c.Interval)
+	ticker := time.NewTicker(time.Duration(intervalSec) * time.Second)
+	defer ticker.Stop()
+
+	logger.Infof("watchdog start, interval: %s, scope: %v", c.Interval, c.Scope)
+	var unknownTimes int
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		reasons, unknown := c.Conditions.Evaluate(ctx)
+		if len(unknown) == 0 {
+			unknownTimes = 0
+		} else {
+			unknownTimes++
+			logger.Warnf("watchdog can not evaluate conditions[%d/%d]: %s", unknownTimes, MaxUnknownTimes, strings.Join(unknown, "; "))
+			if unknownTimes >= MaxUnknownTimes {
+				reasons = append(reasons, fmt.Sprintf("conditions can not be evaluated for %d times: %s", unknownTimes, strings.Join(unknown, "; ")))
+			}
+		}
+
+		if len(reasons) == 0 {
+			continue
+		}
+
+		abortExperiments(ctx, c, strings.Join(reasons, "; "))
+	}
+}
+
+func abortExperiments(ctx context.Context, c *Config, reason string) {
+	logger := log.GetLogger(ctx)
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		logger.Errorf("watchdog get db error: %s", err.Error())
+		return
+	}
+
+	exps, err := db.ListByStatus(utils.StatusSuccess)
+	if err != nil {
+		logger.Errorf("watchdog list running experiments error: %s", err.Error())
+		return
+	}
+
+	for _, exp := range exps {
+		if !c.InScope(exp.Target, exp.Fault) {
+			continue
+		}
+
+		logger.Warnf("watchdog recover experiment[%s], reason: %s", exp.Uid, reason)
+		errMsg := fmt.Sprintf("aborted by watchdog: %s", reason)
+		// the status is owned by ProcessRecover, only the reason is recorded here with an event
+		code, msg := injector.ProcessRecover(ctx, exp.Uid)
+		if code != errutil.NoErr {
+			logger.Errorf("watchdog recover experiment[%s] error: %s", exp.Uid, msg)
+			errMsg = fmt.Sprintf("%s, recover error: %s", errMsg, msg)
+		}
+
+		if err := db.UpdateErr(exp.Uid, errMsg); err != nil {
+			logger.Warnf("update error for experiment[%s] error: %s", exp.Uid, err.Error())
+		}
+	}
+}
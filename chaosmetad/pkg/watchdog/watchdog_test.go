@@ -0,0 +1,139 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package watchdog
+
+import (
+	"bufio"
+	"context"
+	"strings"
+	"testing"
+)
+
+func Test_parsePSIAvg10(t *testing.T) {
+	content := "some avg10=12.50 avg60=3.00 avg300=1.00 total=1000\nfull avg10=2.25 avg60=0.00 avg300=0.00 total=10\n"
+	tests := []struct {
+		name    string
+		level   string
+		want    float64
+		wantErr bool
+	}{
+		{name: "some", level: PSISome, want: 12.5},
+		{name: "full", level: PSIFull, want: 2.25},
+		{name: "not found", level: "none", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePSIAvg10(bufio.NewScanner(strings.NewReader(content)), tt.level)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parsePSIAvg10() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parsePSIAvg10() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_isLess(t *testing.T) {
+	tests := []struct {
+		name      string
+		threshold string
+		value     uint64
+		total     uint64
+		want      bool
+	}{
+		{name: "percent less", threshold: "10%", value: 5, total: 100, want: true},
+		{name: "percent enough", threshold: "10%", value: 50, total: 100, want: false},
+		{name: "bytes less", threshold: "1kb", value: 1000, total: 4096, want: true},
+		{name: "bytes enough", threshold: "1KB", value: 1024, total: 4096, want: false},
+		{name: "invalid", threshold: "abc", value: 0, total: 100, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isLess(tt.threshold, tt.value, tt.total); got != tt.want {
+				t.Errorf("isLess() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConfig_InScope(t *testing.T) {
+	c := &Config{Scope: []string{"cpu", "mem-fill"}}
+	tests := []struct {
+		target string
+		fault  string
+		want   bool
+	}{
+		{target: "cpu", fault: "burn", want: true},
+		{target: "cpu", fault: "load", want: true},
+		{target: "mem", fault: "fill", want: true},
+		{target: "mem", fault: "oom", want: false},
+		{target: "network", fault: "delay", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target+"-"+tt.fault, func(t *testing.T) {
+			if got := c.InScope(tt.target, tt.fault); got != tt.want {
+				t.Errorf("InScope() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if !(&Config{}).InScope("network", "delay") {
+		t.Errorf("InScope() with empty scope should be true")
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  *Config
+		wantErr bool
+	}{
+		{name: "normal", config: &Config{Interval: "5s", Conditions: Conditions{MaxLoad: 32, MinMemAvailable: "10%",
+			MinDiskFree: []DiskFreeUnit{{Path: "/", Min: "1gb"}}, MaxPSI: []PSIUnit{{Resource: PSIMemory, Avg10: 50}}}}},
+		{name: "invalid interval", config: &Config{Interval: "0"}, wantErr: true},
+		{name: "invalid scope", config: &Config{Interval: "5s", Scope: []string{"a-b-c"}}, wantErr: true},
+		{name: "invalid mem", config: &Config{Interval: "5s", Conditions: Conditions{MinMemAvailable: "120%"}}, wantErr: true},
+		{name: "invalid psi", config: &Config{Interval: "5s", Conditions: Conditions{MaxPSI: []PSIUnit{{Resource: "net", Avg10: 50}}}}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.config.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConditions_CheckReadable(t *testing.T) {
+	tests := []struct {
+		name       string
+		conditions *Conditions
+		wantErr    bool
+	}{
+		{name: "empty", conditions: &Conditions{}},
+		{name: "disk", conditions: &Conditions{MinDiskFree: []DiskFreeUnit{{Path: "/", Min: "1gb"}}}},
+		{name: "disk not exist", conditions: &Conditions{MinDiskFree: []DiskFreeUnit{{Path: "/chaosmeta-watchdog-not-exist", Min: "1gb"}}}, wantErr: true},
+		{name: "healthz is not read in advance", conditions: &Conditions{KubeletHealthz: "http://127.0.0.1:1/healthz"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.conditions.CheckReadable(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("CheckReadable() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
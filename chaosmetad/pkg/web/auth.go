@@ -0,0 +1,52 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package web
+
+import (
+	"context"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/auth"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/handler"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/model"
+	"net/http"
+)
+
+// publicRoutes can be accessed without token, so that the liveness check of clients still works
+var publicRoutes = map[string]bool{
+	"Index":      true,
+	"VersionGet": true,
+}
+
+func Auth(ctx context.Context, inner http.Handler, name string) http.Handler {
+	if publicRoutes[name] {
+		return inner
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := auth.Check(r.Header.Get(auth.HeaderKey)); err != nil {
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+			w.WriteHeader(http.StatusUnauthorized)
+			handler.WriteResponse(ctx, w, &model.CommonResponse{
+				Code:    errutil.AuthErr,
+				Message: err.Error(),
+			})
+			return
+		}
+
+		inner.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,84 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+
+package handler
+
+import (
+	"context"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/version"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/model"
+	"net/http"
+)
+
+// DescribeGet list the supported faults and their args. eg: GET /v1/describe?target=network&fault=delay
+func DescribeGet(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+
+	query := r.URL.Query()
+	ctx := utils.GetCtxWithTraceId(context.Background(), query.Get("trace_id"))
+	WriteResponse(ctx, w, Describe(ctx, &model.DescribeRequest{
+		Target: query.Get("target"),
+		Fault:  query.Get("fault"),
+	}))
+}
+
+// Describe is shared by the HTTP and gRPC API
+func Describe(ctx context.Context, describeReq *model.DescribeRequest) *model.DescribeResponse {
+	faults := injector.Describe(describeReq.Target, describeReq.Fault)
+	if len(faults) == 0 && (describeReq.Target != "" || describeReq.Fault != "") {
+		return &model.DescribeResponse{
+			Code:    errutil.BadArgsErr,
+			Message: fmt.Sprintf("no fault found by target[%s] and fault[%s]", describeReq.Target, describeReq.Fault),
+			TraceId: utils.GetTraceId(ctx),
+		}
+	}
+
+	v := version.GetVersion()
+	data := &model.DescribeResponseData{
+		Version:   v.Version,
+		BuildDate: v.BuildDate,
+		Faults:    make([]model.FaultDescriptorUnit, len(faults)),
+	}
+
+	for i, f := range faults {
+		data.Faults[i] = model.FaultDescriptorUnit{
+			Target:      f.Target,
+			Fault:       f.Fault,
+			Description: f.Description,
+			Args:        make([]model.ArgDescriptorUnit, len(f.Args)),
+		}
+		for j, a := range f.Args {
+			data.Faults[i].Args[j] = model.ArgDescriptorUnit{
+				Name:        a.Name,
+				Type:        a.Type,
+				Default:     a.Default,
+				Description: a.Description,
+			}
+		}
+	}
+
+	return &model.DescribeResponse{
+		Code:    errutil.NoErr,
+		Message: "success",
+		TraceId: utils.GetTraceId(ctx),
+		Data:    data,
+	}
+}
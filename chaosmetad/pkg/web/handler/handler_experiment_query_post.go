@@ -20,6 +20,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/injector"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
 	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
@@ -41,22 +42,38 @@ func ExperimentQueryPost(w http.ResponseWriter, r *http.Request) {
 		queryRes = getExperimentQueryPostResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("req body format error: %s", err.Error()), nil, 0)
 	} else {
 		ctx = utils.GetCtxWithTraceId(ctx, queryReq.TraceId)
-		db, dbErr := storage.GetExperimentStore()
-		if dbErr != nil {
-			queryRes = getExperimentQueryPostResponse(ctx, errutil.DBErr, fmt.Sprintf("get db error: %s", dbErr.Error()), nil, 0)
-		} else {
-			exps, total, qErr := db.QueryByOption(queryReq.Uid, queryReq.Status, queryReq.Target, queryReq.Fault,
-				queryReq.Creator, queryReq.ContainerRuntime, queryReq.ContainerId, uint(queryReq.Offset), uint(queryReq.Limit))
-			if qErr != nil {
-				queryRes = getExperimentQueryPostResponse(ctx, errutil.DBErr, fmt.Sprintf("db query error: %s", qErr.Error()), nil, 0)
-			}
-			queryRes = getExperimentQueryPostResponse(ctx, errutil.NoErr, "success", exps, total)
-		}
+		queryRes = Query(ctx, queryReq)
 	}
 
 	WriteResponse(ctx, w, queryRes)
 }
 
+// Query is shared by the HTTP and gRPC API
+func Query(ctx context.Context, queryReq *model.QueryRequest) *model.QueryResponse {
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		return getExperimentQueryPostResponse(ctx, errutil.DBErr, fmt.Sprintf("get db error: %s", err.Error()), nil, 0)
+	}
+
+	exps, total, err := db.QueryByOption(queryReq.Uid, queryReq.Status, queryReq.Target, queryReq.Fault,
+		queryReq.Creator, queryReq.ContainerRuntime, queryReq.ContainerId, uint(queryReq.Offset), uint(queryReq.Limit))
+	if err != nil {
+		return getExperimentQueryPostResponse(ctx, errutil.DBErr, fmt.Sprintf("db query error: %s", err.Error()), nil, 0)
+	}
+
+	queryRes := getExperimentQueryPostResponse(ctx, errutil.NoErr, "success", exps, total)
+	if queryReq.Uid != "" {
+		fillDetail(ctx, exps, queryRes.Data)
+	}
+	if queryReq.Audit {
+		if err := fillAudits(queryRes.Data); err != nil {
+			return getExperimentQueryPostResponse(ctx, errutil.DBErr, fmt.Sprintf("db query audit error: %s", err.Error()), nil, 0)
+		}
+	}
+
+	return queryRes
+}
+
 func getExperimentQueryPostResponse(ctx context.Context, code int, msg string, exps []*storage.Experiment, total int64) *model.QueryResponse {
 	var re = &model.QueryResponse{
 		Code:    code,
@@ -93,5 +110,57 @@ func ExpToExperimentDataUnit(exp *storage.Experiment) model.ExperimentDataUnit {
 		UpdateTime:       exp.UpdateTime,
 		ContainerId:      exp.ContainerId,
 		ContainerRuntime: exp.ContainerRuntime,
+		// guardrail
+		GuardrailOverride:  exp.GuardrailOverride,
+		GuardrailViolation: exp.GuardrailViolation,
+		// intermittent
+		Intermittent: exp.Intermittent,
+	}
+}
+
+// fillDetail add the live status reported by injector to each experiment of the query result
+func fillDetail(ctx context.Context, exps []*storage.Experiment, data *model.QueryResponseData) {
+	for i, exp := range exps {
+		detail, err := injector.QueryDetail(ctx, exp)
+		if err != nil {
+			detail = fmt.Sprintf("query detail error: %s", err.Error())
+		}
+		data.Experiments[i].Detail = detail
+	}
+}
+
+// fillAudits add the audit trail to each experiment of the query result
+func fillAudits(data *model.QueryResponseData) error {
+	db, err := storage.GetExperimentStore()
+	if err != nil {
+		return err
+	}
+
+	for i := range data.Experiments {
+		audits, err := db.ListAudits(data.Experiments[i].Uid)
+		if err != nil {
+			return fmt.Errorf("list audits of experiment[%s] error: %s", data.Experiments[i].Uid, err.Error())
+		}
+
+		data.Experiments[i].Audits = make([]model.ExperimentAuditUnit, len(audits))
+		for j, a := range audits {
+			data.Experiments[i].Audits[j] = AuditToExperimentAuditUnit(a)
+		}
+	}
+
+	return nil
+}
+
+func AuditToExperimentAuditUnit(a *storage.ExperimentAudit) model.ExperimentAuditUnit {
+	return model.ExperimentAuditUnit{
+		Phase:       a.Phase,
+		Kind:        a.Kind,
+		ContainerId: a.ContainerId,
+		Command:     a.Command,
+		ExitCode:    a.ExitCode,
+		Output:      a.Output,
+		Error_:      a.Error,
+		StartTime:   a.StartTime,
+		EndTime:     a.EndTime,
 	}
 }
@@ -41,13 +41,18 @@ func ExperimentRecoverPost(w http.ResponseWriter, r *http.Request) {
 		recoverRes = getCommonResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("req body format error: %s", err.Error()))
 	} else {
 		ctx = utils.GetCtxWithTraceId(ctx, recoverReq.TraceId)
-		code, msg := injector.ProcessRecover(ctx, recoverReq.Uid)
-		recoverRes = getCommonResponse(ctx, code, msg)
+		recoverRes = Recover(ctx, recoverReq)
 	}
 
 	WriteResponse(ctx, w, recoverRes)
 }
 
+// Recover is shared by the HTTP and gRPC API
+func Recover(ctx context.Context, recoverReq *model.RecoverRequest) *model.CommonResponse {
+	code, msg := injector.ProcessRecover(ctx, recoverReq.Uid)
+	return getCommonResponse(ctx, code, msg)
+}
+
 func getCommonResponse(ctx context.Context, code int, msg string) *model.CommonResponse {
 	return &model.CommonResponse{
 		Code:    code,
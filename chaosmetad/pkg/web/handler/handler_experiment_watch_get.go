@@ -0,0 +1,95 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/log"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/watch"
+	"net/http"
+	"strconv"
+)
+
+// ExperimentWatchGet server-sent events: the id of every event is its revision, so that clients can resume by "Last-Event-ID" header
+// or "revision" query parameter. eg: GET /v1/experiment/watch?uid=xxx&revision=10
+func ExperimentWatchGet(w http.ResponseWriter, r *http.Request) {
+	var (
+		query = r.URL.Query()
+		ctx   = utils.GetCtxWithTraceId(context.Background(), query.Get("trace_id"))
+		o     = &watch.OptionWatch{
+			Uid:    query.Get("uid"),
+			Target: query.Get("target"),
+			Fault:  query.Get("fault"),
+		}
+		logger = log.GetLogger(ctx)
+	)
+
+	revisionStr := r.Header.Get("Last-Event-ID")
+	if revisionStr == "" {
+		revisionStr = query.Get("revision")
+	}
+
+	if revisionStr != "" {
+		revision, err := strconv.ParseInt(revisionStr, 10, 64)
+		if err != nil {
+			w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+			w.WriteHeader(http.StatusBadRequest)
+			WriteResponse(ctx, w, getCommonResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("\"revision\"[%s] is invalid: %s", revisionStr, err.Error())))
+			return
+		}
+		o.Revision = revision
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+		w.WriteHeader(http.StatusInternalServerError)
+		WriteResponse(ctx, w, getCommonResponse(ctx, errutil.InternalErr, "streaming is not supported"))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	if _, err := watch.Watch(r.Context(), o, func(events []*storage.ExperimentEvent) bool {
+		for _, event := range events {
+			data, err := json.Marshal(EventToExperimentEventUnit(event))
+			if err != nil {
+				logger.Errorf("event[%d] Marshal error: %s", event.Revision, err.Error())
+				return false
+			}
+
+			if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.Revision, event.Status, data); err != nil {
+				logger.Warnf("write event[%d] error: %s", event.Revision, err.Error())
+				return false
+			}
+		}
+		flusher.Flush()
+		return true
+	}); err != nil {
+		logger.Errorf("watch error: %s", err.Error())
+		_, _ = fmt.Fprintf(w, "event: error\ndata: %s\n\n", err.Error())
+		flusher.Flush()
+	}
+}
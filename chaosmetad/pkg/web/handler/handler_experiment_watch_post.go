@@ -0,0 +1,115 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/storage"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/utils/errutil"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/watch"
+	"github.com/traas-stack/chaosmeta/chaosmetad/pkg/web/model"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultWatchTimeout = "30s"
+	maxWatchTimeout     = 300
+)
+
+// ExperimentWatchPost long-poll: return as soon as there are events after the revision, or return empty events when timeout
+func ExperimentWatchPost(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+
+	var (
+		ctx      = context.Background()
+		watchReq = &model.WatchRequest{}
+		watchRes *model.WatchResponse
+	)
+
+	if err := json.NewDecoder(r.Body).Decode(watchReq); err != nil {
+		watchRes = getExperimentWatchPostResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("req body format error: %s", err.Error()), 0, nil)
+	} else {
+		ctx = utils.GetCtxWithTraceId(ctx, watchReq.TraceId)
+		if watchReq.Timeout == "" {
+			watchReq.Timeout = defaultWatchTimeout
+		}
+
+		timeout, err := utils.GetTimeSecond(watchReq.Timeout)
+		if err != nil || timeout <= 0 || timeout > maxWatchTimeout {
+			watchRes = getExperimentWatchPostResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("\"timeout\"[%s] is invalid, should be in (0, %ds]", watchReq.Timeout, maxWatchTimeout), 0, nil)
+		} else {
+			watchCtx, cancel := context.WithTimeout(r.Context(), time.Duration(timeout)*time.Second)
+			var events []*storage.ExperimentEvent
+			revision, wErr := watch.Watch(watchCtx, &watch.OptionWatch{
+				Uid:      watchReq.Uid,
+				Target:   watchReq.Target,
+				Fault:    watchReq.Fault,
+				Revision: watchReq.Revision,
+			}, func(evs []*storage.ExperimentEvent) bool {
+				events = evs
+				return false
+			})
+			cancel()
+
+			if wErr != nil {
+				watchRes = getExperimentWatchPostResponse(ctx, errutil.DBErr, fmt.Sprintf("watch error: %s", wErr.Error()), 0, nil)
+			} else {
+				watchRes = getExperimentWatchPostResponse(ctx, errutil.NoErr, "success", revision, events)
+			}
+		}
+	}
+
+	WriteResponse(ctx, w, watchRes)
+}
+
+func getExperimentWatchPostResponse(ctx context.Context, code int, msg string, revision int64, events []*storage.ExperimentEvent) *model.WatchResponse {
+	var re = &model.WatchResponse{
+		Code:    code,
+		Message: msg,
+		TraceId: utils.GetTraceId(ctx),
+	}
+
+	if code == errutil.NoErr {
+		reList := make([]model.ExperimentEventUnit, len(events))
+		for i, event := range events {
+			reList[i] = EventToExperimentEventUnit(event)
+		}
+
+		re.Data = &model.WatchResponseData{
+			Revision: revision,
+			Events:   reList,
+		}
+	}
+
+	return re
+}
+
+func EventToExperimentEventUnit(event *storage.ExperimentEvent) model.ExperimentEventUnit {
+	return model.ExperimentEventUnit{
+		Revision:   event.Revision,
+		Uid:        event.Uid,
+		Target:     event.Target,
+		Fault:      event.Fault,
+		Status:     event.Status,
+		Error_:     event.Error,
+		CreateTime: event.CreateTime,
+	}
+}
@@ -42,46 +42,62 @@ func ExperimentInjectPost(w http.ResponseWriter, r *http.Request) {
 		injectRes = getExperimentInjectPostResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("req body format error: %s", err.Error()), nil)
 	} else {
 		ctx = utils.GetCtxWithTraceId(ctx, injectReq.TraceId)
-		i, err := injector.NewInjector(injectReq.Target, injectReq.Fault)
-		if err != nil {
-			injectRes = getExperimentInjectPostResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("get injector error: %s", err.Error()), nil)
-		} else {
-			creator := injectReq.Creator
-			if creator == "" {
-				creator = r.RemoteAddr
-			}
-
-			if err := i.LoadInjector(&storage.Experiment{
-				Uid:              injectReq.Uid,
-				Target:           injectReq.Target,
-				Fault:            injectReq.Fault,
-				Args:             injectReq.Args,
-				Timeout:          injectReq.Timeout,
-				ContainerRuntime: injectReq.ContainerRuntime,
-				ContainerId:      injectReq.ContainerId,
-				Creator:          creator,
-				Runtime:          "{}",
-			}, i.GetArgs(), i.GetRuntime()); err != nil {
-				injectRes = getExperimentInjectPostResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("args load error: %s", err.Error()), nil)
-			} else {
-				code, msg := injector.ProcessInject(ctx, i)
-				if code == errutil.NoErr {
-					exp, err := i.OptionToExp(i.GetArgs(), i.GetRuntime())
-					if err != nil {
-						injectRes = getExperimentInjectPostResponse(ctx, errutil.NoErr, fmt.Sprintf("inject success but get exp info error: %s", err.Error()), nil)
-					} else {
-						injectRes = getExperimentInjectPostResponse(ctx, errutil.NoErr, "success", exp)
-					}
-				} else {
-					injectRes = getExperimentInjectPostResponse(ctx, errutil.InjectErr, fmt.Sprintf("injector error: %s", msg), nil)
-				}
-			}
-		}
+		injectRes = Inject(ctx, injectReq, r.RemoteAddr)
 	}
 
 	WriteResponse(ctx, w, injectRes)
 }
 
+// Inject is shared by the HTTP and gRPC API, the creator is default to the remote address of the client
+func Inject(ctx context.Context, injectReq *model.InjectRequest, remoteAddr string) *model.InjectResponse {
+	i, err := injector.NewInjector(injectReq.Target, injectReq.Fault)
+	if err != nil {
+		return getExperimentInjectPostResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("get injector error: %s", err.Error()), nil)
+	}
+
+	creator := injectReq.Creator
+	if creator == "" {
+		creator = remoteAddr
+	}
+
+	if err := i.LoadInjector(&storage.Experiment{
+		Uid:              injectReq.Uid,
+		Target:           injectReq.Target,
+		Fault:            injectReq.Fault,
+		Args:             injectReq.Args,
+		Timeout:          injectReq.Timeout,
+		ContainerRuntime: injectReq.ContainerRuntime,
+		ContainerId:      injectReq.ContainerId,
+		Creator:          creator,
+		Runtime:          "{}",
+		// guardrail
+		GuardrailOverride: injectReq.GuardrailOverride,
+		// intermittent
+		Intermittent: (&injector.IntermittentInfo{
+			On:          injectReq.On,
+			Off:         injectReq.Off,
+			Jitter:      injectReq.OnOffJitter,
+			Probability: injectReq.Probability,
+		}).String(),
+	}, i.GetArgs(), i.GetRuntime()); err != nil {
+		return getExperimentInjectPostResponse(ctx, errutil.BadArgsErr, fmt.Sprintf("args load error: %s", err.Error()), nil)
+	}
+
+	code, msg := injector.ProcessInject(ctx, i)
+	if code == errutil.GuardrailErr {
+		return getExperimentInjectPostResponse(ctx, errutil.GuardrailErr, fmt.Sprintf("injector error: %s", msg), nil)
+	} else if code != errutil.NoErr {
+		return getExperimentInjectPostResponse(ctx, errutil.InjectErr, fmt.Sprintf("injector error: %s", msg), nil)
+	}
+
+	exp, err := i.OptionToExp(i.GetArgs(), i.GetRuntime())
+	if err != nil {
+		return getExperimentInjectPostResponse(ctx, errutil.NoErr, fmt.Sprintf("inject success but get exp info error: %s", err.Error()), nil)
+	}
+
+	return getExperimentInjectPostResponse(ctx, errutil.NoErr, "success", exp)
+}
+
 func getExperimentInjectPostResponse(ctx context.Context, code int, msg string, exp *storage.Experiment) *model.InjectResponse {
 	var re = &model.InjectResponse{
 		Code:    code,
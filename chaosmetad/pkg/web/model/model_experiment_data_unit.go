@@ -30,4 +30,13 @@ type ExperimentDataUnit struct {
 	UpdateTime       string `json:"update_time,omitempty"`
 	ContainerId      string `json:"container_id,omitempty"`
 	ContainerRuntime string `json:"container_runtime,omitempty"`
+	// guardrail
+	GuardrailOverride  bool   `json:"guardrail_override,omitempty"`
+	GuardrailViolation string `json:"guardrail_violation,omitempty"`
+	// intermittent config and cycle state
+	Intermittent string `json:"intermittent,omitempty"`
+	// live status reported by injector, only returned if queried by uid
+	Detail string `json:"detail,omitempty"`
+	// audit trail, only returned if required
+	Audits []ExperimentAuditUnit `json:"audits,omitempty"`
 }
@@ -26,4 +26,11 @@ type InjectRequest struct {
 	ContainerRuntime string `json:"container_runtime"`
 	TraceId          string `json:"trace_id"`
 	Uid              string `json:"uid"`
+	// GuardrailOverride allow to attack the resources protected by guardrail
+	GuardrailOverride bool `json:"guardrail_override,omitempty"`
+	// intermittent mode, cycle between inject and recover
+	On          string  `json:"on,omitempty"`
+	Off         string  `json:"off,omitempty"`
+	OnOffJitter string  `json:"on_off_jitter,omitempty"`
+	Probability float64 `json:"probability,omitempty"`
 }
@@ -26,5 +26,6 @@ type QueryRequest struct {
 	ContainerRuntime string `json:"container_runtime,omitempty"`
 	Offset           int32  `json:"offset,omitempty"`
 	Limit            int32  `json:"limit,omitempty"`
+	Audit            bool   `json:"audit,omitempty"`
 	TraceId          string `json:"trace_id,omitempty"`
 }
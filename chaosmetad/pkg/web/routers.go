@@ -46,6 +46,7 @@ func NewRouter(ctx context.Context, isPprof bool) *mux.Router {
 	for _, route := range routes {
 		var handler http.Handler
 		handler = route.HandlerFunc
+		handler = Auth(ctx, handler, route.Name)
 		handler = Logger(ctx, handler, route.Name)
 
 		router.
@@ -91,6 +92,27 @@ var routes = Routes{
 		handler.ExperimentRecoverPost,
 	},
 
+	Route{
+		"ExperimentWatchPost",
+		strings.ToUpper("Post"),
+		"/v1/experiment/watch",
+		handler.ExperimentWatchPost,
+	},
+
+	Route{
+		"ExperimentWatchGet",
+		strings.ToUpper("Get"),
+		"/v1/experiment/watch",
+		handler.ExperimentWatchGet,
+	},
+
+	Route{
+		"DescribeGet",
+		strings.ToUpper("Get"),
+		"/v1/describe",
+		handler.DescribeGet,
+	},
+
 	Route{
 		"VersionGet",
 		strings.ToUpper("Get"),
@@ -0,0 +1,255 @@
+/*
+ * Copyright 2022-2023 Chaos Meta Authors.
+ *
+ * Licensed under the Apache License, Version 2.0 (the "License");
+ * you may not use this file except in compliance with the License.
+ * You may obtain a copy of the License at
+ *
+ *     http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing, software
+ * distributed under the License is distributed on an "AS IS" BASIS,
+ * WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+ * See the License for the specific language governing permissions and
+ * limitations under the License.
+ */
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/traas-stack/chaosmeta/chaosmetad/tools/common"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+const (
+	roleSend = "send"
+	roleSink = "sink"
+
+	statInterval   = time.Second
+	redialInterval = time.Second
+)
+
+type stat struct {
+	Role      string `json:"role"`
+	Protocol  string `json:"protocol"`
+	Address   string `json:"address"`
+	StartTime string `json:"start_time"`
+	UpdateAt  string `json:"update_at"`
+	Bytes     int64  `json:"bytes"`
+	Packets   int64  `json:"packets"`
+	Errors    int64  `json:"errors"`
+	Conns     int64  `json:"conns"`
+	// RateBps achieved bits per second of the last stat interval
+	RateBps int64 `json:"rate_bps"`
+	// AvgRateBps achieved bits per second since start
+	AvgRateBps int64 `json:"avg_rate_bps"`
+}
+
+type counter struct {
+	bytes, packets, errors, conns int64
+}
+
+// [uid] send [protocol] [address] [rate(bit/s), 0 means unlimited] [size] [conn] [timeout] [stat file]
+// [uid] sink [protocol] [port] [timeout] [stat file]
+func main() {
+	args := os.Args
+	if len(args) < 3 {
+		common.ExitWithErr("must provide args: uid、role")
+	}
+
+	switch args[2] {
+	case roleSend:
+		send(args)
+	case roleSink:
+		sink(args)
+	default:
+		common.ExitWithErr(fmt.Sprintf("role only support: %s、%s", roleSend, roleSink))
+	}
+}
+
+func send(args []string) {
+	if len(args) < 10 {
+		common.ExitWithErr("must provide 9 args: uid、role、protocol、address、rate、size、conn、timeout、stat file")
+	}
+
+	proto, address, statFile := getProtocol(args[3]), args[4], args[9]
+	rate, size, conn, timeout := getInt(args[5], "rate", 0), getInt(args[6], "size", 1), getInt(args[7], "conn", 1), getInt(args[8], "timeout", 0)
+
+	c, conns := &counter{}, make([]net.Conn, conn)
+	for i := range conns {
+		var err error
+		if conns[i], err = net.Dial(proto, address); err != nil {
+			common.ExitWithErr(fmt.Sprintf("%s dial %s error: %s", proto, address, err.Error()))
+		}
+	}
+	c.conns = int64(conn)
+
+	// the rate is shared by all connections
+	bytesPerConn := float64(rate) / 8 / float64(conn)
+	for i := range conns {
+		go sendLoop(conns[i], proto, address, size, bytesPerConn, c)
+	}
+
+	fmt.Println("[success]inject success")
+
+	go writeStat(statFile, &stat{Role: roleSend, Protocol: proto, Address: address}, c)
+	common.SleepWait(timeout)
+}
+
+// sendLoop write packets of the size with the pace of rate, reconnect if the tcp connection is broken
+func sendLoop(conn net.Conn, proto, address string, size int, bytesPerSec float64, c *counter) {
+	buf, start, sent := make([]byte, size), time.Now(), int64(0)
+	for i := range buf {
+		buf[i] = byte(i)
+	}
+
+	for {
+		if bytesPerSec > 0 {
+			if ahead := time.Duration(float64(sent)/bytesPerSec*float64(time.Second)) - time.Since(start); ahead > 0 {
+				time.Sleep(ahead)
+			}
+		}
+
+		l, err := conn.Write(buf)
+		sent += int64(size)
+		if err == nil {
+			atomic.AddInt64(&c.bytes, int64(l))
+			atomic.AddInt64(&c.packets, 1)
+			continue
+		}
+
+		atomic.AddInt64(&c.errors, 1)
+		if proto == "udp4" || proto == "udp6" {
+			// the ICMP unreachable of the last datagram is reported by this write, keep sending
+			continue
+		}
+
+		_ = conn.Close()
+		atomic.AddInt64(&c.conns, -1)
+		for {
+			time.Sleep(redialInterval)
+			if conn, err = net.Dial(proto, address); err == nil {
+				break
+			}
+			atomic.AddInt64(&c.errors, 1)
+		}
+		atomic.AddInt64(&c.conns, 1)
+		start, sent = time.Now(), 0
+	}
+}
+
+func sink(args []string) {
+	if len(args) < 7 {
+		common.ExitWithErr("must provide 6 args: uid、role、protocol、port、timeout、stat file")
+	}
+
+	proto, port, timeout, statFile := getProtocol(args[3]), getInt(args[4], "port", 1), getInt(args[5], "timeout", 0), args[6]
+	c, address := &counter{}, fmt.Sprintf(":%d", port)
+
+	if proto == "tcp4" || proto == "tcp6" {
+		l, err := net.Listen(proto, address)
+		if err != nil {
+			common.ExitWithErr(fmt.Sprintf("%s listen on %d error: %s", proto, port, err.Error()))
+		}
+		go acceptLoop(l, c)
+	} else {
+		conn, err := net.ListenUDP(proto, &net.UDPAddr{Port: port})
+		if err != nil {
+			common.ExitWithErr(fmt.Sprintf("%s listen on %d error: %s", proto, port, err.Error()))
+		}
+		go readLoop(conn, c)
+	}
+
+	fmt.Println("[success]inject success")
+
+	go writeStat(statFile, &stat{Role: roleSink, Protocol: proto, Address: address}, c)
+	common.SleepWait(timeout)
+}
+
+func acceptLoop(l net.Listener, c *counter) {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			atomic.AddInt64(&c.errors, 1)
+			time.Sleep(redialInterval)
+			continue
+		}
+
+		atomic.AddInt64(&c.conns, 1)
+		go func() {
+			readLoop(conn, c)
+			_ = conn.Close()
+			atomic.AddInt64(&c.conns, -1)
+		}()
+	}
+}
+
+// readLoop discard the received data, a read is a packet for udp
+func readLoop(conn io.Reader, c *counter) {
+	buf := make([]byte, 64*1024)
+	for {
+		l, err := conn.Read(buf)
+		if l > 0 {
+			atomic.AddInt64(&c.bytes, int64(l))
+			atomic.AddInt64(&c.packets, 1)
+		}
+
+		if err != nil {
+			if _, ok := conn.(*net.UDPConn); ok {
+				atomic.AddInt64(&c.errors, 1)
+				continue
+			}
+			return
+		}
+	}
+}
+
+// writeStat refresh the stat file every interval, it is read by the query of chaosmetad
+func writeStat(statFile string, s *stat, c *counter) {
+	start, lastBytes := time.Now(), int64(0)
+	s.StartTime = start.Format(time.RFC3339)
+	ticker := time.NewTicker(statInterval)
+	for now := range ticker.C {
+		s.Bytes, s.Packets = atomic.LoadInt64(&c.bytes), atomic.LoadInt64(&c.packets)
+		s.Errors, s.Conns = atomic.LoadInt64(&c.errors), atomic.LoadInt64(&c.conns)
+		s.RateBps = int64(float64(s.Bytes-lastBytes) * 8 / statInterval.Seconds())
+		s.AvgRateBps = int64(float64(s.Bytes) * 8 / now.Sub(start).Seconds())
+		s.UpdateAt = now.Format(time.RFC3339)
+		lastBytes = s.Bytes
+
+		data, _ := json.Marshal(s)
+		tmpFile := statFile + ".tmp"
+		if err := os.WriteFile(tmpFile, data, 0644); err == nil {
+			_ = os.Rename(tmpFile, statFile)
+		}
+	}
+}
+
+func getProtocol(proto string) string {
+	switch proto {
+	case "tcp":
+		return "tcp4"
+	case "udp":
+		return "udp4"
+	case "tcp6", "udp6":
+		return proto
+	}
+
+	common.ExitWithErr("proto only support: udp、tcp、udp6、tcp6")
+	return ""
+}
+
+func getInt(value, name string, min int) int {
+	re, err := strconv.Atoi(value)
+	if err != nil || re < min {
+		common.ExitWithErr(fmt.Sprintf("%s[%s] is not a valid int, must not less than %d", name, value, min))
+	}
+
+	return re
+}